@@ -6,16 +6,20 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/vitaly-stepin/agile_party/internal/adapters/config"
 	"github.com/vitaly-stepin/agile_party/internal/adapters/memory"
 	"github.com/vitaly-stepin/agile_party/internal/adapters/postgres"
+	"github.com/vitaly-stepin/agile_party/internal/adapters/tracing"
 	"github.com/vitaly-stepin/agile_party/internal/application"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 	"github.com/vitaly-stepin/agile_party/internal/interfaces/http/rest"
 	ws "github.com/vitaly-stepin/agile_party/internal/interfaces/http/websocket"
 	"github.com/vitaly-stepin/agile_party/internal/interfaces/middleware"
+	"github.com/vitaly-stepin/agile_party/internal/interfaces/ratelimit"
 )
 
 func main() {
@@ -25,6 +29,15 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	if cfg.Tracing.Enabled {
+		log.Printf("✅ Tracing enabled, exporting to %s", cfg.Tracing.OTLPEndpoint)
+	}
+
 	// Initialize database
 	db, err := postgres.NewDB(&cfg.Database)
 	if err != nil {
@@ -40,6 +53,8 @@ func main() {
 
 	roomRepo := postgres.NewRoomRepository(db)
 	taskRepo := postgres.NewTaskRepository(db)
+	historyRepo := postgres.NewEstimationHistoryRepository(db)
+	commentRepo := postgres.NewTaskCommentRepository(db)
 	stateManager := memory.NewRoomStateManager(memory.CleanupConfig{
 		CleanupInterval: cfg.Memory.CleanupInterval,
 		RoomTTL:         cfg.Memory.RoomTTL,
@@ -48,16 +63,34 @@ func main() {
 
 	roomService := application.NewRoomService(roomRepo, stateManager)
 	userService := application.NewUserService(roomRepo, stateManager)
-	votingService := application.NewVotingService(roomRepo, stateManager)
-	taskService := application.NewTaskService(taskRepo, roomRepo)
+	votingService := application.NewVotingService(roomRepo, stateManager, taskRepo)
+	taskService := application.NewTaskService(taskRepo, roomRepo, historyRepo)
+	commentService := application.NewCommentService(commentRepo, taskRepo)
+	undoService := application.NewUndoService(roomRepo, taskRepo, historyRepo, stateManager)
+
+	roomLimits := room.RoomLimits{
+		MaxTasks:        cfg.Room.MaxTasks,
+		MaxParticipants: cfg.Room.MaxParticipants,
+	}
+	userService.SetLimits(roomLimits)
+	taskService.SetLimits(roomLimits)
 	log.Println("✅ Application services initialized")
 
+	go purgeDeletedTasks(taskService, cfg.Room.DeletedTaskPurgeInterval, cfg.Room.DeletedTaskRetention)
+
 	ws_hub := ws.NewHub()
 	go ws_hub.Run()
 	log.Println("✅ WebSocket hub started")
 
-	roomHandler := rest.NewRoomHandler(roomService, userService, votingService)
-	wsHandler := ws.NewHandler(ws_hub, roomService, userService, votingService, taskService)
+	wsHandler := ws.NewHandler(ws_hub, roomService, userService, votingService, taskService, commentService, undoService, ws.NewEventLimiter(cfg.RateLimit))
+	roomHandler := rest.NewRoomHandler(roomService, wsHandler)
+	taskHandler := rest.NewTaskHandler(taskService, commentService, wsHandler)
+
+	healthHandler := rest.NewHealthHandler(cfg.Server.HealthTimeout)
+	healthHandler.AddLivenessCheck("hub", ws_hub.Ping)
+	healthHandler.AddReadinessCheck("postgres", db.PingContext)
+	healthHandler.AddReadinessCheck("migrations", db.CheckMigrations)
+	healthHandler.AddReadinessCheck("hub", ws_hub.Ping)
 	log.Println("✅ Handlers initialized")
 
 	app := fiber.New(fiber.Config{
@@ -70,11 +103,14 @@ func main() {
 
 	app.Use(middleware.Recovery())
 	app.Use(middleware.Logger())
+	app.Use(middleware.Tracing())
 	app.Use(middleware.CORS())
 
 	app.Get("/api/health", roomHandler.Health)
+	app.Get("/livez", healthHandler.Livez)
+	app.Get("/readyz", healthHandler.Readyz)
 
-	api := app.Group("/api")
+	api := app.Group("/api", middleware.RateLimit(ratelimit.NewKeyedLimiter(cfg.RateLimit.RESTPerSecond, cfg.RateLimit.RESTBurst)))
 
 	api.Post("/rooms", roomHandler.NewRoom)
 	api.Get("/rooms/:id", roomHandler.GetRoom)
@@ -88,6 +124,20 @@ func main() {
 	api.Post("/rooms/:id/reveal", roomHandler.RevealVotes)
 	api.Post("/rooms/:id/clear", roomHandler.ClearVotes)
 
+	api.Get("/rooms/:id/tasks", taskHandler.ListTasks)
+	api.Get("/rooms/:id/tasks/tree", taskHandler.GetTaskTree)
+	api.Get("/rooms/:id/tasks/topological-order", taskHandler.ProposeTaskOrder)
+	api.Put("/rooms/:id/task-filter", roomHandler.SetTaskFilter)
+	api.Patch("/rooms/:id/tasks/:taskId", taskHandler.UpdateTask)
+	api.Get("/rooms/:id/tasks/:taskId/estimations", taskHandler.GetEstimationHistory)
+	api.Get("/rooms/:id/tasks/:taskId/comments", taskHandler.ListComments)
+
+	api.Get("/rooms/:id/tasks/:taskId/criteria", taskHandler.GetCriteria)
+	api.Post("/rooms/:id/tasks/:taskId/criteria", taskHandler.AddCriterion)
+	api.Put("/rooms/:id/tasks/:taskId/criteria/order", taskHandler.ReorderCriteria)
+	api.Patch("/rooms/:id/tasks/:taskId/criteria/:criterionId", taskHandler.UpdateCriterion)
+	api.Delete("/rooms/:id/tasks/:taskId/criteria/:criterionId", taskHandler.DeleteCriterion)
+
 	app.Get("/ws/rooms/:id", func(c *fiber.Ctx) error {
 		if websocket.IsWebSocketUpgrade(c) {
 			return wsHandler.HandleConnection(c)
@@ -116,5 +166,30 @@ func main() {
 	if err := app.ShutdownWithContext(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
 	log.Println("✅ Server stopped gracefully")
 }
+
+// purgeDeletedTasks periodically removes the tasks deleted longer than retention ago;
+// until then their deletion can be undone. A non-positive interval turns purging off.
+func purgeDeletedTasks(taskService *application.TaskService, interval, retention time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := taskService.PurgeDeletedTasks(context.Background(), retention)
+		if err != nil {
+			log.Printf("Warning: failed to purge deleted tasks: %v", err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("Purged %d deleted tasks", purged)
+		}
+	}
+}
@@ -8,9 +8,12 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Memory   MemoryConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Memory    MemoryConfig
+	Tracing   TracingConfig
+	RateLimit RateLimitConfig
+	Room      RoomConfig
 }
 
 type ServerConfig struct {
@@ -18,6 +21,7 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	HealthTimeout   time.Duration
 }
 
 type DatabaseConfig struct {
@@ -37,6 +41,33 @@ type MemoryConfig struct {
 	RoomTTL         time.Duration
 }
 
+type RateLimitConfig struct {
+	WsConnPerSecond float64 // events per second for a single WebSocket connection
+	WsConnBurst     int
+	WsIPPerSecond   float64 // events per second across all WebSocket connections from one IP
+	WsIPBurst       int
+	RESTPerSecond   float64 // requests per second per IP on the REST API
+	RESTBurst       int
+	MaxViolations   int // rate-limited events tolerated within ViolationWindow before disconnecting
+	ViolationWindow time.Duration
+}
+
+type RoomConfig struct {
+	MaxTasks        int // 0 means unlimited
+	MaxParticipants int // 0 means unlimited
+
+	DeletedTaskRetention     time.Duration // how long a deleted task is kept so its deletion can be undone
+	DeletedTaskPurgeInterval time.Duration
+}
+
+type TracingConfig struct {
+	Enabled      bool
+	ServiceName  string
+	OTLPEndpoint string // host:port of the OTLP/HTTP collector
+	OTLPInsecure bool
+	SampleRatio  float64
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
@@ -44,6 +75,7 @@ func Load() (*Config, error) {
 			ReadTimeout:     getDurationEnv("SERVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout:    getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Second),
 			ShutdownTimeout: getDurationEnv("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			HealthTimeout:   getDurationEnv("SERVER_HEALTH_TIMEOUT", 2*time.Second),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -60,6 +92,30 @@ func Load() (*Config, error) {
 			CleanupInterval: getDurationEnv("MEMORY_CLEANUP_INTERVAL", 10*time.Minute),
 			RoomTTL:         getDurationEnv("MEMORY_ROOM_TTL", 24*time.Hour),
 		},
+		RateLimit: RateLimitConfig{
+			WsConnPerSecond: getFloatEnv("RATE_LIMIT_WS_CONN_PER_SECOND", 5),
+			WsConnBurst:     getIntEnv("RATE_LIMIT_WS_CONN_BURST", 10),
+			WsIPPerSecond:   getFloatEnv("RATE_LIMIT_WS_IP_PER_SECOND", 20),
+			WsIPBurst:       getIntEnv("RATE_LIMIT_WS_IP_BURST", 40),
+			RESTPerSecond:   getFloatEnv("RATE_LIMIT_REST_PER_SECOND", 10),
+			RESTBurst:       getIntEnv("RATE_LIMIT_REST_BURST", 20),
+			MaxViolations:   getIntEnv("RATE_LIMIT_MAX_VIOLATIONS", 20),
+			ViolationWindow: getDurationEnv("RATE_LIMIT_VIOLATION_WINDOW", time.Minute),
+		},
+		Room: RoomConfig{
+			MaxTasks:        getIntEnv("ROOM_MAX_TASKS", 200),
+			MaxParticipants: getIntEnv("ROOM_MAX_PARTICIPANTS", 50),
+
+			DeletedTaskRetention:     getDurationEnv("ROOM_DELETED_TASK_RETENTION", 7*24*time.Hour),
+			DeletedTaskPurgeInterval: getDurationEnv("ROOM_DELETED_TASK_PURGE_INTERVAL", time.Hour),
+		},
+		Tracing: TracingConfig{
+			Enabled:      getBoolEnv("TRACING_ENABLED", false),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "agile-party-api"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getBoolEnv("TRACING_OTLP_INSECURE", true),
+			SampleRatio:  getFloatEnv("TRACING_SAMPLE_RATIO", 1.0),
+		},
 	}
 
 	if cfg.Database.Password == "" {
@@ -92,6 +148,24 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
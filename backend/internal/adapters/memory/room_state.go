@@ -2,6 +2,8 @@ package memory
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -13,12 +15,47 @@ type liveRoom struct {
 	roomID          string
 	users           map[string]*room.User
 	votes           map[string]string
+	dimensionVotes  map[string]map[string]string
+	threePointVotes map[string]room.ThreePointEstimate
+	comments        map[string]string
+	voteChanges     []room.VoteChange
+	hands           []room.RaisedHand // queue, first raised first
 	isRevealed      bool
 	taskDescription string
 	activeTaskID    string
+	facilitatorID   string
+	arrivals        []string          // IDs of the users in the room, in the order they joined
+	undo            []ports.UndoEntry // latest change last
 	lastAccess      time.Time
 }
 
+// maxUndoEntries bounds each room's undo stack, older changes can no longer be undone
+const maxUndoEntries = 10
+
+// setVote stores a vote, recording it as a change when it alters a revealed round
+// setVote stores the user's vote with its comment; an empty comment removes the previous one
+func (r *liveRoom) setVote(userID, voteValue, comment string) {
+	if previous, voted := r.votes[userID]; r.isRevealed && (!voted || previous != voteValue) {
+		r.voteChanges = append(r.voteChanges, room.VoteChange{Voter: userID, From: previous, To: voteValue})
+	}
+	r.votes[userID] = voteValue
+
+	if comment == "" {
+		delete(r.comments, userID)
+	} else {
+		r.comments[userID] = comment
+	}
+}
+
+func (r *liveRoom) lowerHand(userID string) {
+	for i, hand := range r.hands {
+		if hand.UserID == userID {
+			r.hands = append(r.hands[:i], r.hands[i+1:]...)
+			return
+		}
+	}
+}
+
 type RoomStateManager struct {
 	mu    sync.RWMutex
 	rooms map[string]*liveRoom // rename to activeRooms + update on first user join
@@ -53,6 +90,9 @@ func (m *RoomStateManager) NewRoom(roomID string) error {
 		roomID:          roomID,
 		users:           make(map[string]*room.User),
 		votes:           make(map[string]string),
+		dimensionVotes:  make(map[string]map[string]string),
+		threePointVotes: make(map[string]room.ThreePointEstimate),
+		comments:        make(map[string]string),
 		isRevealed:      false,
 		taskDescription: "",
 		activeTaskID:    "",
@@ -85,13 +125,51 @@ func (m *RoomStateManager) GetRoomState(roomID string) (*ports.LiveRoomState, er
 		votesCopy[id] = vote
 	}
 
+	dimensionVotesCopy := make(map[string]map[string]string, len(r.dimensionVotes))
+	for id, values := range r.dimensionVotes {
+		valuesCopy := make(map[string]string, len(values))
+		for key, value := range values {
+			valuesCopy[key] = value
+		}
+		dimensionVotesCopy[id] = valuesCopy
+	}
+
+	threePointVotesCopy := make(map[string]room.ThreePointEstimate, len(r.threePointVotes))
+	for id, estimate := range r.threePointVotes {
+		threePointVotesCopy[id] = estimate
+	}
+
+	voteChangesCopy := make([]room.VoteChange, len(r.voteChanges))
+	copy(voteChangesCopy, r.voteChanges)
+
+	handsCopy := make([]room.RaisedHand, len(r.hands))
+	copy(handsCopy, r.hands)
+
+	commentsCopy := make(map[string]string, len(r.comments))
+	for id, comment := range r.comments {
+		commentsCopy[id] = comment
+	}
+
+	// Entries are never changed once pushed, sharing the latest one is safe
+	var lastChange *ports.UndoEntry
+	if len(r.undo) > 0 {
+		lastChange = &r.undo[len(r.undo)-1]
+	}
+
 	return &ports.LiveRoomState{
 		RoomID:          r.roomID,
 		Users:           usersCopy,
 		Votes:           votesCopy,
+		DimensionVotes:  dimensionVotesCopy,
+		ThreePointVotes: threePointVotesCopy,
+		Comments:        commentsCopy,
+		VoteChanges:     voteChangesCopy,
+		RaisedHands:     handsCopy,
 		IsRevealed:      r.isRevealed,
 		TaskDescription: r.taskDescription,
 		ActiveTaskID:    r.activeTaskID,
+		FacilitatorID:   r.facilitatorID,
+		LastChange:      lastChange,
 	}, nil
 }
 
@@ -115,7 +193,7 @@ func (m *RoomStateManager) DeleteRoom(roomID string) error {
 	return nil
 }
 
-func (m *RoomStateManager) AddUser(roomID string, user *room.User) error {
+func (m *RoomStateManager) AddUser(roomID string, user *room.User, limits room.RoomLimits) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -127,6 +205,9 @@ func (m *RoomStateManager) AddUser(roomID string, user *room.User) error {
 	if _, userExists := r.users[user.ID]; userExists {
 		return fmt.Errorf("user already exists in room: %s", user.ID)
 	}
+	if err := limits.CheckParticipantCount(len(r.users)); err != nil {
+		return err
+	}
 
 	// preserve user vote status if they have already voted (reconnection scenario)
 	if _, hasVote := r.votes[user.ID]; hasVote {
@@ -134,6 +215,10 @@ func (m *RoomStateManager) AddUser(roomID string, user *room.User) error {
 	}
 
 	r.users[user.ID] = user
+	r.arrivals = append(r.arrivals, user.ID)
+	if r.facilitatorID == "" {
+		r.facilitatorID = user.ID
+	}
 	r.lastAccess = time.Now()
 
 	return nil
@@ -154,6 +239,18 @@ func (m *RoomStateManager) RemoveUser(roomID, userID string) error {
 
 	delete(r.users, userID)
 	delete(r.votes, userID)
+	delete(r.dimensionVotes, userID)
+	delete(r.threePointVotes, userID)
+	delete(r.comments, userID)
+	r.lowerHand(userID)
+	r.arrivals = slices.DeleteFunc(r.arrivals, func(id string) bool { return id == userID })
+	// The facilitator role passes to whoever has been in the room the longest
+	if r.facilitatorID == userID {
+		r.facilitatorID = ""
+		if len(r.arrivals) > 0 {
+			r.facilitatorID = r.arrivals[0]
+		}
+	}
 	r.lastAccess = time.Now()
 
 	return nil
@@ -209,7 +306,47 @@ func (m *RoomStateManager) GetUserCount(roomID string) (int, error) {
 	return len(r.users), nil
 }
 
-func (m *RoomStateManager) SubmitVote(roomID, userID, voteValue string) error {
+func (m *RoomStateManager) RaiseHand(roomID, userID string, signal room.HandSignal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+
+	if _, userExists := r.users[userID]; !userExists {
+		return fmt.Errorf("user not found in room: %s", userID)
+	}
+
+	r.lastAccess = time.Now()
+	for i := range r.hands {
+		if r.hands[i].UserID == userID {
+			r.hands[i].Signal = signal
+			return nil
+		}
+	}
+	r.hands = append(r.hands, room.RaisedHand{UserID: userID, Signal: signal, RaisedAt: r.lastAccess})
+
+	return nil
+}
+
+func (m *RoomStateManager) LowerHand(roomID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+
+	r.lowerHand(userID)
+	r.lastAccess = time.Now()
+
+	return nil
+}
+
+func (m *RoomStateManager) SubmitVote(roomID, userID, voteValue, comment string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -223,7 +360,56 @@ func (m *RoomStateManager) SubmitVote(roomID, userID, voteValue string) error {
 		return fmt.Errorf("user not found in room: %s", userID)
 	} // can we refactor this to avoid code duplication?
 
-	r.votes[userID] = voteValue
+	r.setVote(userID, voteValue, comment)
+	user.IsVoted = true
+	r.lastAccess = time.Now()
+
+	return nil
+}
+
+func (m *RoomStateManager) SubmitDimensionVote(roomID, userID, voteValue string, dimensionValues map[string]string, comment string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+
+	user, userExists := r.users[userID]
+	if !userExists {
+		return fmt.Errorf("user not found in room: %s", userID)
+	}
+
+	values := make(map[string]string, len(dimensionValues))
+	for key, value := range dimensionValues {
+		values[key] = value
+	}
+
+	r.setVote(userID, voteValue, comment)
+	r.dimensionVotes[userID] = values
+	user.IsVoted = true
+	r.lastAccess = time.Now()
+
+	return nil
+}
+
+func (m *RoomStateManager) SubmitThreePointVote(roomID, userID, voteValue string, estimate room.ThreePointEstimate, comment string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+
+	user, userExists := r.users[userID]
+	if !userExists {
+		return fmt.Errorf("user not found in room: %s", userID)
+	}
+
+	r.setVote(userID, voteValue, comment)
+	r.threePointVotes[userID] = estimate
 	user.IsVoted = true
 	r.lastAccess = time.Now()
 
@@ -255,6 +441,11 @@ func (m *RoomStateManager) ClearVotes(roomID string) error {
 	}
 
 	r.votes = make(map[string]string)
+	r.dimensionVotes = make(map[string]map[string]string)
+	r.threePointVotes = make(map[string]room.ThreePointEstimate)
+	r.comments = make(map[string]string)
+	r.voteChanges = nil
+	r.hands = nil
 	r.isRevealed = false
 	r.activeTaskID = ""
 
@@ -348,3 +539,78 @@ func (m *RoomStateManager) Stats() map[string]interface{} {
 		"room_ttl":         m.cfg.RoomTTL.String(),
 	}
 }
+
+func (m *RoomStateManager) PushUndo(roomID string, entry ports.UndoEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+
+	r.undo = append(r.undo, entry)
+	if len(r.undo) > maxUndoEntries {
+		r.undo = slices.Clone(r.undo[len(r.undo)-maxUndoEntries:])
+	}
+	r.lastAccess = time.Now()
+
+	return nil
+}
+
+func (m *RoomStateManager) PopUndo(roomID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+	if len(r.undo) == 0 {
+		return room.ErrNothingToUndo
+	}
+
+	r.undo = r.undo[:len(r.undo)-1]
+	r.lastAccess = time.Now()
+
+	return nil
+}
+
+func (m *RoomStateManager) RestoreRound(roomID string, round *ports.LiveRoomState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.rooms[roomID]
+	if !exists {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+
+	r.votes = cloneMap(round.Votes)
+	r.dimensionVotes = make(map[string]map[string]string, len(round.DimensionVotes))
+	for id, values := range round.DimensionVotes {
+		r.dimensionVotes[id] = maps.Clone(values)
+	}
+	r.threePointVotes = cloneMap(round.ThreePointVotes)
+	r.comments = cloneMap(round.Comments)
+	r.voteChanges = slices.Clone(round.VoteChanges)
+	r.hands = slices.Clone(round.RaisedHands)
+	r.isRevealed = round.IsRevealed
+	r.activeTaskID = round.ActiveTaskID
+
+	for id, user := range r.users {
+		_, voted := r.votes[id]
+		user.IsVoted = voted
+	}
+
+	r.lastAccess = time.Now()
+
+	return nil
+}
+
+// cloneMap copies m, giving an empty map for nil so the room can keep writing to it
+func cloneMap[M ~map[K]V, K comparable, V any](m M) M {
+	if m == nil {
+		return M{}
+	}
+	return maps.Clone(m)
+}
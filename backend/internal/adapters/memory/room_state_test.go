@@ -1,10 +1,14 @@
 package memory
 
 import (
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
@@ -120,7 +124,7 @@ func TestRoomStateManager_AddUser(t *testing.T) {
 	}
 
 	// Test AddUser
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
@@ -135,7 +139,7 @@ func TestRoomStateManager_AddUser(t *testing.T) {
 	}
 
 	// Test duplicate user
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err == nil {
 		t.Error("Expected error when adding duplicate user")
 	}
@@ -157,13 +161,13 @@ func TestRoomStateManager_RemoveUser(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
 
 	// Add a vote for the user
-	err = manager.SubmitVote(roomID, user.ID, "5")
+	err = manager.SubmitVote(roomID, user.ID, "5", "")
 	if err != nil {
 		t.Fatalf("Failed to submit vote: %v", err)
 	}
@@ -206,7 +210,7 @@ func TestRoomStateManager_GetUser(t *testing.T) {
 	}
 
 	originalUser, err := room.CreateUser("user1", "Alice")
-	err = manager.AddUser(roomID, originalUser)
+	err = manager.AddUser(roomID, originalUser, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
@@ -241,7 +245,7 @@ func TestRoomStateManager_UpdateUser(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
@@ -285,13 +289,13 @@ func TestRoomStateManager_SubmitVote(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
 
 	// Test SubmitVote
-	err = manager.SubmitVote(roomID, user.ID, "5")
+	err = manager.SubmitVote(roomID, user.ID, "5", "")
 	if err != nil {
 		t.Fatalf("Failed to submit vote: %v", err)
 	}
@@ -316,6 +320,228 @@ func TestRoomStateManager_SubmitVote(t *testing.T) {
 	}
 }
 
+func TestRoomStateManager_SubmitDimensionVote(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	user, _ := room.CreateUser("user1", "Alice")
+	if err := manager.AddUser(roomID, user, room.RoomLimits{}); err != nil {
+		t.Fatalf("Failed to add user: %v", err)
+	}
+
+	values := map[string]string{"complexity": "3", "effort": "5"}
+	if err := manager.SubmitDimensionVote(roomID, user.ID, "8", values, ""); err != nil {
+		t.Fatalf("Failed to submit dimension vote: %v", err)
+	}
+	values["effort"] = "13" // the manager must keep its own copy
+
+	state, err := manager.GetRoomState(roomID)
+	if err != nil {
+		t.Fatalf("Failed to get room state: %v", err)
+	}
+	if state.Votes[user.ID] != "8" {
+		t.Errorf("Expected combined vote '8', got %s", state.Votes[user.ID])
+	}
+	if state.DimensionVotes[user.ID]["effort"] != "5" {
+		t.Errorf("Expected effort vote '5', got %s", state.DimensionVotes[user.ID]["effort"])
+	}
+
+	if err := manager.ClearVotes(roomID); err != nil {
+		t.Fatalf("Failed to clear votes: %v", err)
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if len(state.DimensionVotes) != 0 {
+		t.Error("Dimension votes should be cleared")
+	}
+}
+
+func TestRoomStateManager_SubmitThreePointVote(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	user, _ := room.CreateUser("user1", "Alice")
+	if err := manager.AddUser(roomID, user, room.RoomLimits{}); err != nil {
+		t.Fatalf("Failed to add user: %v", err)
+	}
+
+	estimate := room.ThreePointEstimate{Optimistic: 2, MostLikely: 3, Pessimistic: 8}
+	if err := manager.SubmitThreePointVote(roomID, user.ID, "3", estimate, ""); err != nil {
+		t.Fatalf("Failed to submit three-point vote: %v", err)
+	}
+
+	state, _ := manager.GetRoomState(roomID)
+	if state.Votes[user.ID] != "3" {
+		t.Errorf("Expected vote '3', got %s", state.Votes[user.ID])
+	}
+	if state.ThreePointVotes[user.ID] != estimate {
+		t.Errorf("Expected triple %+v, got %+v", estimate, state.ThreePointVotes[user.ID])
+	}
+
+	if err := manager.ClearVotes(roomID); err != nil {
+		t.Fatalf("Failed to clear votes: %v", err)
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if len(state.ThreePointVotes) != 0 {
+		t.Error("Three-point votes should be cleared")
+	}
+}
+
+func TestRoomStateManager_SubmitVoteWithComment(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	user, _ := room.CreateUser("user1", "Alice")
+	if err := manager.AddUser(roomID, user, room.RoomLimits{}); err != nil {
+		t.Fatalf("Failed to add user: %v", err)
+	}
+
+	if err := manager.SubmitVote(roomID, "ghost", "5", "why not"); err == nil {
+		t.Error("Expected error for unknown user")
+	}
+
+	if err := manager.SubmitVote(roomID, user.ID, "5", "legacy code"); err != nil {
+		t.Fatalf("Failed to submit vote: %v", err)
+	}
+	state, _ := manager.GetRoomState(roomID)
+	if state.Comments[user.ID] != "legacy code" {
+		t.Errorf("Expected comment 'legacy code', got %q", state.Comments[user.ID])
+	}
+
+	if err := manager.SubmitVote(roomID, user.ID, "8", ""); err != nil {
+		t.Fatalf("Failed to submit vote: %v", err)
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if _, ok := state.Comments[user.ID]; ok {
+		t.Error("Empty comment should remove the previous one")
+	}
+
+	manager.SubmitVote(roomID, user.ID, "8", "legacy code")
+	if err := manager.ClearVotes(roomID); err != nil {
+		t.Fatalf("Failed to clear votes: %v", err)
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if len(state.Comments) != 0 {
+		t.Error("Comments should be cleared with the votes")
+	}
+}
+
+func TestRoomStateManager_TracksVoteChangesAfterReveal(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	alice, _ := room.CreateUser("user1", "Alice")
+	bob, _ := room.CreateUser("user2", "Bob")
+	manager.AddUser(roomID, alice, room.RoomLimits{})
+	manager.AddUser(roomID, bob, room.RoomLimits{})
+
+	manager.SubmitVote(roomID, alice.ID, "3", "")
+	manager.SubmitVote(roomID, alice.ID, "5", "") // before reveal, not a change
+	manager.RevealVotes(roomID)
+
+	manager.SubmitVote(roomID, alice.ID, "5", "") // same value, not a change
+	manager.SubmitVote(roomID, alice.ID, "8", "")
+	manager.SubmitVote(roomID, bob.ID, "13", "")
+
+	state, _ := manager.GetRoomState(roomID)
+	expected := []room.VoteChange{
+		{Voter: alice.ID, From: "5", To: "8"},
+		{Voter: bob.ID, From: "", To: "13"},
+	}
+	if len(state.VoteChanges) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), state.VoteChanges)
+	}
+	for i, change := range expected {
+		if state.VoteChanges[i] != change {
+			t.Errorf("Expected change %+v, got %+v", change, state.VoteChanges[i])
+		}
+	}
+
+	if err := manager.ClearVotes(roomID); err != nil {
+		t.Fatalf("Failed to clear votes: %v", err)
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if len(state.VoteChanges) != 0 {
+		t.Error("Vote changes should be cleared with the votes")
+	}
+}
+
+func TestRoomStateManager_HandQueue(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	for _, id := range []string{"user1", "user2", "user3"} {
+		user, _ := room.CreateUser(id, id)
+		manager.AddUser(roomID, user, room.RoomLimits{})
+	}
+
+	if err := manager.RaiseHand(roomID, "ghost", room.SignalQuestion); err == nil {
+		t.Error("Expected error for unknown user")
+	}
+
+	manager.RaiseHand(roomID, "user2", room.SignalQuestion)
+	manager.RaiseHand(roomID, "user1", room.SignalQuestion)
+	manager.RaiseHand(roomID, "user3", room.SignalSplit)
+	manager.RaiseHand(roomID, "user2", room.SignalSplit) // keeps its place
+
+	state, _ := manager.GetRoomState(roomID)
+	if len(state.RaisedHands) != 3 {
+		t.Fatalf("Expected 3 raised hands, got %d", len(state.RaisedHands))
+	}
+	if first := state.RaisedHands[0]; first.UserID != "user2" || first.Signal != room.SignalSplit {
+		t.Errorf("Expected user2 first with split, got %+v", first)
+	}
+
+	manager.LowerHand(roomID, "user2")
+	manager.RemoveUser(roomID, "user1")
+	state, _ = manager.GetRoomState(roomID)
+	if len(state.RaisedHands) != 1 || state.RaisedHands[0].UserID != "user3" {
+		t.Errorf("Expected only user3 left in the queue, got %+v", state.RaisedHands)
+	}
+
+	if err := manager.ClearVotes(roomID); err != nil {
+		t.Fatalf("Failed to clear votes: %v", err)
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if len(state.RaisedHands) != 0 {
+		t.Error("Hands should be lowered when the round is reset")
+	}
+}
+
 func TestRoomStateManager_RevealVotes(t *testing.T) {
 	manager := NewRoomStateManager(CleanupConfig{
 		CleanupInterval: 1 * time.Hour,
@@ -361,12 +587,12 @@ func TestRoomStateManager_ClearVotes(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
 
-	err = manager.SubmitVote(roomID, user.ID, "5")
+	err = manager.SubmitVote(roomID, user.ID, "5", "")
 	if err != nil {
 		t.Fatalf("Failed to submit vote: %v", err)
 	}
@@ -405,6 +631,133 @@ func TestRoomStateManager_ClearVotes(t *testing.T) {
 	}
 }
 
+func TestRoomStateManager_RestoreRound(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	user, _ := room.CreateUser("user1", "Alice")
+	if err := manager.AddUser(roomID, user, room.RoomLimits{}); err != nil {
+		t.Fatalf("Failed to add user: %v", err)
+	}
+	_ = manager.SubmitVote(roomID, user.ID, "5", "")
+	_ = manager.SetActiveTask(roomID, "task1")
+	_ = manager.RevealVotes(roomID)
+
+	round, _ := manager.GetRoomState(roomID)
+	_ = manager.ClearVotes(roomID)
+
+	if err := manager.RestoreRound(roomID, round); err != nil {
+		t.Fatalf("Failed to restore round: %v", err)
+	}
+
+	state, _ := manager.GetRoomState(roomID)
+	if state.Votes[user.ID] != "5" || !state.IsRevealed || state.ActiveTaskID != "task1" {
+		t.Errorf("Expected the revealed vote on task1 back, got %+v", state)
+	}
+	if !state.Users[user.ID].IsVoted {
+		t.Error("User should be marked as voted again")
+	}
+
+	// The restored round belongs to the room, not to the snapshot
+	_ = manager.SubmitVote(roomID, user.ID, "8", "")
+	if round.Votes[user.ID] != "5" {
+		t.Errorf("Expected the snapshot to keep its vote, got %s", round.Votes[user.ID])
+	}
+}
+
+func TestRoomStateManager_UndoStack(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	first, _ := room.CreateUser("user1", "Alice")
+	second, _ := room.CreateUser("user2", "Bob")
+	_ = manager.AddUser(roomID, first, room.RoomLimits{})
+	_ = manager.AddUser(roomID, second, room.RoomLimits{})
+
+	if err := manager.PopUndo(roomID); err != room.ErrNothingToUndo {
+		t.Errorf("Expected ErrNothingToUndo, got %v", err)
+	}
+
+	for i := 0; i < maxUndoEntries+2; i++ {
+		if err := manager.PushUndo(roomID, ports.UndoEntry{Action: ports.UndoClear, UserID: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Failed to push undo entry: %v", err)
+		}
+	}
+
+	state, _ := manager.GetRoomState(roomID)
+	if state.FacilitatorID != first.ID {
+		t.Errorf("Expected the first user to be the facilitator, got %s", state.FacilitatorID)
+	}
+	if state.LastChange == nil || state.LastChange.UserID != fmt.Sprint(maxUndoEntries+1) {
+		t.Fatalf("Expected the latest change on top, got %+v", state.LastChange)
+	}
+
+	// Only the latest entries are kept
+	for i := 0; i < maxUndoEntries; i++ {
+		if err := manager.PopUndo(roomID); err != nil {
+			t.Fatalf("Failed to pop undo entry %d: %v", i, err)
+		}
+	}
+	state, _ = manager.GetRoomState(roomID)
+	if state.LastChange != nil {
+		t.Errorf("Expected the oldest changes to be dropped, got %+v", state.LastChange)
+	}
+}
+
+func TestRoomStateManager_FacilitatorPassesOnWhenLeaving(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		user, _ := room.CreateUser(name, name)
+		_ = manager.AddUser(roomID, user, room.RoomLimits{})
+	}
+
+	facilitator := func() string {
+		state, _ := manager.GetRoomState(roomID)
+		return state.FacilitatorID
+	}
+
+	_ = manager.RemoveUser(roomID, "Alice")
+	if got := facilitator(); got != "Bob" {
+		t.Errorf("Expected the role to pass to Bob, got %q", got)
+	}
+
+	_ = manager.RemoveUser(roomID, "Carol")
+	if got := facilitator(); got != "Bob" {
+		t.Errorf("Expected Bob to stay facilitator, got %q", got)
+	}
+
+	_ = manager.RemoveUser(roomID, "Bob")
+	if got := facilitator(); got != "" {
+		t.Errorf("Expected no facilitator in an empty room, got %q", got)
+	}
+
+	dave, _ := room.CreateUser("Dave", "Dave")
+	_ = manager.AddUser(roomID, dave, room.RoomLimits{})
+	if got := facilitator(); got != "Dave" {
+		t.Errorf("Expected the next user to join to be facilitator, got %q", got)
+	}
+}
+
 func TestRoomStateManager_ConcurrentAccess(t *testing.T) {
 	manager := NewRoomStateManager(CleanupConfig{
 		CleanupInterval: 1 * time.Hour,
@@ -431,7 +784,7 @@ func TestRoomStateManager_ConcurrentAccess(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to create user: %v", err)
 			}
-			err = manager.AddUser(roomID, user)
+			err = manager.AddUser(roomID, user, room.RoomLimits{})
 			if err != nil {
 				t.Errorf("Failed to add user %s: %v", userID, err)
 			}
@@ -450,6 +803,50 @@ func TestRoomStateManager_ConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestRoomStateManager_ConcurrentAddUserRespectsLimit(t *testing.T) {
+	manager := NewRoomStateManager(CleanupConfig{
+		CleanupInterval: 1 * time.Hour,
+		RoomTTL:         1 * time.Hour,
+	})
+
+	roomID := "testroom1"
+	if err := manager.NewRoom(roomID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	limits := room.RoomLimits{MaxParticipants: 5}
+	var wg sync.WaitGroup
+	var full atomic.Int32
+	for i := 0; i < 20; i++ {
+		user, err := room.CreateUser(fmt.Sprintf("user%d", i), fmt.Sprintf("User %d", i))
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := manager.AddUser(roomID, user, limits); errors.Is(err, room.ErrRoomFull) {
+				full.Add(1)
+			} else if err != nil {
+				t.Errorf("Failed to add user %s: %v", user.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	count, err := manager.GetUserCount(roomID)
+	if err != nil {
+		t.Fatalf("Failed to get user count: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected the room to stop at 5 users, got %d", count)
+	}
+	if full.Load() != 15 {
+		t.Errorf("Expected 15 joins to be refused, got %d", full.Load())
+	}
+}
+
 func TestRoomStateManager_Cleanup(t *testing.T) {
 	manager := NewRoomStateManager(CleanupConfig{
 		CleanupInterval: 100 * time.Millisecond,
@@ -467,7 +864,7 @@ func TestRoomStateManager_Cleanup(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
-	err = manager.AddUser(roomID, user)
+	err = manager.AddUser(roomID, user, room.RoomLimits{})
 	if err != nil {
 		t.Fatalf("Failed to add user: %v", err)
 	}
@@ -505,7 +902,7 @@ func TestRoomStateManager_Stats(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to create user: %v", err)
 		}
-		err = manager.AddUser(roomID, user)
+		err = manager.AddUser(roomID, user, room.RoomLimits{})
 		if err != nil {
 			t.Fatalf("Failed to add user: %v", err)
 		}
@@ -34,6 +34,258 @@ func (db *DB) Close() error {
 	return db.DB.Close()
 }
 
+type schemaMigration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrations is the ordered schema history; keep it in sync with the files in migrations/
+var migrations = []schemaMigration{
+	{
+		version: 1,
+		name:    "create_rooms_table",
+		sql: `
+			CREATE TABLE IF NOT EXISTS rooms (
+				id VARCHAR(10) PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				voting_system VARCHAR(20) NOT NULL DEFAULT 'dbs_fibo',
+				auto_reveal BOOLEAN NOT NULL DEFAULT false,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		version: 2,
+		name:    "create_tasks_table",
+		sql: `
+		CREATE TABLE IF NOT EXISTS tasks (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			room_id VARCHAR(10) NOT NULL,
+			headline VARCHAR(255) NOT NULL,
+			description TEXT,
+			tracker_link TEXT,
+			estimation VARCHAR(10),
+			position INTEGER NOT NULL,
+			CONSTRAINT fk_room FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE,
+			CONSTRAINT unique_room_position UNIQUE (room_id, position)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_tasks_room_id ON tasks(room_id);
+		-- position was replaced by rank in version 17; skip this when the migrations are replayed after it
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'position') THEN
+				CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks(room_id, position);
+			END IF;
+		END $$;
+		`,
+	},
+	{
+		version: 3,
+		name:    "add_task_status",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+		UPDATE tasks
+		SET status = 'estimated'
+		WHERE estimation IS NOT NULL AND estimation <> '' AND estimation <> '?';
+
+		-- position was replaced by rank in version 17; skip this when the migrations are replayed after it
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'position') THEN
+				CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(room_id, status, position);
+			END IF;
+		END $$;
+		`,
+	},
+	{
+		version: 4,
+		name:    "add_task_estimation_override",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimation_overridden BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS override_reason TEXT NOT NULL DEFAULT '';
+		`,
+	},
+	{
+		version: 5,
+		name:    "create_task_estimations_table",
+		sql: `
+		CREATE TABLE IF NOT EXISTS task_estimations (
+			id UUID PRIMARY KEY,
+			task_id UUID NOT NULL,
+			value VARCHAR(10) NOT NULL,
+			source VARCHAR(20) NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			participants TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			CONSTRAINT fk_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_task_estimations_task_id ON task_estimations(task_id, created_at);
+
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS previous_estimation VARCHAR(10) NOT NULL DEFAULT '';
+		`,
+	},
+	{
+		version: 6,
+		name:    "add_estimation_dimensions",
+		sql: `
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS estimation_dimensions JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS dimension_combination VARCHAR(20) NOT NULL DEFAULT '';
+
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimation_breakdown JSONB NOT NULL DEFAULT '{}';
+		`,
+	},
+	{
+		version: 7,
+		name:    "add_three_point_estimation",
+		sql: `
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS estimation_mode VARCHAR(20) NOT NULL DEFAULT 'standard';
+
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS pert_estimate JSONB;
+		`,
+	},
+	{
+		version: 8,
+		name:    "add_anonymous_votes",
+		sql: `
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS anonymous_votes BOOLEAN NOT NULL DEFAULT false;
+		`,
+	},
+	{
+		version: 9,
+		name:    "add_estimation_comments",
+		sql: `
+		ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS comments JSONB NOT NULL DEFAULT '[]';
+		`,
+	},
+	{
+		version: 10,
+		name:    "add_vote_change_policy",
+		sql: `
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS vote_change_policy VARCHAR(20) NOT NULL DEFAULT 'tracked';
+		ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS vote_changes JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS voter_names JSONB NOT NULL DEFAULT '{}';
+		`,
+	},
+	{
+		version: 11,
+		name:    "create_task_comments_table",
+		sql: `
+		CREATE TABLE IF NOT EXISTS task_comments (
+			id UUID PRIMARY KEY,
+			task_id UUID NOT NULL,
+			author_id VARCHAR(255) NOT NULL,
+			author_name VARCHAR(50) NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			CONSTRAINT fk_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments(task_id, created_at);
+		`,
+	},
+	{
+		version: 12,
+		name:    "add_task_decision_log",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS notes JSONB;
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS notes_history JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS notes TEXT[] NOT NULL DEFAULT '{}';
+		`,
+	},
+	{
+		version: 13,
+		name:    "add_acceptance_criteria",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS acceptance_criteria JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS require_acceptance_criteria BOOLEAN NOT NULL DEFAULT FALSE;
+		`,
+	},
+	{
+		version: 14,
+		name:    "add_task_labels_and_types",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS task_type VARCHAR(20) NOT NULL DEFAULT 'story';
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS task_filter JSONB NOT NULL DEFAULT '{}';
+
+		CREATE INDEX IF NOT EXISTS idx_tasks_labels ON tasks USING GIN (labels);
+		`,
+	},
+	{
+		version: 15,
+		name:    "add_task_hierarchy",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id) ON DELETE SET NULL;
+
+		CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+		`,
+	},
+	{
+		version: 16,
+		name:    "create_task_dependencies_table",
+		sql: `
+		CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id UUID NOT NULL,
+			depends_on_id UUID NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (task_id, depends_on_id),
+			CONSTRAINT fk_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			CONSTRAINT fk_depends_on FOREIGN KEY (depends_on_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			CONSTRAINT no_self_dependency CHECK (task_id <> depends_on_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on_id);
+		`,
+	},
+	{
+		version: 17,
+		name:    "add_task_ranks",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C";
+		-- On a replay position is already gone and every task has its rank
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'position') THEN
+				UPDATE tasks SET rank = lpad(position::text, 10, '0') || 'i' WHERE rank IS NULL;
+			END IF;
+		END $$;
+		ALTER TABLE tasks ALTER COLUMN rank SET NOT NULL;
+
+		ALTER TABLE tasks DROP CONSTRAINT IF EXISTS unique_room_position;
+		ALTER TABLE tasks DROP COLUMN IF EXISTS position;
+		CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(room_id, status);
+
+		ALTER TABLE tasks DROP CONSTRAINT IF EXISTS unique_sibling_rank;
+		ALTER TABLE tasks ADD CONSTRAINT unique_sibling_rank
+			UNIQUE NULLS NOT DISTINCT (room_id, parent_id, rank) DEFERRABLE INITIALLY IMMEDIATE;
+		`,
+	},
+	{
+		version: 18,
+		name:    "add_task_version",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+		`,
+	},
+	{
+		version: 19,
+		name:    "add_task_soft_delete",
+		sql: `
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+		ALTER TABLE tasks DROP CONSTRAINT IF EXISTS unique_sibling_rank;
+		ALTER TABLE tasks ADD CONSTRAINT unique_sibling_rank
+			UNIQUE NULLS NOT DISTINCT (room_id, parent_id, rank, deleted_at) DEFERRABLE INITIALLY IMMEDIATE;
+		`,
+	},
+}
+
 func (db *DB) RunMigrations() error { // use a migration tool later
 	createMigrationsTable := `
 		CREATE TABLE IF NOT EXISTS schema_migrations (
@@ -45,47 +297,6 @@ func (db *DB) RunMigrations() error { // use a migration tool later
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
-	migrations := []struct {
-		version int
-		name    string
-		sql     string
-	}{
-		{
-			version: 1,
-			name:    "create_rooms_table",
-			sql: `
-				CREATE TABLE IF NOT EXISTS rooms (
-					id VARCHAR(10) PRIMARY KEY,
-					name VARCHAR(255) NOT NULL,
-					voting_system VARCHAR(20) NOT NULL DEFAULT 'dbs_fibo',
-					auto_reveal BOOLEAN NOT NULL DEFAULT false,
-					created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-					updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-				);
-			`,
-		},
-		{
-			version: 2,
-			name:    "create_tasks_table",
-			sql: `
-			CREATE TABLE IF NOT EXISTS tasks (
-				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-				room_id VARCHAR(10) NOT NULL,
-				headline VARCHAR(255) NOT NULL,
-				description TEXT,
-				tracker_link TEXT,
-				estimation VARCHAR(10),
-				position INTEGER NOT NULL,
-				CONSTRAINT fk_room FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE,
-				CONSTRAINT unique_room_position UNIQUE (room_id, position)
-			);
-
-			CREATE INDEX IF NOT EXISTS idx_tasks_room_id ON tasks(room_id);
-			CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks(room_id, position);
-			`,
-		},
-	}
-
 	for _, migration := range migrations {
 		var count int
 		err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = $1", migration.version).Scan(&count)
@@ -116,3 +327,45 @@ func (db *DB) RunMigrations() error { // use a migration tool later
 func (db *DB) PingContext(ctx context.Context) error {
 	return db.DB.PingContext(ctx)
 }
+
+// PendingMigrations returns the versions known to this build that are not yet recorded in schema_migrations
+func (db *DB) PendingMigrations(ctx context.Context) ([]int, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	var pending []int
+	for _, migration := range migrations {
+		if !applied[migration.version] {
+			pending = append(pending, migration.version)
+		}
+	}
+
+	return pending, nil
+}
+
+// CheckMigrations fails while the schema is behind this build, so the pod is kept out of rotation
+func (db *DB) CheckMigrations(ctx context.Context) error {
+	pending, err := db.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %v", pending)
+	}
+	return nil
+}
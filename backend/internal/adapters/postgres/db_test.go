@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDB_PendingMigrations_AfterRun(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	pending, err := db.PendingMigrations(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read migration status: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending migrations after RunMigrations, got %v", pending)
+	}
+
+	if err := db.CheckMigrations(context.Background()); err != nil {
+		t.Errorf("Expected CheckMigrations to pass, got %v", err)
+	}
+}
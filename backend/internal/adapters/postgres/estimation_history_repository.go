@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+type EstimationHistoryRepo struct {
+	db *DB
+}
+
+// commentRow is the JSON shape of one entry of task_estimations.comments
+type commentRow struct {
+	Author  string `json:"author,omitempty"`
+	Value   string `json:"value"`
+	Comment string `json:"comment"`
+}
+
+func encodeComments(comments []room.VoteComment) ([]byte, error) {
+	rows := make([]commentRow, len(comments))
+	for i, comment := range comments {
+		rows[i] = commentRow{Author: comment.Author, Value: comment.Value, Comment: comment.Comment}
+	}
+	return json.Marshal(rows)
+}
+
+func decodeComments(data []byte) ([]room.VoteComment, error) {
+	var rows []commentRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	comments := make([]room.VoteComment, len(rows))
+	for i, row := range rows {
+		comments[i] = room.VoteComment{Author: row.Author, Value: row.Value, Comment: row.Comment}
+	}
+	return comments, nil
+}
+
+// changeRow is the JSON shape of one entry of task_estimations.vote_changes; Voter is a user ID,
+// named through task_estimations.voter_names
+type changeRow struct {
+	Voter string `json:"voter,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+}
+
+func encodeChanges(changes []room.VoteChange) ([]byte, error) {
+	rows := make([]changeRow, len(changes))
+	for i, change := range changes {
+		rows[i] = changeRow{Voter: change.Voter, From: change.From, To: change.To}
+	}
+	return json.Marshal(rows)
+}
+
+func decodeChanges(data []byte) ([]room.VoteChange, error) {
+	var rows []changeRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	changes := make([]room.VoteChange, len(rows))
+	for i, row := range rows {
+		changes[i] = room.VoteChange{Voter: row.Voter, From: row.From, To: row.To}
+	}
+	return changes, nil
+}
+
+func decodeVoterNames(data []byte) (map[string]string, error) {
+	var names map[string]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return names, nil
+}
+
+func NewEstimationHistoryRepository(db *DB) *EstimationHistoryRepo {
+	return &EstimationHistoryRepo{db: db}
+}
+
+func (r *EstimationHistoryRepo) Add(ctx context.Context, record *room.EstimationRecord) error {
+	query := `
+        INSERT INTO task_estimations (id, task_id, value, source, reason, participants, notes, comments, vote_changes, voter_names, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    `
+
+	comments, err := encodeComments(record.Comments)
+	if err != nil {
+		return fmt.Errorf("failed to encode estimation comments: %w", err)
+	}
+	changes, err := encodeChanges(record.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode vote changes: %w", err)
+	}
+	voterNames := record.VoterNames
+	if voterNames == nil {
+		voterNames = map[string]string{}
+	}
+	names, err := json.Marshal(voterNames)
+	if err != nil {
+		return fmt.Errorf("failed to encode voter names: %w", err)
+	}
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		record.ID,
+		record.TaskID,
+		record.Value,
+		record.Source,
+		record.Reason,
+		pq.Array(record.Participants),
+		pq.Array(record.Notes),
+		comments,
+		changes,
+		names,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add estimation record: %w", err)
+	}
+	return nil
+}
+
+func (r *EstimationHistoryRepo) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM task_estimations WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete estimation record: %w", err)
+	}
+	return nil
+}
+
+func (r *EstimationHistoryRepo) ListByTask(ctx context.Context, taskID string) ([]*room.EstimationRecord, error) {
+	query := `
+        SELECT id, task_id, value, source, reason, participants, notes, comments, vote_changes, voter_names, created_at
+        FROM task_estimations
+        WHERE task_id = $1
+        ORDER BY created_at ASC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query estimation history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*room.EstimationRecord
+	for rows.Next() {
+		var record room.EstimationRecord
+		var comments, changes, names []byte
+		err := rows.Scan(
+			&record.ID,
+			&record.TaskID,
+			&record.Value,
+			&record.Source,
+			&record.Reason,
+			pq.Array(&record.Participants),
+			pq.Array(&record.Notes),
+			&comments,
+			&changes,
+			&names,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan estimation record: %w", err)
+		}
+		if record.Comments, err = decodeComments(comments); err != nil {
+			return nil, fmt.Errorf("failed to decode estimation comments: %w", err)
+		}
+		if record.Changes, err = decodeChanges(changes); err != nil {
+			return nil, fmt.Errorf("failed to decode vote changes: %w", err)
+		}
+		if record.VoterNames, err = decodeVoterNames(names); err != nil {
+			return nil, fmt.Errorf("failed to decode voter names: %w", err)
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read estimation history: %w", err)
+	}
+
+	return records, nil
+}
@@ -15,4 +15,10 @@ CREATE TABLE IF NOT EXISTS tasks (
 );
 
 CREATE INDEX IF NOT EXISTS idx_tasks_room_id ON tasks(room_id);
-CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks(room_id, position);
+-- position was replaced by rank in version 17; skip this when the migrations are replayed after it
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'position') THEN
+        CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks(room_id, position);
+    END IF;
+END $$;
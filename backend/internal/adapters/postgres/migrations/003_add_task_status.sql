@@ -0,0 +1,17 @@
+-- Migration: Add task status
+-- Version: 3
+-- Description: Track task lifecycle (pending, in discussion, estimated, skipped, deferred)
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+UPDATE tasks
+SET status = 'estimated'
+WHERE estimation IS NOT NULL AND estimation <> '' AND estimation <> '?';
+
+-- position was replaced by rank in version 17; skip this when the migrations are replayed after it
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'position') THEN
+        CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(room_id, status, position);
+    END IF;
+END $$;
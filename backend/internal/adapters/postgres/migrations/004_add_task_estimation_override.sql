@@ -0,0 +1,6 @@
+-- Migration: Add estimation override tracking
+-- Version: 4
+-- Description: Record whether the accepted estimation differs from the suggested one, and why
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimation_overridden BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS override_reason TEXT NOT NULL DEFAULT '';
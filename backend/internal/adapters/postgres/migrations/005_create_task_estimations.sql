@@ -0,0 +1,18 @@
+-- Migration: Create task estimation history
+-- Version: 5
+-- Description: Keep every accepted estimation per task and expose the previous one on the task
+
+CREATE TABLE IF NOT EXISTS task_estimations (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL,
+    value VARCHAR(10) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    participants TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_estimations_task_id ON task_estimations(task_id, created_at);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS previous_estimation VARCHAR(10) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- Migration: Add estimation dimensions
+-- Version: 6
+-- Description: Let rooms estimate on several dimensions and keep the per-dimension breakdown on tasks
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS estimation_dimensions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS dimension_combination VARCHAR(20) NOT NULL DEFAULT '';
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimation_breakdown JSONB NOT NULL DEFAULT '{}';
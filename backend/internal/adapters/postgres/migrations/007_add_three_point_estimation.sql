@@ -0,0 +1,7 @@
+-- Migration: Add three-point estimation
+-- Version: 7
+-- Description: Add the room estimation mode and keep the PERT expected value and range on tasks
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS estimation_mode VARCHAR(20) NOT NULL DEFAULT 'standard';
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS pert_estimate JSONB;
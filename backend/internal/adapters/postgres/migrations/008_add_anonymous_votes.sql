@@ -0,0 +1,5 @@
+-- Migration: Add anonymous voting
+-- Version: 8
+-- Description: Let rooms show revealed votes only as a distribution
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS anonymous_votes BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+-- Migration: Add estimation comments
+-- Version: 9
+-- Description: Keep the rationale voters left with their votes in the estimation history
+
+ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS comments JSONB NOT NULL DEFAULT '[]';
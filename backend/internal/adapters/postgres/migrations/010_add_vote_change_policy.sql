@@ -0,0 +1,8 @@
+-- Migration: Add vote change policy
+-- Version: 10
+-- Description: Let rooms lock votes after reveal or track changes, and keep the changes with the round
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS vote_change_policy VARCHAR(20) NOT NULL DEFAULT 'tracked';
+ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS vote_changes JSONB NOT NULL DEFAULT '[]';
+-- The changes name their voters by user ID; the names the voters had in the round are kept alongside
+ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS voter_names JSONB NOT NULL DEFAULT '{}';
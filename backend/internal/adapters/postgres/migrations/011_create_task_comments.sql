@@ -0,0 +1,16 @@
+-- Migration: Create task comments table
+-- Version: 11
+-- Description: Discussion thread attached to each task
+
+CREATE TABLE IF NOT EXISTS task_comments (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL,
+    author_id VARCHAR(255) NOT NULL,
+    author_name VARCHAR(50) NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments(task_id, created_at);
@@ -0,0 +1,8 @@
+-- Migration: Add task decision log
+-- Version: 12
+-- Description: Facilitator notes with the assumptions behind each estimate, and their earlier revisions
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS notes JSONB;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS notes_history JSONB NOT NULL DEFAULT '[]';
+-- Each accepted estimate keeps the notes it was made on
+ALTER TABLE task_estimations ADD COLUMN IF NOT EXISTS notes TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,6 @@
+-- Migration: Add acceptance criteria
+-- Version: 13
+-- Description: Ordered acceptance criteria checklist on tasks and the room rule requiring it before voting
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS acceptance_criteria JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS require_acceptance_criteria BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+-- Migration: Add task labels and types
+-- Version: 14
+-- Description: Label and type tasks, and save a room filter restricting which task is estimated next
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS task_type VARCHAR(20) NOT NULL DEFAULT 'story';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS task_filter JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_labels ON tasks USING GIN (labels);
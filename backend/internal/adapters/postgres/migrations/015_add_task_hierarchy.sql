@@ -0,0 +1,7 @@
+-- Migration: Add task hierarchy
+-- Version: 15
+-- Description: Let tasks be subtasks of an epic; subtasks of a deleted epic are moved up by the application, NULL is the fallback
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
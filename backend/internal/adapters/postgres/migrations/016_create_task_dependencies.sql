@@ -0,0 +1,15 @@
+-- Migration: Create task dependencies table
+-- Version: 16
+-- Description: Many-to-many "blocked by" relation between tasks of a room
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id UUID NOT NULL,
+    depends_on_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, depends_on_id),
+    CONSTRAINT fk_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_depends_on FOREIGN KEY (depends_on_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT no_self_dependency CHECK (task_id <> depends_on_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on_id);
@@ -0,0 +1,22 @@
+-- Migration: Add task ranks
+-- Version: 17
+-- Description: Order tasks among their siblings by a lexicographic rank instead of a stored position
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C";
+-- On a replay position is already gone and every task has its rank
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'position') THEN
+        UPDATE tasks SET rank = lpad(position::text, 10, '0') || 'i' WHERE rank IS NULL;
+    END IF;
+END $$;
+ALTER TABLE tasks ALTER COLUMN rank SET NOT NULL;
+
+-- Positions are now derived from the ranks when tasks are read
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS unique_room_position;
+ALTER TABLE tasks DROP COLUMN IF EXISTS position;
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(room_id, status);
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS unique_sibling_rank;
+ALTER TABLE tasks ADD CONSTRAINT unique_sibling_rank
+    UNIQUE NULLS NOT DISTINCT (room_id, parent_id, rank) DEFERRABLE INITIALLY IMMEDIATE;
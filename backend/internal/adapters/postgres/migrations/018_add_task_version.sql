@@ -0,0 +1,5 @@
+-- Migration: Add task version
+-- Version: 18
+-- Description: Count saved changes per task so edits made on an outdated copy are rejected
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
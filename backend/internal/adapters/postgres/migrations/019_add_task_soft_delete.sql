@@ -0,0 +1,10 @@
+-- Migration: Add task soft delete
+-- Version: 19
+-- Description: Keep deleted tasks so that a deletion can be undone
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- A deleted task keeps its rank without holding it against the live siblings
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS unique_sibling_rank;
+ALTER TABLE tasks ADD CONSTRAINT unique_sibling_rank
+    UNIQUE NULLS NOT DISTINCT (room_id, parent_id, rank, deleted_at) DEFERRABLE INITIALLY IMMEDIATE;
@@ -3,12 +3,81 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
+// dimensionRow is the JSON shape of an estimation dimension in rooms.estimation_dimensions
+type dimensionRow struct {
+	Key          string  `json:"key"`
+	Name         string  `json:"name"`
+	VotingSystem string  `json:"votingSystem"`
+	Weight       float64 `json:"weight,omitempty"`
+}
+
+func encodeDimensions(dimensions []room.EstimationDimension) ([]byte, error) {
+	rows := make([]dimensionRow, len(dimensions))
+	for i, dimension := range dimensions {
+		rows[i] = dimensionRow{
+			Key:          dimension.Key,
+			Name:         dimension.Name,
+			VotingSystem: string(dimension.VotingSystem),
+			Weight:       dimension.Weight,
+		}
+	}
+	return json.Marshal(rows)
+}
+
+func decodeDimensions(data []byte) ([]room.EstimationDimension, error) {
+	var rows []dimensionRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	dimensions := make([]room.EstimationDimension, len(rows))
+	for i, row := range rows {
+		dimensions[i] = room.EstimationDimension{
+			Key:          row.Key,
+			Name:         row.Name,
+			VotingSystem: room.VotingSystem(row.VotingSystem),
+			Weight:       row.Weight,
+		}
+	}
+	return dimensions, nil
+}
+
+// taskFilterRow is the JSON shape of rooms.task_filter
+type taskFilterRow struct {
+	Labels   []string `json:"labels,omitempty"`
+	Types    []string `json:"types,omitempty"`
+	Statuses []string `json:"statuses,omitempty"`
+}
+
+func encodeTaskFilter(filter room.TaskFilter) ([]byte, error) {
+	row := taskFilterRow{Labels: filter.Labels}
+	for _, t := range filter.Types {
+		row.Types = append(row.Types, string(t))
+	}
+	for _, s := range filter.Statuses {
+		row.Statuses = append(row.Statuses, string(s))
+	}
+	return json.Marshal(row)
+}
+
+func decodeTaskFilter(data []byte) (room.TaskFilter, error) {
+	var row taskFilterRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return room.TaskFilter{}, err
+	}
+	return room.NewTaskFilter(row.Labels, row.Types, row.Statuses)
+}
+
 type RoomRepo struct {
 	db *DB
 }
@@ -19,17 +88,34 @@ func NewRoomRepository(db *DB) *RoomRepo {
 
 func (r *RoomRepo) Create(ctx context.Context, rm *room.Room) error {
 	query := `
-		INSERT INTO rooms (id, name, voting_system, auto_reveal, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO rooms (id, name, voting_system, auto_reveal, estimation_dimensions, dimension_combination, estimation_mode,
+			anonymous_votes, vote_change_policy, require_acceptance_criteria, task_filter, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
-	_, err := r.db.ExecContext(
+	dimensions, err := encodeDimensions(rm.Dimensions)
+	if err != nil {
+		return fmt.Errorf("failed to encode estimation dimensions: %w", err)
+	}
+	taskFilter, err := encodeTaskFilter(rm.TaskFilter)
+	if err != nil {
+		return fmt.Errorf("failed to encode task filter: %w", err)
+	}
+
+	_, err = r.db.ExecContext(
 		ctx,
 		query,
 		rm.ID,
 		rm.Name,
 		rm.VotingSystem,
 		rm.AutoReveal,
+		dimensions,
+		rm.Combination,
+		rm.Mode,
+		rm.AnonymousVotes,
+		rm.VoteChangePolicy,
+		rm.RequireAcceptanceCriteria,
+		taskFilter,
 		rm.CreatedAt,
 		rm.UpdatedAt,
 	)
@@ -43,19 +129,28 @@ func (r *RoomRepo) Create(ctx context.Context, rm *room.Room) error {
 
 func (r *RoomRepo) GetByID(ctx context.Context, id string) (*room.Room, error) {
 	query := `
-		SELECT id, name, voting_system, auto_reveal, created_at, updated_at
+		SELECT id, name, voting_system, auto_reveal, estimation_dimensions, dimension_combination, estimation_mode,
+			anonymous_votes, vote_change_policy, require_acceptance_criteria, task_filter, created_at, updated_at
 		FROM rooms
 		WHERE id = $1
 	`
 
 	var rm room.Room
 	var votingSystem string
+	var dimensions, taskFilter []byte
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&rm.ID,
 		&rm.Name,
 		&votingSystem,
 		&rm.AutoReveal,
+		&dimensions,
+		&rm.Combination,
+		&rm.Mode,
+		&rm.AnonymousVotes,
+		&rm.VoteChangePolicy,
+		&rm.RequireAcceptanceCriteria,
+		&taskFilter,
 		&rm.CreatedAt,
 		&rm.UpdatedAt,
 	)
@@ -68,6 +163,13 @@ func (r *RoomRepo) GetByID(ctx context.Context, id string) (*room.Room, error) {
 	}
 
 	rm.VotingSystem = room.VotingSystem(votingSystem)
+	rm.Dimensions, err = decodeDimensions(dimensions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode estimation dimensions: %w", err)
+	}
+	if rm.TaskFilter, err = decodeTaskFilter(taskFilter); err != nil {
+		return nil, fmt.Errorf("failed to decode task filter: %w", err)
+	}
 
 	return &rm, nil
 }
@@ -75,10 +177,20 @@ func (r *RoomRepo) GetByID(ctx context.Context, id string) (*room.Room, error) {
 func (r *RoomRepo) Update(ctx context.Context, rm *room.Room) error {
 	query := `
 		UPDATE rooms
-		SET name = $2, voting_system = $3, auto_reveal = $4, updated_at = $5
+		SET name = $2, voting_system = $3, auto_reveal = $4, estimation_dimensions = $5, dimension_combination = $6, estimation_mode = $7,
+			anonymous_votes = $8, vote_change_policy = $9, require_acceptance_criteria = $10, task_filter = $11, updated_at = $12
 		WHERE id = $1
 	`
 
+	dimensions, err := encodeDimensions(rm.Dimensions)
+	if err != nil {
+		return fmt.Errorf("failed to encode estimation dimensions: %w", err)
+	}
+	taskFilter, err := encodeTaskFilter(rm.TaskFilter)
+	if err != nil {
+		return fmt.Errorf("failed to encode task filter: %w", err)
+	}
+
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
@@ -86,6 +198,13 @@ func (r *RoomRepo) Update(ctx context.Context, rm *room.Room) error {
 		rm.Name,
 		rm.VotingSystem,
 		rm.AutoReveal,
+		dimensions,
+		rm.Combination,
+		rm.Mode,
+		rm.AnonymousVotes,
+		rm.VoteChangePolicy,
+		rm.RequireAcceptanceCriteria,
+		taskFilter,
 		rm.UpdatedAt,
 	)
 
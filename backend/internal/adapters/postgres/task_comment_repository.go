@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+const taskCommentColumns = `id, task_id, author_id, author_name, body, created_at, updated_at`
+
+type TaskCommentRepo struct {
+	db *DB
+}
+
+func NewTaskCommentRepository(db *DB) *TaskCommentRepo {
+	return &TaskCommentRepo{db: db}
+}
+
+func scanTaskComment(row rowScanner) (*room.TaskComment, error) {
+	var comment room.TaskComment
+	err := row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.AuthorID,
+		&comment.AuthorName,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *TaskCommentRepo) Create(ctx context.Context, comment *room.TaskComment) error {
+	query := `
+        INSERT INTO task_comments (` + taskCommentColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		comment.ID,
+		comment.TaskID,
+		comment.AuthorID,
+		comment.AuthorName,
+		comment.Body,
+		comment.CreatedAt,
+		comment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+func (r *TaskCommentRepo) GetByID(ctx context.Context, id string) (*room.TaskComment, error) {
+	query := `
+        SELECT ` + taskCommentColumns + `
+        FROM task_comments
+        WHERE id = $1
+    `
+
+	comment, err := scanTaskComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, room.ErrTaskCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return comment, nil
+}
+
+func (r *TaskCommentRepo) Update(ctx context.Context, comment *room.TaskComment) error {
+	query := `
+        UPDATE task_comments
+        SET body = $2, updated_at = $3
+        WHERE id = $1
+    `
+
+	result, err := r.db.ExecContext(ctx, query, comment.ID, comment.Body, comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return room.ErrTaskCommentNotFound
+	}
+	return nil
+}
+
+func (r *TaskCommentRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM task_comments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return room.ErrTaskCommentNotFound
+	}
+	return nil
+}
+
+func (r *TaskCommentRepo) ListByTask(ctx context.Context, taskID string, limit, offset int) ([]*room.TaskComment, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM task_comments WHERE task_id = $1`, taskID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	query := `
+        SELECT ` + taskCommentColumns + `
+        FROM task_comments
+        WHERE task_id = $1
+        ORDER BY created_at ASC, id ASC
+        LIMIT $2 OFFSET $3
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*room.TaskComment
+	for rows.Next() {
+		comment, err := scanTaskComment(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read comments: %w", err)
+	}
+
+	return comments, total, nil
+}
@@ -3,12 +3,278 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
+const taskColumns = `id, room_id, headline, description, tracker_link, estimation, status, rank,
+        estimation_overridden, override_reason, previous_estimation, estimation_breakdown, pert_estimate,
+        notes, notes_history, acceptance_criteria, task_type, labels, parent_id, version`
+
+// taskSelectColumns adds the task's backlog position and its dependencies on tasks that are
+// not deleted, oldest first, to the stored columns. Selects using them join task_order, see withTaskOrder.
+const taskSelectColumns = taskColumns + `, task_order.position,
+        ARRAY(SELECT d.depends_on_id::text FROM task_dependencies d JOIN tasks dep ON dep.id = d.depends_on_id
+              WHERE d.task_id = tasks.id AND dep.deleted_at IS NULL
+              ORDER BY d.created_at, d.depends_on_id) AS depends_on`
+
+// withTaskOrder numbers the tasks of the room given by roomExpr in backlog order: depth-first,
+// each epic directly followed by its subtasks, siblings sorted by rank. Deleted tasks are left out,
+// so joining task_order hides them.
+func withTaskOrder(roomExpr string) string {
+	return `
+        WITH RECURSIVE task_paths AS (
+            SELECT id, ARRAY[rank] AS path FROM tasks
+            WHERE room_id = ` + roomExpr + ` AND parent_id IS NULL AND deleted_at IS NULL
+            UNION ALL
+            SELECT t.id, p.path || t.rank FROM tasks t JOIN task_paths p ON t.parent_id = p.id
+            WHERE t.deleted_at IS NULL
+        ),
+        task_order AS (
+            SELECT id, ROW_NUMBER() OVER (ORDER BY path)::int AS position FROM task_paths
+        )`
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (*room.Task, error) {
+	var task room.Task
+	var breakdown, pert, notes, notesHistory, criteria []byte
+	var parentID sql.NullString
+	err := row.Scan(
+		&task.ID,
+		&task.RoomID,
+		&task.Headline,
+		&task.Description,
+		&task.TrackerLink,
+		&task.Estimation,
+		&task.Status,
+		&task.Rank,
+		&task.EstimationOverridden,
+		&task.OverrideReason,
+		&task.PreviousEstimation,
+		&breakdown,
+		&pert,
+		&notes,
+		&notesHistory,
+		&criteria,
+		&task.Type,
+		pq.Array(&task.Labels),
+		&parentID,
+		&task.Version,
+		&task.Position,
+		pq.Array(&task.DependsOn),
+	)
+	if err != nil {
+		return nil, err
+	}
+	task.ParentID = parentID.String
+	if err := json.Unmarshal(breakdown, &task.EstimationBreakdown); err != nil {
+		return nil, fmt.Errorf("failed to decode estimation breakdown: %w", err)
+	}
+	if len(task.EstimationBreakdown) == 0 {
+		task.EstimationBreakdown = nil
+	}
+	if task.Pert, err = decodePert(pert); err != nil {
+		return nil, fmt.Errorf("failed to decode PERT estimate: %w", err)
+	}
+	if task.Notes, task.NotesHistory, err = decodeNotes(notes, notesHistory); err != nil {
+		return nil, fmt.Errorf("failed to decode decision log: %w", err)
+	}
+	if task.AcceptanceCriteria, err = decodeCriteria(criteria); err != nil {
+		return nil, fmt.Errorf("failed to decode acceptance criteria: %w", err)
+	}
+	if len(task.Labels) == 0 {
+		task.Labels = nil
+	}
+	if len(task.DependsOn) == 0 {
+		task.DependsOn = nil
+	}
+	return &task, nil
+}
+
+// pertRow is the JSON shape of tasks.pert_estimate
+type pertRow struct {
+	Optimistic  float64 `json:"optimistic"`
+	MostLikely  float64 `json:"mostLikely"`
+	Pessimistic float64 `json:"pessimistic"`
+	Expected    float64 `json:"expected"`
+	StdDev      float64 `json:"stdDev"`
+}
+
+// encodePert stores a missing PERT estimate as NULL
+func encodePert(pert *room.PertEstimate) (any, error) {
+	if pert == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(pertRow{
+		Optimistic:  pert.Optimistic,
+		MostLikely:  pert.MostLikely,
+		Pessimistic: pert.Pessimistic,
+		Expected:    pert.Expected,
+		StdDev:      pert.StdDev,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func decodePert(data []byte) (*room.PertEstimate, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var row pertRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return nil, err
+	}
+	return &room.PertEstimate{
+		ThreePointEstimate: room.ThreePointEstimate{
+			Optimistic:  row.Optimistic,
+			MostLikely:  row.MostLikely,
+			Pessimistic: row.Pessimistic,
+		},
+		Expected: row.Expected,
+		StdDev:   row.StdDev,
+	}, nil
+}
+
+// notesRow is the JSON shape of one decision log revision in tasks.notes and tasks.notes_history
+type notesRow struct {
+	Items     []string  `json:"items"`
+	Author    string    `json:"author"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func toNotesRow(notes room.TaskNotes) notesRow {
+	items := notes.Items
+	if items == nil {
+		items = []string{}
+	}
+	return notesRow{Items: items, Author: notes.Author, UpdatedAt: notes.UpdatedAt}
+}
+
+func fromNotesRow(row notesRow) room.TaskNotes {
+	return room.TaskNotes{Items: row.Items, Author: row.Author, UpdatedAt: row.UpdatedAt}
+}
+
+// encodeNotes stores a missing decision log as NULL and its history as a JSON array
+func encodeNotes(notes *room.TaskNotes, history []room.TaskNotes) (any, []byte, error) {
+	rows := make([]notesRow, len(history))
+	for i, revision := range history {
+		rows[i] = toNotesRow(revision)
+	}
+	historyData, err := json.Marshal(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if notes == nil {
+		return nil, historyData, nil
+	}
+	notesData, err := json.Marshal(toNotesRow(*notes))
+	if err != nil {
+		return nil, nil, err
+	}
+	return notesData, historyData, nil
+}
+
+func decodeNotes(notesData, historyData []byte) (*room.TaskNotes, []room.TaskNotes, error) {
+	var rows []notesRow
+	if err := json.Unmarshal(historyData, &rows); err != nil {
+		return nil, nil, err
+	}
+	var history []room.TaskNotes
+	for _, row := range rows {
+		history = append(history, fromNotesRow(row))
+	}
+
+	if notesData == nil {
+		return nil, history, nil
+	}
+	var row notesRow
+	if err := json.Unmarshal(notesData, &row); err != nil {
+		return nil, nil, err
+	}
+	notes := fromNotesRow(row)
+	return &notes, history, nil
+}
+
+// criterionRow is the JSON shape of one item of tasks.acceptance_criteria
+type criterionRow struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+func encodeCriteria(criteria []room.AcceptanceCriterion) ([]byte, error) {
+	rows := make([]criterionRow, len(criteria))
+	for i, criterion := range criteria {
+		rows[i] = criterionRow{ID: criterion.ID, Text: criterion.Text, Done: criterion.Done}
+	}
+	return json.Marshal(rows)
+}
+
+func decodeCriteria(data []byte) ([]room.AcceptanceCriterion, error) {
+	var rows []criterionRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	criteria := make([]room.AcceptanceCriterion, len(rows))
+	for i, row := range rows {
+		criteria[i] = room.AcceptanceCriterion{ID: row.ID, Text: row.Text, Done: row.Done}
+	}
+	return criteria, nil
+}
+
+// labelsOrEmpty stores missing labels as an empty array, the column is NOT NULL
+func labelsOrEmpty(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+// nullableParent stores top-level tasks with a NULL parent_id
+func nullableParent(parentID string) any {
+	if parentID == "" {
+		return nil
+	}
+	return parentID
+}
+
+// filterArgs turns a task filter into text arrays; an empty array matches every task
+func filterArgs(filter room.TaskFilter) (labels, types, statuses any) {
+	typeNames := make([]string, len(filter.Types))
+	for i, t := range filter.Types {
+		typeNames[i] = string(t)
+	}
+	statusNames := make([]string, len(filter.Statuses))
+	for i, s := range filter.Statuses {
+		statusNames[i] = string(s)
+	}
+	return pq.Array(labelsOrEmpty(filter.Labels)), pq.Array(typeNames), pq.Array(statusNames)
+}
+
+// encodeBreakdown stores a missing breakdown as an empty JSON object
+func encodeBreakdown(breakdown map[string]string) ([]byte, error) {
+	if breakdown == nil {
+		breakdown = map[string]string{}
+	}
+	return json.Marshal(breakdown)
+}
+
 type TaskRepo struct {
 	db *DB
 }
@@ -19,11 +285,28 @@ func NewTaskRepository(db *DB) *TaskRepo {
 
 func (r *TaskRepo) Create(ctx context.Context, task *room.Task) error {
 	query := `
-        INSERT INTO tasks (id, room_id, headline, description, tracker_link, estimation, position)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO tasks (` + taskColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
     `
 
-	_, err := r.db.ExecContext(
+	breakdown, err := encodeBreakdown(task.EstimationBreakdown)
+	if err != nil {
+		return fmt.Errorf("failed to encode estimation breakdown: %w", err)
+	}
+	pert, err := encodePert(task.Pert)
+	if err != nil {
+		return fmt.Errorf("failed to encode PERT estimate: %w", err)
+	}
+	notes, notesHistory, err := encodeNotes(task.Notes, task.NotesHistory)
+	if err != nil {
+		return fmt.Errorf("failed to encode decision log: %w", err)
+	}
+	criteria, err := encodeCriteria(task.AcceptanceCriteria)
+	if err != nil {
+		return fmt.Errorf("failed to encode acceptance criteria: %w", err)
+	}
+
+	_, err = r.db.ExecContext(
 		ctx,
 		query,
 		task.ID,
@@ -32,28 +315,45 @@ func (r *TaskRepo) Create(ctx context.Context, task *room.Task) error {
 		task.Description,
 		task.TrackerLink,
 		task.Estimation,
-		task.Position,
+		task.Status,
+		task.Rank,
+		task.EstimationOverridden,
+		task.OverrideReason,
+		task.PreviousEstimation,
+		breakdown,
+		pert,
+		notes,
+		notesHistory,
+		criteria,
+		task.Type,
+		pq.Array(labelsOrEmpty(task.Labels)),
+		nullableParent(task.ParentID),
+		task.Version,
 	)
 
 	if err != nil {
+		if isRankTaken(err) {
+			return room.ErrTaskRankTaken
+		}
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 	return nil
 }
 
-func (r *TaskRepo) GetByID(ctx context.Context, id string) (*room.Task, error) {
-	query := `
-        SELECT id, room_id, headline, description, tracker_link, estimation, position
-        FROM tasks
-        WHERE id = $1
-    `
+// isRankTaken reports whether err is a sibling of the task already holding its rank
+func isRankTaken(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "unique_sibling_rank"
+}
 
-	var task room.Task
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.ID, &task.RoomID, &task.Headline, &task.Description,
-		&task.TrackerLink, &task.Estimation, &task.Position,
-	)
+func (r *TaskRepo) GetByID(ctx context.Context, roomID, id string) (*room.Task, error) {
+	query := withTaskOrder(`$2`) + `
+        SELECT ` + taskSelectColumns + `
+        FROM tasks JOIN task_order USING (id)
+        WHERE id = $1 AND room_id = $2
+    `
 
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id, roomID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, room.ErrTaskNotFound
@@ -61,15 +361,14 @@ func (r *TaskRepo) GetByID(ctx context.Context, id string) (*room.Task, error) {
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	return &task, nil
+	return task, nil
 }
 
 func (r *TaskRepo) GetByRoomID(ctx context.Context, roomID string) ([]*room.Task, error) {
-	query := `
-        SELECT id, room_id, headline, description, tracker_link, estimation, position
-        FROM tasks
-        WHERE room_id = $1
-        ORDER BY position ASC
+	query := withTaskOrder(`$1`) + `
+        SELECT ` + taskSelectColumns + `
+        FROM tasks JOIN task_order USING (id)
+        ORDER BY task_order.position ASC
     `
 
 	rows, err := r.db.QueryContext(ctx, query, roomID)
@@ -80,20 +379,11 @@ func (r *TaskRepo) GetByRoomID(ctx context.Context, roomID string) ([]*room.Task
 
 	var tasks []*room.Task
 	for rows.Next() {
-		var task room.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.RoomID,
-			&task.Headline,
-			&task.Description,
-			&task.TrackerLink,
-			&task.Estimation,
-			&task.Position,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-		tasks = append(tasks, &task)
+		tasks = append(tasks, task)
 	}
 
 	return tasks, nil
@@ -102,10 +392,31 @@ func (r *TaskRepo) GetByRoomID(ctx context.Context, roomID string) ([]*room.Task
 func (r *TaskRepo) Update(ctx context.Context, task *room.Task) error {
 	query := `
         UPDATE tasks
-        SET headline = $2, description = $3, tracker_link = $4, estimation = $5, position = $6
-        WHERE id = $1
+        SET headline = $2, description = $3, tracker_link = $4, estimation = $5, status = $6,
+            estimation_overridden = $7, override_reason = $8, previous_estimation = $9, estimation_breakdown = $10,
+            pert_estimate = $11, notes = $12, notes_history = $13,
+            acceptance_criteria = $14, task_type = $15, labels = $16,
+            version = version + 1
+        WHERE id = $1 AND version = $17 AND room_id = $18 AND deleted_at IS NULL
     `
 
+	breakdown, err := encodeBreakdown(task.EstimationBreakdown)
+	if err != nil {
+		return fmt.Errorf("failed to encode estimation breakdown: %w", err)
+	}
+	pert, err := encodePert(task.Pert)
+	if err != nil {
+		return fmt.Errorf("failed to encode PERT estimate: %w", err)
+	}
+	notes, notesHistory, err := encodeNotes(task.Notes, task.NotesHistory)
+	if err != nil {
+		return fmt.Errorf("failed to encode decision log: %w", err)
+	}
+	criteria, err := encodeCriteria(task.AcceptanceCriteria)
+	if err != nil {
+		return fmt.Errorf("failed to encode acceptance criteria: %w", err)
+	}
+
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
@@ -114,7 +425,19 @@ func (r *TaskRepo) Update(ctx context.Context, task *room.Task) error {
 		task.Description,
 		task.TrackerLink,
 		task.Estimation,
-		task.Position,
+		task.Status,
+		task.EstimationOverridden,
+		task.OverrideReason,
+		task.PreviousEstimation,
+		breakdown,
+		pert,
+		notes,
+		notesHistory,
+		criteria,
+		task.Type,
+		pq.Array(labelsOrEmpty(task.Labels)),
+		task.Version,
+		task.RoomID,
 	)
 
 	if err != nil {
@@ -126,21 +449,26 @@ func (r *TaskRepo) Update(ctx context.Context, task *room.Task) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
+		// Either the task is gone or someone saved it since it was loaded
+		var exists bool
+		query := `SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND room_id = $2 AND deleted_at IS NULL)`
+		if err := r.db.QueryRowContext(ctx, query, task.ID, task.RoomID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check task existence: %w", err)
+		}
+		if exists {
+			return room.ErrTaskVersionConflict
+		}
 		return room.ErrTaskNotFound
 	}
 
+	task.Version++
 	return nil
 }
 
-func (r *TaskRepo) Delete(ctx context.Context, id string) error {
-	// First get the task to know its position and room
-	task, err := r.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	query := `DELETE FROM tasks WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+// Delete only marks the task as deleted; its comments, history and dependencies stay for Restore
+func (r *TaskRepo) Delete(ctx context.Context, roomID, id string) error {
+	query := `UPDATE tasks SET deleted_at = NOW() WHERE id = $1 AND room_id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, roomID)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -153,67 +481,133 @@ func (r *TaskRepo) Delete(ctx context.Context, id string) error {
 		return room.ErrTaskNotFound
 	}
 
-	// Reorder remaining tasks to fill the gap
-	reorderQuery := `
-        UPDATE tasks
-        SET position = position - 1
-        WHERE room_id = $1 AND position > $2
+	return nil
+}
+
+func (r *TaskRepo) Restore(ctx context.Context, task *room.Task) error {
+	query := `
+        UPDATE tasks SET deleted_at = NULL, rank = $3, parent_id = $4
+        WHERE id = $1 AND room_id = $2 AND deleted_at IS NOT NULL
     `
-	_, err = r.db.ExecContext(ctx, reorderQuery, task.RoomID, task.Position)
+
+	result, err := r.db.ExecContext(ctx, query, task.ID, task.RoomID, task.Rank, nullableParent(task.ParentID))
 	if err != nil {
-		return fmt.Errorf("failed to reorder tasks after deletion: %w", err)
+		if isRankTaken(err) {
+			return room.ErrTaskRankTaken
+		}
+		return fmt.Errorf("failed to restore task: %w", err)
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return room.ErrTaskNotFound
+	}
 	return nil
 }
 
-func (r *TaskRepo) UpdatePositions(ctx context.Context, tasks []*room.Task) error {
+func (r *TaskRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `DELETE FROM tasks WHERE deleted_at < $1`
+	result, err := r.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return purged, nil
+}
+
+func (r *TaskRepo) UpdateRanks(ctx context.Context, roomID string, tasks []*room.Task) error {
 	if len(tasks) == 0 {
 		return nil
 	}
 
+	ctx, span := startTxSpan(ctx, "update_ranks")
+	defer span.End()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
+		recordQueryError(span, err)
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	query := `UPDATE tasks SET position = $1 WHERE id = $2`
+	// Ranks may swap between siblings, only check they are unique once all are written
+	if _, err := tx.ExecContext(ctx, `SET CONSTRAINTS unique_sibling_rank DEFERRED`); err != nil {
+		recordQueryError(span, err)
+		return fmt.Errorf("failed to defer rank constraint: %w", err)
+	}
+
+	query := `UPDATE tasks SET rank = $1 WHERE id = $2 AND room_id = $3 AND deleted_at IS NULL`
 
 	for _, task := range tasks {
-		_, err := tx.ExecContext(ctx, query, task.Position, task.ID)
+		result, err := tx.ExecContext(ctx, query, task.Rank, task.ID, roomID)
 		if err != nil {
-			return fmt.Errorf("failed to update task position: %w", err)
+			recordQueryError(span, err)
+			return fmt.Errorf("failed to update task rank: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return room.ErrTaskNotFound
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
+		recordQueryError(span, err)
+		if isRankTaken(err) {
+			return room.ErrTaskRankTaken
+		}
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (r *TaskRepo) GetNextUnestimatedTask(ctx context.Context, roomID string) (*room.Task, error) {
-	query := `
-        SELECT id, room_id, headline, description, tracker_link, estimation, position
-        FROM tasks
-        WHERE room_id = $1 AND (estimation IS NULL OR estimation = '' OR estimation = '?')
-        ORDER BY position ASC
+func (r *TaskRepo) Move(ctx context.Context, task *room.Task) error {
+	query := `UPDATE tasks SET rank = $2, parent_id = $3 WHERE id = $1 AND room_id = $4 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, task.ID, task.Rank, nullableParent(task.ParentID), task.RoomID)
+	if err != nil {
+		if isRankTaken(err) {
+			return room.ErrTaskRankTaken
+		}
+		return fmt.Errorf("failed to move task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return room.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (r *TaskRepo) GetNextUnestimatedTask(ctx context.Context, roomID string, filter room.TaskFilter) (*room.Task, error) {
+	query := withTaskOrder(`$1`) + `
+        SELECT ` + taskSelectColumns + `
+        FROM tasks JOIN task_order USING (id)
+        WHERE status IN ('pending', 'in_discussion')
+            AND NOT EXISTS (SELECT 1 FROM tasks sub WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL)
+            AND (cardinality($2::text[]) = 0 OR labels && $2::text[])
+            AND (cardinality($3::text[]) = 0 OR task_type = ANY($3::text[]))
+            AND (cardinality($4::text[]) = 0 OR status = ANY($4::text[]))
+        ORDER BY task_order.position ASC
         LIMIT 1
     `
 
-	var task room.Task
-	err := r.db.QueryRowContext(ctx, query, roomID).Scan(
-		&task.ID,
-		&task.RoomID,
-		&task.Headline,
-		&task.Description,
-		&task.TrackerLink,
-		&task.Estimation,
-		&task.Position,
-	)
-
+	labels, types, statuses := filterArgs(filter)
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, roomID, labels, types, statuses))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, room.ErrTaskNotFound
@@ -221,5 +615,66 @@ func (r *TaskRepo) GetNextUnestimatedTask(ctx context.Context, roomID string) (*
 		return nil, fmt.Errorf("failed to get next unestimated task: %w", err)
 	}
 
-	return &task, nil
+	return task, nil
+}
+
+func (r *TaskRepo) AddDependency(ctx context.Context, roomID, taskID, dependsOnID string) error {
+	// Both tasks must be in the room and not deleted, otherwise nothing is inserted
+	query := `
+        INSERT INTO task_dependencies (task_id, depends_on_id)
+        SELECT task.id, dependency.id
+        FROM tasks task JOIN tasks dependency ON dependency.room_id = task.room_id
+        WHERE task.id = $1 AND dependency.id = $2 AND task.room_id = $3
+            AND task.deleted_at IS NULL AND dependency.deleted_at IS NULL
+        ON CONFLICT (task_id, depends_on_id) DO NOTHING
+    `
+
+	result, err := r.db.ExecContext(ctx, query, taskID, dependsOnID, roomID)
+	if err != nil {
+		return fmt.Errorf("failed to add task dependency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		// Either the dependency already exists or one of the tasks is not in the room
+		var exists bool
+		query := `
+            SELECT EXISTS(
+                SELECT 1 FROM task_dependencies JOIN tasks ON tasks.id = task_dependencies.task_id
+                WHERE task_id = $1 AND depends_on_id = $2 AND room_id = $3 AND deleted_at IS NULL
+            )
+        `
+		if err := r.db.QueryRowContext(ctx, query, taskID, dependsOnID, roomID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check task dependency: %w", err)
+		}
+		if !exists {
+			return room.ErrTaskNotFound
+		}
+	}
+	return nil
+}
+
+func (r *TaskRepo) RemoveDependency(ctx context.Context, roomID, taskID, dependsOnID string) error {
+	query := `
+        DELETE FROM task_dependencies USING tasks
+        WHERE tasks.id = task_dependencies.task_id
+            AND task_id = $1 AND depends_on_id = $2 AND room_id = $3 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, taskID, dependsOnID, roomID)
+	if err != nil {
+		return fmt.Errorf("failed to remove task dependency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return room.ErrTaskDependencyNotFound
+	}
+	return nil
 }
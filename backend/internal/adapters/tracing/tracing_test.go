@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vitaly-stepin/agile_party/internal/adapters/config"
+)
+
+func TestInit_Disabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), &config.TracingConfig{Enabled: false})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("expected no-op shutdown, got %v", err)
+	}
+}
+
+func TestNewInMemoryProvider_RecordsSpans(t *testing.T) {
+	tp, exporter := NewInMemoryProvider()
+	defer tp.Shutdown(context.Background())
+
+	_, span := tp.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name != "operation" {
+		t.Errorf("expected span name 'operation', got '%s'", spans[0].Name)
+	}
+}
@@ -0,0 +1,172 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+const (
+	DefaultCommentPageSize = 50
+	MaxCommentPageSize     = 100
+)
+
+// CommentService manages the discussion thread of each task
+type CommentService struct {
+	commentRepo ports.TaskCommentRepo
+	taskRepo    ports.TaskRepo
+}
+
+func NewCommentService(commentRepo ports.TaskCommentRepo, taskRepo ports.TaskRepo) *CommentService {
+	return &CommentService{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+	}
+}
+
+// AddComment posts a comment on a task of the room under the author's current name
+func (s *CommentService) AddComment(ctx context.Context, roomID, taskID, authorID, authorName, body string) (*dto.TaskCommentResp, error) {
+	ctx, span := startSpan(ctx, "CommentService.AddComment",
+		roomIDKey.String(roomID), taskIDKey.String(taskID), userIDKey.String(authorID))
+	defer span.End()
+
+	if err := s.checkRoomTask(ctx, roomID, taskID); err != nil {
+		return nil, err
+	}
+
+	comment, err := room.NewTaskComment(taskID, authorID, authorName, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to persist comment: %w", err)
+	}
+
+	return dto.FromDomainTaskComment(comment), nil
+}
+
+// EditComment replaces the body of the author's own comment
+func (s *CommentService) EditComment(ctx context.Context, roomID, commentID, authorID, body string) (*dto.TaskCommentResp, error) {
+	ctx, span := startSpan(ctx, "CommentService.EditComment", roomIDKey.String(roomID), userIDKey.String(authorID))
+	defer span.End()
+
+	comment, err := s.roomComment(ctx, roomID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := comment.Edit(authorID, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return dto.FromDomainTaskComment(comment), nil
+}
+
+// DeleteComment removes the author's own comment and returns it so the deletion can be announced
+func (s *CommentService) DeleteComment(ctx context.Context, roomID, commentID, authorID string) (*dto.TaskCommentResp, error) {
+	ctx, span := startSpan(ctx, "CommentService.DeleteComment", roomIDKey.String(roomID), userIDKey.String(authorID))
+	defer span.End()
+
+	comment, err := s.roomComment(ctx, roomID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := comment.CheckAuthor(authorID); err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
+		return nil, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return dto.FromDomainTaskComment(comment), nil
+}
+
+// ListComments returns a page of a task's thread, oldest first. A non-positive limit
+// uses the default page size and limits above MaxCommentPageSize are capped.
+func (s *CommentService) ListComments(ctx context.Context, roomID, taskID string, limit, offset int) (*dto.TaskCommentPageResp, error) {
+	ctx, span := startSpan(ctx, "CommentService.ListComments", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	if limit <= 0 {
+		limit = DefaultCommentPageSize
+	}
+	if limit > MaxCommentPageSize {
+		limit = MaxCommentPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := s.checkRoomTask(ctx, roomID, taskID); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := s.commentRepo.ListByTask(ctx, taskID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	return &dto.TaskCommentPageResp{
+		Comments: dto.FromDomainTaskComments(comments),
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+// GetThread returns a task's whole thread, oldest first, to go along with its estimation history
+func (s *CommentService) GetThread(ctx context.Context, roomID, taskID string) ([]*dto.TaskCommentResp, error) {
+	ctx, span := startSpan(ctx, "CommentService.GetThread", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	if err := s.checkRoomTask(ctx, roomID, taskID); err != nil {
+		return nil, err
+	}
+
+	var thread []*room.TaskComment
+	for {
+		comments, total, err := s.commentRepo.ListByTask(ctx, taskID, MaxCommentPageSize, len(thread))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comments: %w", err)
+		}
+		thread = append(thread, comments...)
+		if len(comments) == 0 || len(thread) >= total {
+			break
+		}
+	}
+
+	return dto.FromDomainTaskComments(thread), nil
+}
+
+// checkRoomTask reports ErrTaskNotFound for tasks of another room
+func (s *CommentService) checkRoomTask(ctx context.Context, roomID, taskID string) error {
+	_, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	return err
+}
+
+// roomComment loads a comment and hides comments that belong to another room's tasks
+func (s *CommentService) roomComment(ctx context.Context, roomID, commentID string) (*room.TaskComment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRoomTask(ctx, roomID, comment.TaskID); err != nil {
+		if errors.Is(err, room.ErrTaskNotFound) {
+			return nil, room.ErrTaskCommentNotFound
+		}
+		return nil, err
+	}
+	return comment, nil
+}
@@ -0,0 +1,200 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+type mockCommentRepo struct {
+	comments map[string]*room.TaskComment
+}
+
+func newMockCommentRepo() *mockCommentRepo {
+	return &mockCommentRepo{comments: make(map[string]*room.TaskComment)}
+}
+
+func (m *mockCommentRepo) Create(ctx context.Context, comment *room.TaskComment) error {
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *mockCommentRepo) GetByID(ctx context.Context, id string) (*room.TaskComment, error) {
+	comment, ok := m.comments[id]
+	if !ok {
+		return nil, room.ErrTaskCommentNotFound
+	}
+	commentCopy := *comment
+	return &commentCopy, nil
+}
+
+func (m *mockCommentRepo) Update(ctx context.Context, comment *room.TaskComment) error {
+	if _, ok := m.comments[comment.ID]; !ok {
+		return room.ErrTaskCommentNotFound
+	}
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *mockCommentRepo) Delete(ctx context.Context, id string) error {
+	if _, ok := m.comments[id]; !ok {
+		return room.ErrTaskCommentNotFound
+	}
+	delete(m.comments, id)
+	return nil
+}
+
+func (m *mockCommentRepo) ListByTask(ctx context.Context, taskID string, limit, offset int) ([]*room.TaskComment, int, error) {
+	var comments []*room.TaskComment
+	for _, comment := range m.comments {
+		if comment.TaskID == taskID {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+
+	total := len(comments)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return comments[offset:end], total, nil
+}
+
+func newCommentTestService(t *testing.T) (*CommentService, *room.Task) {
+	t.Helper()
+	task, err := room.NewTask("room1", "Login page", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewCommentService(newMockCommentRepo(), newMockTaskRepo(task)), task
+}
+
+func TestCommentService_AddComment(t *testing.T) {
+	service, task := newCommentTestService(t)
+	ctx := context.Background()
+
+	comment, err := service.AddComment(ctx, "room1", task.ID, "user1", "Alice", "Needs design review")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.AuthorName != "Alice" || comment.Body != "Needs design review" {
+		t.Errorf("expected Alice's comment, got %+v", comment)
+	}
+
+	if _, err := service.AddComment(ctx, "room2", task.ID, "user1", "Alice", "Hi"); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for another room's task, got %v", err)
+	}
+	if _, err := service.AddComment(ctx, "room1", task.ID, "user1", "Alice", ""); !errors.Is(err, room.ErrEmptyTaskComment) {
+		t.Errorf("expected ErrEmptyTaskComment, got %v", err)
+	}
+}
+
+func TestCommentService_EditAndDeleteComment(t *testing.T) {
+	service, task := newCommentTestService(t)
+	ctx := context.Background()
+
+	comment, err := service.AddComment(ctx, "room1", task.ID, "user1", "Alice", "first")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.EditComment(ctx, "room1", comment.ID, "user2", "hijack"); !errors.Is(err, room.ErrNotCommentAuthor) {
+		t.Errorf("expected ErrNotCommentAuthor, got %v", err)
+	}
+	if _, err := service.EditComment(ctx, "room2", comment.ID, "user1", "elsewhere"); !errors.Is(err, room.ErrTaskCommentNotFound) {
+		t.Errorf("expected ErrTaskCommentNotFound from another room, got %v", err)
+	}
+
+	edited, err := service.EditComment(ctx, "room1", comment.ID, "user1", "second")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if edited.Body != "second" {
+		t.Errorf("expected body second, got %q", edited.Body)
+	}
+
+	if _, err := service.DeleteComment(ctx, "room1", comment.ID, "user2"); !errors.Is(err, room.ErrNotCommentAuthor) {
+		t.Errorf("expected ErrNotCommentAuthor, got %v", err)
+	}
+	deleted, err := service.DeleteComment(ctx, "room1", comment.ID, "user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted.TaskID != task.ID {
+		t.Errorf("expected deleted comment of task %s, got %s", task.ID, deleted.TaskID)
+	}
+
+	page, err := service.ListComments(ctx, "room1", task.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 0 {
+		t.Errorf("expected empty thread, got %d comments", page.Total)
+	}
+}
+
+func TestCommentService_ListComments_Paginates(t *testing.T) {
+	service, task := newCommentTestService(t)
+	ctx := context.Background()
+
+	for _, body := range []string{"one", "two", "three"} {
+		if _, err := service.AddComment(ctx, "room1", task.ID, "user1", "Alice", body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	page, err := service.ListComments(ctx, "room1", task.ID, 2, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 {
+		t.Errorf("expected total 3, got %d", page.Total)
+	}
+	if len(page.Comments) != 1 {
+		t.Fatalf("expected 1 comment on the second page, got %d", len(page.Comments))
+	}
+
+	page, err = service.ListComments(ctx, "room1", task.ID, 1000, -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Limit != MaxCommentPageSize || page.Offset != 0 {
+		t.Errorf("expected limit %d and offset 0, got %d and %d", MaxCommentPageSize, page.Limit, page.Offset)
+	}
+
+	if _, err := service.ListComments(ctx, "room2", task.ID, 10, 0); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for another room, got %v", err)
+	}
+}
+
+func TestCommentService_GetThread_ReadsEveryPage(t *testing.T) {
+	service, task := newCommentTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < MaxCommentPageSize+1; i++ {
+		if _, err := service.AddComment(ctx, "room1", task.ID, "user1", "Alice", "point"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	thread, err := service.GetThread(ctx, "room1", task.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(thread) != MaxCommentPageSize+1 {
+		t.Errorf("expected %d comments, got %d", MaxCommentPageSize+1, len(thread))
+	}
+
+	if _, err := service.GetThread(ctx, "room2", task.ID); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for another room, got %v", err)
+	}
+}
@@ -7,17 +7,45 @@ import (
 )
 
 type NewRoomReq struct {
-	Name         string `json:"name"`
-	VotingSystem string `json:"voting_system"`
-	AutoReveal   bool   `json:"auto_reveal"`
+	Name         string         `json:"name"`
+	VotingSystem string         `json:"voting_system"`
+	AutoReveal   bool           `json:"auto_reveal"`
+	Dimensions   []DimensionDTO `json:"dimensions,omitempty"`
+	Combination  string         `json:"combination,omitempty"`
+	Mode         string         `json:"estimation_mode,omitempty"`
+
+	AnonymousVotes   bool   `json:"anonymous_votes"`
+	VoteChangePolicy string `json:"vote_change_policy,omitempty"` // "tracked" (default) or "locked"
+
+	RequireAcceptanceCriteria bool `json:"require_acceptance_criteria"`
+
+	TaskFilter *TaskFilterReq `json:"task_filter,omitempty"` // saved filter for next-task selection
+}
+
+// DimensionDTO describes one estimation axis of a multi-dimensional room
+type DimensionDTO struct {
+	Key          string  `json:"key"`
+	Name         string  `json:"name"`
+	VotingSystem string  `json:"voting_system,omitempty"`
+	Weight       float64 `json:"weight,omitempty"`
 }
 
 type NewRoomResp struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	VotingSystem string    `json:"voting_system"`
-	AutoReveal   bool      `json:"auto_reveal"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	VotingSystem string         `json:"voting_system"`
+	AutoReveal   bool           `json:"auto_reveal"`
+	Dimensions   []DimensionDTO `json:"dimensions,omitempty"`
+	Combination  string         `json:"combination,omitempty"`
+	Mode         string         `json:"estimation_mode"`
+	CreatedAt    time.Time      `json:"created_at"`
+
+	AnonymousVotes   bool   `json:"anonymous_votes"`
+	VoteChangePolicy string `json:"vote_change_policy"`
+
+	RequireAcceptanceCriteria bool `json:"require_acceptance_criteria"`
+
+	TaskFilter *TaskFilterReq `json:"task_filter,omitempty"` // saved filter for next-task selection
 }
 
 type UpdateRoomReq struct {
@@ -27,12 +55,22 @@ type UpdateRoomReq struct {
 }
 
 type RoomResp struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	VotingSystem string    `json:"voting_system"`
-	AutoReveal   bool      `json:"auto_reveal"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	VotingSystem string         `json:"voting_system"`
+	AutoReveal   bool           `json:"auto_reveal"`
+	Dimensions   []DimensionDTO `json:"dimensions,omitempty"`
+	Combination  string         `json:"combination,omitempty"`
+	Mode         string         `json:"estimation_mode"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+
+	AnonymousVotes   bool   `json:"anonymous_votes"`
+	VoteChangePolicy string `json:"vote_change_policy"`
+
+	RequireAcceptanceCriteria bool `json:"require_acceptance_criteria"`
+
+	TaskFilter *TaskFilterReq `json:"task_filter,omitempty"` // saved filter for next-task selection
 }
 
 func FromDomainRoom(r *room.Room) *RoomResp { // consider more self explaining naming
@@ -44,8 +82,18 @@ func FromDomainRoom(r *room.Room) *RoomResp { // consider more self explaining n
 		Name:         r.Name,
 		VotingSystem: string(r.VotingSystem),
 		AutoReveal:   r.AutoReveal,
+		Dimensions:   FromDomainDimensions(r.Dimensions),
+		Combination:  string(r.Combination),
+		Mode:         string(r.Mode),
 		CreatedAt:    r.CreatedAt,
 		UpdatedAt:    r.UpdatedAt,
+
+		AnonymousVotes:   r.AnonymousVotes,
+		VoteChangePolicy: string(r.VoteChangePolicy),
+
+		RequireAcceptanceCriteria: r.RequireAcceptanceCriteria,
+
+		TaskFilter: fromDomainRoomTaskFilter(r.TaskFilter),
 	}
 }
 
@@ -58,6 +106,58 @@ func FromDomainRoomForCreate(r *room.Room) *NewRoomResp { // consider more self
 		Name:         r.Name,
 		VotingSystem: string(r.VotingSystem),
 		AutoReveal:   r.AutoReveal,
+		Dimensions:   FromDomainDimensions(r.Dimensions),
+		Combination:  string(r.Combination),
+		Mode:         string(r.Mode),
 		CreatedAt:    r.CreatedAt,
+
+		AnonymousVotes:   r.AnonymousVotes,
+		VoteChangePolicy: string(r.VoteChangePolicy),
+
+		RequireAcceptanceCriteria: r.RequireAcceptanceCriteria,
+
+		TaskFilter: fromDomainRoomTaskFilter(r.TaskFilter),
+	}
+}
+
+func fromDomainRoomTaskFilter(filter room.TaskFilter) *TaskFilterReq {
+	if filter.IsEmpty() {
+		return nil
+	}
+	resp := FromDomainTaskFilter(filter)
+	return &resp
+}
+
+func FromDomainDimensions(dimensions []room.EstimationDimension) []DimensionDTO {
+	if len(dimensions) == 0 {
+		return nil
+	}
+
+	result := make([]DimensionDTO, len(dimensions))
+	for i, dimension := range dimensions {
+		result[i] = DimensionDTO{
+			Key:          dimension.Key,
+			Name:         dimension.Name,
+			VotingSystem: string(dimension.VotingSystem),
+			Weight:       dimension.Weight,
+		}
+	}
+	return result
+}
+
+func ToDomainDimensions(dimensions []DimensionDTO) []room.EstimationDimension {
+	if len(dimensions) == 0 {
+		return nil
+	}
+
+	result := make([]room.EstimationDimension, len(dimensions))
+	for i, dimension := range dimensions {
+		result[i] = room.EstimationDimension{
+			Key:          dimension.Key,
+			Name:         dimension.Name,
+			VotingSystem: room.VotingSystem(dimension.VotingSystem),
+			Weight:       dimension.Weight,
+		}
 	}
+	return result
 }
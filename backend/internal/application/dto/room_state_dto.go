@@ -6,40 +6,66 @@ import (
 )
 
 type RoomStateResp struct {
-	RoomID          string     `json:"roomId"`
-	RoomName        string     `json:"roomName"`
-	Users           []UserResp `json:"users"`
-	Votes           []VoteResp `json:"votes"`
-	IsRevealed      bool       `json:"isRevealed"`
-	TaskDescription string     `json:"taskDescription"`
-	Average         *float64   `json:"average,omitempty"`
+	RoomID          string      `json:"roomId"`
+	RoomName        string      `json:"roomName"`
+	Users           []UserResp  `json:"users"`
+	Votes           []VoteResp  `json:"votes"`
+	IsRevealed      bool        `json:"isRevealed"`
+	TaskDescription string      `json:"taskDescription"`
+	Average         *float64    `json:"average,omitempty"`
+	FacilitatorID   string      `json:"facilitatorId"`
+	LastChange      *ChangeResp `json:"lastChange,omitempty"`
 }
 
-func FromDomainRoomState(state *ports.LiveRoomState, votingSystem room.VotingSystem) *RoomStateResp {
+// FromDomainRoomState projects the live state; in anonymous rooms votes carry no user attribution
+func FromDomainRoomState(state *ports.LiveRoomState, settings room.RoomSettings) *RoomStateResp {
 	if state == nil {
 		return nil
 	}
 
+	hands := make(map[string]RaisedHandResp, len(state.RaisedHands))
+	for _, hand := range FromDomainRaisedHands(state.RaisedHands) {
+		hands[hand.UserID] = hand
+	}
+
 	users := make([]UserResp, 0, len(state.Users))
 	for _, user := range state.Users {
-		users = append(users, *FromDomainUser(user))
+		resp := *FromDomainUser(user)
+		if hand, ok := hands[user.ID]; ok {
+			resp.RaisedHand = hand.Signal
+			resp.HandPosition = hand.Position
+		}
+		users = append(users, resp)
 	}
 
-	votes := make([]VoteResp, 0, len(state.Votes))
-	for userID, voteValue := range state.Votes {
-		if user, ok := state.Users[userID]; ok {
-			votes = append(votes, VoteResp{
-				UserID:   userID,
-				UserName: user.Name,
-				Value:    voteValue,
-			})
+	var votes []VoteResp
+	if settings.AnonymousVotes {
+		votes = anonymousVotes(state)
+	} else {
+		votes = make([]VoteResp, 0, len(state.Votes))
+		for userID, voteValue := range state.Votes {
+			if user, ok := state.Users[userID]; ok {
+				vote := VoteResp{
+					UserID:     userID,
+					UserName:   user.Name,
+					Value:      voteValue,
+					Dimensions: state.DimensionVotes[userID],
+				}
+				if estimate, ok := state.ThreePointVotes[userID]; ok {
+					vote.ThreePoint = FromDomainThreePoint(estimate)
+				}
+				if state.IsRevealed {
+					vote.Comment = state.Comments[userID]
+				}
+				votes = append(votes, vote)
+			}
 		}
 	}
 
 	var average *float64
 	if state.IsRevealed && len(state.Votes) > 0 {
 		estimationService := room.NewEstimationService()
-		avg, err := estimationService.CalculateAverage(state.Votes, votingSystem)
+		avg, err := estimationService.CalculateAverage(state.Votes, settings.VotingSystem)
 		if err == nil && avg >= 0 {
 			average = &avg
 		}
@@ -53,5 +79,33 @@ func FromDomainRoomState(state *ports.LiveRoomState, votingSystem room.VotingSys
 		IsRevealed:      state.IsRevealed,
 		TaskDescription: state.TaskDescription,
 		Average:         average,
+		FacilitatorID:   state.FacilitatorID,
+		LastChange:      FromDomainChange(state.LastChange),
+	}
+}
+
+// anonymousVotes lists the values of the users still in the room, sorted so that
+// their order does not hint at who voted what; comments follow their values once revealed
+func anonymousVotes(state *ports.LiveRoomState) []VoteResp {
+	present := make(map[string]string, len(state.Votes))
+	for userID, voteValue := range state.Votes {
+		if _, ok := state.Users[userID]; ok {
+			present[userID] = voteValue
+		}
+	}
+
+	var comments map[string]string
+	if state.IsRevealed {
+		comments = state.Comments
+	}
+	values, texts := commentedValues(present, comments)
+
+	votes := make([]VoteResp, len(values))
+	for i, value := range values {
+		votes[i] = VoteResp{Value: value}
+		if texts != nil {
+			votes[i].Comment = texts[i]
+		}
 	}
+	return votes
 }
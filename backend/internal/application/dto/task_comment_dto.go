@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+type TaskCommentResp struct {
+	ID         string    `json:"id"`
+	TaskID     string    `json:"taskId"`
+	AuthorID   string    `json:"authorId"`
+	AuthorName string    `json:"authorName"`
+	Body       string    `json:"body"`
+	Edited     bool      `json:"edited,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TaskCommentPageResp is one page of a task's thread, oldest first
+type TaskCommentPageResp struct {
+	Comments []*TaskCommentResp `json:"comments"`
+	Total    int                `json:"total"`
+	Limit    int                `json:"limit"`
+	Offset   int                `json:"offset"`
+}
+
+func FromDomainTaskComment(comment *room.TaskComment) *TaskCommentResp {
+	return &TaskCommentResp{
+		ID:         comment.ID,
+		TaskID:     comment.TaskID,
+		AuthorID:   comment.AuthorID,
+		AuthorName: comment.AuthorName,
+		Body:       comment.Body,
+		Edited:     comment.IsEdited(),
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+}
+
+func FromDomainTaskComments(comments []*room.TaskComment) []*TaskCommentResp {
+	result := make([]*TaskCommentResp, len(comments))
+	for i, comment := range comments {
+		result[i] = FromDomainTaskComment(comment)
+	}
+	return result
+}
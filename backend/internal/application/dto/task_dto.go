@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
@@ -11,25 +13,143 @@ type TaskResp struct {
 	Description string `json:"description,omitempty"`
 	TrackerLink string `json:"trackerLink,omitempty"`
 	Estimation  string `json:"estimation,omitempty"`
+	Status      string `json:"status"`
 	Position    int    `json:"position"`
+	Version     int    `json:"version"` // send it back with edits, see UpdateTaskReq
+
+	ParentID string              `json:"parentId,omitempty"`
+	Rollup   *EstimateRollupResp `json:"rollup,omitempty"` // only for epics, summed from their subtasks
+
+	DependsOn []string `json:"dependsOn,omitempty"` // IDs of the tasks blocking this one
+
+	Type   string   `json:"type"`
+	Labels []string `json:"labels,omitempty"`
+
+	EstimationOverridden bool   `json:"estimationOverridden,omitempty"`
+	OverrideReason       string `json:"overrideReason,omitempty"`
+	PreviousEstimation   string `json:"previousEstimation,omitempty"`
+
+	EstimationBreakdown map[string]string `json:"estimationBreakdown,omitempty"`
+	Pert                *PertResp         `json:"pert,omitempty"`
+
+	Notes        *TaskNotesResp  `json:"notes,omitempty"`
+	NotesHistory []TaskNotesResp `json:"notesHistory,omitempty"`
+
+	AcceptanceCriteria []AcceptanceCriterionResp `json:"acceptanceCriteria,omitempty"`
+}
+
+type EstimateRollupResp struct {
+	Points    string `json:"points"`
+	Estimated int    `json:"estimated"`
+	Subtasks  int    `json:"subtasks"`
+}
+
+// TaskNodeResp is a task with its subtasks nested below it
+type TaskNodeResp struct {
+	*TaskResp
+	Subtasks []*TaskNodeResp `json:"subtasks,omitempty"`
+}
+
+type AcceptanceCriterionResp struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+type AddCriterionReq struct {
+	Text string `json:"text"`
+}
+
+// UpdateCriterionReq changes the text and/or the checked state; omitted fields are kept
+type UpdateCriterionReq struct {
+	Text *string `json:"text,omitempty"`
+	Done *bool   `json:"done,omitempty"`
+}
+
+type ReorderCriteriaReq struct {
+	CriterionIDs []string `json:"criterionIds"`
+}
+
+// TaskNotesResp is one revision of a task's decision log
+type TaskNotesResp struct {
+	Items     []string  `json:"items"`
+	Author    string    `json:"author,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type CreateTaskReq struct {
-	Headline    string `json:"headline"`
-	Description string `json:"description,omitempty"`
-	TrackerLink string `json:"trackerLink,omitempty"`
+	Headline    string   `json:"headline"`
+	Description string   `json:"description,omitempty"`
+	TrackerLink string   `json:"trackerLink,omitempty"`
+	Type        string   `json:"type,omitempty"` // "story" (default), "bug", "spike" or "chore"
+	Labels      []string `json:"labels,omitempty"`
+	ParentID    string   `json:"parentId,omitempty"` // epic to file the task under
 }
 
+// UpdateTaskReq leaves empty fields unchanged; Labels replaces the labels when not nil.
+// Version is the task version the edit was made on, the edit is rejected if the task moved on since.
 type UpdateTaskReq struct {
-	Headline    string `json:"headline,omitempty"`
-	Description string `json:"description,omitempty"`
-	TrackerLink string `json:"trackerLink,omitempty"`
+	Version     *int      `json:"version"`
+	Headline    string    `json:"headline,omitempty"`
+	Description string    `json:"description,omitempty"`
+	TrackerLink string    `json:"trackerLink,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
+}
+
+// TaskFilterReq narrows the backlog to some labels, types and statuses; empty fields match everything
+type TaskFilterReq struct {
+	Labels   []string `json:"labels,omitempty"`
+	Types    []string `json:"types,omitempty"`
+	Statuses []string `json:"statuses,omitempty"`
+}
+
+func (r TaskFilterReq) ToDomain() (room.TaskFilter, error) {
+	return room.NewTaskFilter(r.Labels, r.Types, r.Statuses)
+}
+
+func FromDomainTaskFilter(filter room.TaskFilter) TaskFilterReq {
+	resp := TaskFilterReq{Labels: filter.Labels}
+	for _, t := range filter.Types {
+		resp.Types = append(resp.Types, string(t))
+	}
+	for _, s := range filter.Statuses {
+		resp.Statuses = append(resp.Statuses, string(s))
+	}
+	return resp
+}
+
+// AcceptEstimateReq finalizes a round. TaskID may be empty to target the next open task.
+type AcceptEstimateReq struct {
+	TaskID    string `json:"taskId,omitempty"`
+	Value     string `json:"value"`
+	Suggested string `json:"suggested,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+
+	ByFacilitator bool `json:"-"` // set from the sender's role, only the facilitator may override
+
+	Participants []string `json:"participants,omitempty"` // names of the users who voted
+
+	Breakdown map[string]string `json:"breakdown,omitempty"` // dimension key -> accepted card
+	Pert      *PertResp         `json:"pert,omitempty"`      // revealed three-point result
+
+	Comments []VoteCommentResp `json:"comments,omitempty"` // rationale left with the round's votes
+	Changes  []VoteChangeResp  `json:"changes,omitempty"`  // votes changed after the reveal, by user ID
+
+	VoterNames map[string]string `json:"voterNames,omitempty"` // user ID -> name of each voter in Changes
 }
 
 type ReorderTasksReq struct {
 	TaskIDs []string `json:"taskIds"`
 }
 
+// MoveTaskReq places a task right before or right after another task; exactly one of the two is set
+type MoveTaskReq struct {
+	TaskID   string `json:"taskId"`
+	BeforeID string `json:"beforeId,omitempty"`
+	AfterID  string `json:"afterId,omitempty"`
+}
+
 func FromDomainTask(task *room.Task) *TaskResp {
 	if task == nil {
 		return nil
@@ -42,10 +162,72 @@ func FromDomainTask(task *room.Task) *TaskResp {
 		Description: task.Description,
 		TrackerLink: task.TrackerLink,
 		Estimation:  task.Estimation,
+		Status:      string(task.Status),
 		Position:    task.Position,
+		Version:     task.Version,
+
+		ParentID: task.ParentID,
+
+		DependsOn: task.DependsOn,
+
+		Type:   string(task.Type),
+		Labels: task.Labels,
+
+		EstimationOverridden: task.EstimationOverridden,
+		OverrideReason:       task.OverrideReason,
+		PreviousEstimation:   task.PreviousEstimation,
+
+		EstimationBreakdown: task.EstimationBreakdown,
+		Pert:                FromDomainPert(task.Pert),
+
+		Notes:        fromDomainTaskNotes(task.Notes),
+		NotesHistory: fromDomainNotesHistory(task.NotesHistory),
+
+		AcceptanceCriteria: FromDomainCriteria(task.AcceptanceCriteria),
 	}
 }
 
+func FromDomainCriteria(criteria []room.AcceptanceCriterion) []AcceptanceCriterionResp {
+	if len(criteria) == 0 {
+		return nil
+	}
+	result := make([]AcceptanceCriterionResp, len(criteria))
+	for i, criterion := range criteria {
+		result[i] = AcceptanceCriterionResp{
+			ID:   criterion.ID,
+			Text: criterion.Text,
+			Done: criterion.Done,
+		}
+	}
+	return result
+}
+
+func fromDomainTaskNotes(notes *room.TaskNotes) *TaskNotesResp {
+	if notes == nil {
+		return nil
+	}
+	items := notes.Items
+	if items == nil {
+		items = []string{}
+	}
+	return &TaskNotesResp{
+		Items:     items,
+		Author:    notes.Author,
+		UpdatedAt: notes.UpdatedAt,
+	}
+}
+
+func fromDomainNotesHistory(history []room.TaskNotes) []TaskNotesResp {
+	if len(history) == 0 {
+		return nil
+	}
+	result := make([]TaskNotesResp, len(history))
+	for i := range history {
+		result[i] = *fromDomainTaskNotes(&history[i])
+	}
+	return result
+}
+
 func FromDomainTasks(tasks []*room.Task) []*TaskResp {
 	if tasks == nil {
 		return nil
@@ -57,3 +239,81 @@ func FromDomainTasks(tasks []*room.Task) []*TaskResp {
 	}
 	return result
 }
+
+// FromDomainTasksWithRollups converts tasks, adding the roll-up of every epic in tree
+func FromDomainTasksWithRollups(tasks []*room.Task, tree *room.TaskTree) []*TaskResp {
+	result := FromDomainTasks(tasks)
+	for _, resp := range result {
+		if rollup, ok := tree.Rollup(resp.ID); ok {
+			resp.Rollup = FromDomainRollup(rollup)
+		}
+	}
+	return result
+}
+
+func FromDomainRollup(rollup room.EstimateRollup) *EstimateRollupResp {
+	return &EstimateRollupResp{
+		Points:    rollup.String(),
+		Estimated: rollup.Estimated,
+		Subtasks:  rollup.Subtasks,
+	}
+}
+
+// FromDomainTaskTree nests the room's tasks under their epics, top-level tasks first in position order
+func FromDomainTaskTree(tree *room.TaskTree) []*TaskNodeResp {
+	return taskNodes(tree, tree.TopLevel())
+}
+
+func taskNodes(tree *room.TaskTree, tasks []*room.Task) []*TaskNodeResp {
+	if len(tasks) == 0 {
+		return nil
+	}
+	nodes := make([]*TaskNodeResp, len(tasks))
+	for i, task := range tasks {
+		resp := FromDomainTask(task)
+		if rollup, ok := tree.Rollup(task.ID); ok {
+			resp.Rollup = FromDomainRollup(rollup)
+		}
+		nodes[i] = &TaskNodeResp{
+			TaskResp: resp,
+			Subtasks: taskNodes(tree, tree.Subtasks(task.ID)),
+		}
+	}
+	return nodes
+}
+
+type EstimationRecordResp struct {
+	ID           string            `json:"id"`
+	TaskID       string            `json:"taskId"`
+	Value        string            `json:"value"`
+	Source       string            `json:"source"`
+	Reason       string            `json:"reason,omitempty"`
+	Participants []string          `json:"participants"`
+	Notes        []string          `json:"notes,omitempty"` // the decision log the estimate was accepted with
+	Comments     []VoteCommentResp `json:"comments,omitempty"`
+	Changes      []VoteChangeResp  `json:"changes,omitempty"`
+	CreatedAt    time.Time         `json:"createdAt"`
+}
+
+func FromDomainEstimationRecords(records []*room.EstimationRecord) []*EstimationRecordResp {
+	result := make([]*EstimationRecordResp, len(records))
+	for i, record := range records {
+		participants := record.Participants
+		if participants == nil {
+			participants = []string{}
+		}
+		result[i] = &EstimationRecordResp{
+			ID:           record.ID,
+			TaskID:       record.TaskID,
+			Value:        record.Value,
+			Source:       string(record.Source),
+			Reason:       record.Reason,
+			Participants: participants,
+			Notes:        record.Notes,
+			Comments:     FromDomainVoteComments(record.Comments),
+			Changes:      FromDomainRecordedVoteChanges(record.Changes, record.VoterNames),
+			CreatedAt:    record.CreatedAt,
+		}
+	}
+	return result
+}
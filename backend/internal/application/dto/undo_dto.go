@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/vitaly-stepin/agile_party/internal/domain/ports"
+
+// ChangeResp names a change that can be undone and who made it
+type ChangeResp struct {
+	Action string `json:"action"`
+	UserID string `json:"userId"`
+}
+
+// UndoResp tells what an undo brought back, so clients know what to resync
+type UndoResp struct {
+	Undone        ChangeResp  `json:"undone"`
+	RestoredTasks bool        `json:"restoredTasks"`        // the backlog changed
+	RestoredRound bool        `json:"restoredRound"`        // votes and active task changed
+	LastChange    *ChangeResp `json:"lastChange,omitempty"` // next change that can be undone
+}
+
+func FromDomainChange(entry *ports.UndoEntry) *ChangeResp {
+	if entry == nil {
+		return nil
+	}
+	return &ChangeResp{
+		Action: string(entry.Action),
+		UserID: entry.UserID,
+	}
+}
@@ -8,6 +8,24 @@ type UserResp struct {
 	Name     string `json:"name"`
 	IsVoted  bool   `json:"isVoted"`
 	IsOnline bool   `json:"isOnline"`
+
+	RaisedHand   string `json:"raisedHand,omitempty"`   // signal of the user's raised hand
+	HandPosition int    `json:"handPosition,omitempty"` // 1-based place in the hand queue
+}
+
+// RaisedHandResp is one entry of the hand queue
+type RaisedHandResp struct {
+	UserID   string `json:"userId"`
+	Signal   string `json:"signal"`
+	Position int    `json:"position"` // 1-based, the facilitator calls on the lowest first
+}
+
+func FromDomainRaisedHands(hands []room.RaisedHand) []RaisedHandResp {
+	result := make([]RaisedHandResp, len(hands))
+	for i, hand := range hands {
+		result[i] = RaisedHandResp{UserID: hand.UserID, Signal: string(hand.Signal), Position: i + 1}
+	}
+	return result
 }
 
 func FromDomainUser(u *room.User) *UserResp {
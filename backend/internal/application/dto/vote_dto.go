@@ -1,21 +1,238 @@
 package dto
 
-import "github.com/vitaly-stepin/agile_party/internal/domain/room"
+import (
+	"sort"
+	"strconv"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
 
 type SubmitVoteReq struct {
-	UserID string `json:"userId"`
-	Value  string `json:"value"`
+	UserID  string            `json:"userId"`
+	Value   string            `json:"value"`
+	Values  map[string]string `json:"values,omitempty"` // dimension key -> vote value, multi-dimensional rooms only
+	Comment string            `json:"comment,omitempty"`
+
+	ThreePoint *ThreePointReq `json:"threePoint,omitempty"` // three-point rooms only
+}
+
+// ThreePointReq carries the three cards of a three-point vote
+type ThreePointReq struct {
+	Optimistic  string `json:"optimistic"`
+	MostLikely  string `json:"mostLikely"`
+	Pessimistic string `json:"pessimistic"`
+}
+
+type ThreePointResp struct {
+	Optimistic  float64 `json:"optimistic"`
+	MostLikely  float64 `json:"mostLikely"`
+	Pessimistic float64 `json:"pessimistic"`
+}
+
+// PertResp is the team's three-point estimate: the mean triple, PERT expected value and standard deviation
+type PertResp struct {
+	ThreePointResp
+	Expected float64 `json:"expected"`
+	StdDev   float64 `json:"stdDev"`
 }
 
+// VoteResp is one vote; UserID and UserName are left empty in anonymous rooms
 type VoteResp struct {
-	UserID   string `json:"userId"`
-	UserName string `json:"userName"`
-	Value    string `json:"value"`
+	UserID     string            `json:"userId,omitempty"`
+	UserName   string            `json:"userName,omitempty"`
+	Value      string            `json:"value"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	ThreePoint *ThreePointResp   `json:"threePoint,omitempty"`
+	Comment    string            `json:"comment,omitempty"` // only once votes are revealed
 }
 
 type RevealVotesResp struct {
-	Votes   map[string]string `json:"votes"`   // userID -> vote value
-	Average *float64          `json:"average"` // nil if no numeric votes
+	Votes      map[string]string     `json:"votes"`               // userID -> vote value
+	Comments   map[string]string     `json:"comments,omitempty"`  // userID -> rationale left with the vote
+	Average    *float64              `json:"average"`             // nil if no numeric votes
+	Suggested  string                `json:"suggested,omitempty"` // deck card proposed as the final estimate
+	Dimensions []DimensionResultResp `json:"dimensions,omitempty"`
+
+	Changes []VoteChangeResp `json:"changes,omitempty"` // votes changed after the reveal, oldest first
+
+	ThreePointVotes map[string]ThreePointResp `json:"threePointVotes,omitempty"` // userID -> triple
+	Pert            *PertResp                 `json:"pert,omitempty"`
+
+	// Set instead of Votes in anonymous rooms: who voted and, separately, the sorted values.
+	// DistributionComments[i] is the comment left with Distribution[i], empty if none.
+	Anonymous            bool     `json:"anonymous,omitempty"`
+	Voters               []string `json:"voters,omitempty"`
+	Distribution         []string `json:"distribution,omitempty"`
+	DistributionComments []string `json:"distributionComments,omitempty"`
+}
+
+// VoteCommentResp is a comment kept in the estimation history; Author is empty for anonymous rounds
+type VoteCommentResp struct {
+	Author  string `json:"author,omitempty"`
+	Value   string `json:"value"`
+	Comment string `json:"comment"`
+}
+
+// VoteChangeResp is a vote changed after the reveal. UserID identifies the voter and Voter
+// names them where a name is shown; both are empty in anonymous rooms.
+type VoteChangeResp struct {
+	UserID string `json:"userId,omitempty"`
+	Voter  string `json:"voter,omitempty"`
+	From   string `json:"from,omitempty"` // empty when the user first voted after the reveal
+	To     string `json:"to"`
+}
+
+// DimensionResultResp is the outcome of one dimension; Suggested values are combined into RevealVotesResp.Suggested
+type DimensionResultResp struct {
+	Key       string            `json:"key"`
+	Name      string            `json:"name"`
+	Votes     map[string]string `json:"votes,omitempty"` // userID -> vote value
+	Average   *float64          `json:"average"`
+	Suggested string            `json:"suggested,omitempty"`
+
+	Distribution []string `json:"distribution,omitempty"` // anonymous rooms only
+}
+
+// Breakdown maps each dimension with a suggestion to its suggested card
+func (r *RevealVotesResp) Breakdown() map[string]string {
+	if len(r.Dimensions) == 0 {
+		return nil
+	}
+
+	breakdown := make(map[string]string, len(r.Dimensions))
+	for _, dimension := range r.Dimensions {
+		if dimension.Suggested != "" {
+			breakdown[dimension.Key] = dimension.Suggested
+		}
+	}
+	return breakdown
+}
+
+// Anonymize drops every link between voters and values. Only the list of
+// voters (already public through the "has voted" status) and the sorted
+// distribution of values remain; aggregated results are kept.
+func (r *RevealVotesResp) Anonymize() {
+	r.Anonymous = true
+	r.Voters, _ = splitVotes(r.Votes)
+	r.Distribution, r.DistributionComments = commentedValues(r.Votes, r.Comments)
+	r.Votes = nil
+	r.Comments = nil
+	for i := range r.Changes {
+		r.Changes[i].UserID = ""
+	}
+	r.ThreePointVotes = nil
+
+	for i := range r.Dimensions {
+		_, r.Dimensions[i].Distribution = splitVotes(r.Dimensions[i].Votes)
+		r.Dimensions[i].Votes = nil
+	}
+}
+
+// VoteComments lists the round's comments with their values, attributed through
+// userNames unless the result is anonymous
+func (r *RevealVotesResp) VoteComments(userNames map[string]string) []VoteCommentResp {
+	var comments []VoteCommentResp
+	if r.Anonymous {
+		for i, comment := range r.DistributionComments {
+			if comment != "" {
+				comments = append(comments, VoteCommentResp{Value: r.Distribution[i], Comment: comment})
+			}
+		}
+		return comments
+	}
+
+	for userID, comment := range r.Comments {
+		comments = append(comments, VoteCommentResp{Author: userNames[userID], Value: r.Votes[userID], Comment: comment})
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].Author < comments[j].Author })
+	return comments
+}
+
+// NamedChanges lists the changes with the voters' names in place of their IDs
+func (r *RevealVotesResp) NamedChanges(userNames map[string]string) []VoteChangeResp {
+	if len(r.Changes) == 0 {
+		return nil
+	}
+
+	changes := make([]VoteChangeResp, len(r.Changes))
+	for i, change := range r.Changes {
+		changes[i] = VoteChangeResp{Voter: userNames[change.UserID], From: change.From, To: change.To}
+	}
+	return changes
+}
+
+// VoterNames maps the users who changed their vote to their names, to be kept with the changes
+func (r *RevealVotesResp) VoterNames(userNames map[string]string) map[string]string {
+	names := make(map[string]string)
+	for _, change := range r.Changes {
+		if change.UserID != "" {
+			names[change.UserID] = userNames[change.UserID]
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// VoterIDs lists the users who voted, whether or not the result is anonymous
+func (r *RevealVotesResp) VoterIDs() []string {
+	if r.Anonymous {
+		return r.Voters
+	}
+	voters, _ := splitVotes(r.Votes)
+	return voters
+}
+
+func splitVotes(votes map[string]string) ([]string, []string) {
+	voters := make([]string, 0, len(votes))
+	values := make([]string, 0, len(votes))
+	for userID, value := range votes {
+		voters = append(voters, userID)
+		values = append(values, value)
+	}
+	sort.Strings(voters)
+	SortVoteValues(values)
+	return voters, values
+}
+
+// commentedValues sorts the values together with the comments left on them;
+// the comments are omitted entirely when nobody commented
+func commentedValues(votes, comments map[string]string) ([]string, []string) {
+	type commentedValue struct{ value, comment string }
+
+	pairs := make([]commentedValue, 0, len(votes))
+	for userID, value := range votes {
+		pairs = append(pairs, commentedValue{value, comments[userID]})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].comment < pairs[j].comment })
+	sort.SliceStable(pairs, func(i, j int) bool { return voteValueLess(pairs[i].value, pairs[j].value) })
+
+	values := make([]string, len(pairs))
+	texts := make([]string, len(pairs))
+	hasComments := false
+	for i, pair := range pairs {
+		values[i], texts[i] = pair.value, pair.comment
+		hasComments = hasComments || pair.comment != ""
+	}
+	if !hasComments {
+		texts = nil
+	}
+	return values, texts
+}
+
+// SortVoteValues orders cards numerically with "?" last
+func SortVoteValues(values []string) {
+	sort.SliceStable(values, func(i, j int) bool { return voteValueLess(values[i], values[j]) })
+}
+
+func voteValueLess(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA != nil || errB != nil {
+		return errA == nil
+	}
+	return x < y
 }
 
 func FromDomainVotes(votes map[string]*room.Vote) map[string]string {
@@ -29,3 +246,103 @@ func FromDomainVotes(votes map[string]*room.Vote) map[string]string {
 	}
 	return result
 }
+
+func FromDomainVoteComments(comments []room.VoteComment) []VoteCommentResp {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	result := make([]VoteCommentResp, len(comments))
+	for i, comment := range comments {
+		result[i] = VoteCommentResp{Author: comment.Author, Value: comment.Value, Comment: comment.Comment}
+	}
+	return result
+}
+
+// ToDomainVoteComments sanitizes the comments, dropping the ones left empty
+func ToDomainVoteComments(comments []VoteCommentResp) ([]room.VoteComment, error) {
+	var result []room.VoteComment
+	for _, comment := range comments {
+		text, err := room.SanitizeVoteComment(comment.Comment)
+		if err != nil {
+			return nil, err
+		}
+		if text == "" {
+			continue
+		}
+		result = append(result, room.VoteComment{Author: comment.Author, Value: comment.Value, Comment: text})
+	}
+	return result, nil
+}
+
+func FromDomainVoteChanges(changes []room.VoteChange) []VoteChangeResp {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	result := make([]VoteChangeResp, len(changes))
+	for i, change := range changes {
+		result[i] = VoteChangeResp{UserID: change.Voter, From: change.From, To: change.To}
+	}
+	return result
+}
+
+// FromDomainRecordedVoteChanges projects changes kept in the estimation history, naming the
+// voters through the names recorded with them
+func FromDomainRecordedVoteChanges(changes []room.VoteChange, voterNames map[string]string) []VoteChangeResp {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	result := make([]VoteChangeResp, len(changes))
+	for i, change := range changes {
+		result[i] = VoteChangeResp{Voter: voterNames[change.Voter], From: change.From, To: change.To}
+	}
+	return result
+}
+
+func ToDomainVoteChanges(changes []VoteChangeResp) []room.VoteChange {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	result := make([]room.VoteChange, len(changes))
+	for i, change := range changes {
+		result[i] = room.VoteChange{Voter: change.UserID, From: change.From, To: change.To}
+	}
+	return result
+}
+
+func FromDomainThreePoint(estimate room.ThreePointEstimate) *ThreePointResp {
+	return &ThreePointResp{
+		Optimistic:  estimate.Optimistic,
+		MostLikely:  estimate.MostLikely,
+		Pessimistic: estimate.Pessimistic,
+	}
+}
+
+func FromDomainPert(pert *room.PertEstimate) *PertResp {
+	if pert == nil {
+		return nil
+	}
+	return &PertResp{
+		ThreePointResp: *FromDomainThreePoint(pert.ThreePointEstimate),
+		Expected:       pert.Expected,
+		StdDev:         pert.StdDev,
+	}
+}
+
+func ToDomainPert(pert *PertResp) *room.PertEstimate {
+	if pert == nil {
+		return nil
+	}
+	return &room.PertEstimate{
+		ThreePointEstimate: room.ThreePointEstimate{
+			Optimistic:  pert.Optimistic,
+			MostLikely:  pert.MostLikely,
+			Pessimistic: pert.Pessimistic,
+		},
+		Expected: pert.Expected,
+		StdDev:   pert.StdDev,
+	}
+}
@@ -22,6 +22,9 @@ func NewRoomService(roomRepo ports.RoomRepo, stateMgr ports.RoomStateManager) *R
 }
 
 func (s *RoomService) NewRoom(ctx context.Context, req *dto.NewRoomReq) (*dto.NewRoomResp, error) {
+	ctx, span := startSpan(ctx, "RoomService.NewRoom")
+	defer span.End()
+
 	if req == nil {
 		return nil, fmt.Errorf("request cannot be nil")
 	}
@@ -29,12 +32,28 @@ func (s *RoomService) NewRoom(ctx context.Context, req *dto.NewRoomReq) (*dto.Ne
 	settings := room.RoomSettings{
 		VotingSystem: room.VotingSystem(req.VotingSystem),
 		AutoReveal:   req.AutoReveal,
+		Dimensions:   dto.ToDomainDimensions(req.Dimensions),
+		Combination:  room.DimensionCombination(req.Combination),
+		Mode:         room.EstimationMode(req.Mode),
+
+		AnonymousVotes:   req.AnonymousVotes,
+		VoteChangePolicy: room.VoteChangePolicy(req.VoteChangePolicy),
+
+		RequireAcceptanceCriteria: req.RequireAcceptanceCriteria,
+	}
+	if req.TaskFilter != nil {
+		filter, err := req.TaskFilter.ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create room: %w", err)
+		}
+		settings.TaskFilter = filter
 	}
 
 	r, err := room.NewRoom(req.Name, settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create room: %w", err)
 	}
+	span.SetAttributes(roomIDKey.String(r.ID))
 
 	if err := s.roomRepo.Create(ctx, r); err != nil {
 		return nil, fmt.Errorf("failed to persist room: %w", err)
@@ -48,6 +67,9 @@ func (s *RoomService) NewRoom(ctx context.Context, req *dto.NewRoomReq) (*dto.Ne
 }
 
 func (s *RoomService) GetRoom(ctx context.Context, roomID string) (*dto.RoomResp, error) {
+	ctx, span := startSpan(ctx, "RoomService.GetRoom", roomIDKey.String(roomID))
+	defer span.End()
+
 	if roomID == "" {
 		return nil, room.ErrInvalidRoomID
 	}
@@ -61,6 +83,9 @@ func (s *RoomService) GetRoom(ctx context.Context, roomID string) (*dto.RoomResp
 }
 
 func (s *RoomService) GetRoomState(ctx context.Context, roomID string) (*dto.RoomStateResp, error) {
+	ctx, span := startSpan(ctx, "RoomService.GetRoomState", roomIDKey.String(roomID))
+	defer span.End()
+
 	if roomID == "" {
 		return nil, room.ErrInvalidRoomID
 	}
@@ -82,13 +107,16 @@ func (s *RoomService) GetRoomState(ctx context.Context, roomID string) (*dto.Roo
 		return nil, err
 	}
 
-	response := dto.FromDomainRoomState(state, r.VotingSystem)
+	response := dto.FromDomainRoomState(state, r.RoomSettings)
 	response.RoomName = r.Name
 
 	return response, nil
 }
 
 func (s *RoomService) UpdateTaskDescription(ctx context.Context, roomID, description string) error {
+	ctx, span := startSpan(ctx, "RoomService.UpdateTaskDescription", roomIDKey.String(roomID))
+	defer span.End()
+
 	if roomID == "" {
 		return room.ErrInvalidRoomID
 	}
@@ -114,6 +142,33 @@ func (s *RoomService) UpdateTaskDescription(ctx context.Context, roomID, descrip
 	return nil
 }
 
+// SetTaskFilter saves the filter that restricts next-task selection; an empty filter clears it
+func (s *RoomService) SetTaskFilter(ctx context.Context, roomID string, req dto.TaskFilterReq) (*dto.RoomResp, error) {
+	ctx, span := startSpan(ctx, "RoomService.SetTaskFilter", roomIDKey.String(roomID))
+	defer span.End()
+
+	if roomID == "" {
+		return nil, room.ErrInvalidRoomID
+	}
+
+	filter, err := req.ToDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.UpdateTaskFilter(filter)
+	if err := s.roomRepo.Update(ctx, r); err != nil {
+		return nil, fmt.Errorf("failed to save task filter: %w", err)
+	}
+
+	return dto.FromDomainRoom(r), nil
+}
+
 func (s *RoomService) SetActiveTask(roomID, taskID string) error {
 	if roomID == "" {
 		return room.ErrInvalidRoomID
@@ -148,3 +203,39 @@ func (s *RoomService) GetActiveTask(roomID string) (string, error) {
 
 	return taskID, nil
 }
+
+// IsFacilitator reports whether the user currently facilitates the room, reading only in-memory state
+func (s *RoomService) IsFacilitator(roomID, userID string) (bool, error) {
+	if roomID == "" {
+		return false, room.ErrInvalidRoomID
+	}
+
+	if !s.stateMgr.RoomExists(roomID) {
+		return false, nil
+	}
+
+	state, err := s.stateMgr.GetRoomState(roomID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get room state: %w", err)
+	}
+
+	return userID != "" && state.FacilitatorID == userID, nil
+}
+
+// IsRevealed reports whether the current round's votes are visible, reading only in-memory state
+func (s *RoomService) IsRevealed(roomID string) (bool, error) {
+	if roomID == "" {
+		return false, room.ErrInvalidRoomID
+	}
+
+	if !s.stateMgr.RoomExists(roomID) {
+		return false, nil
+	}
+
+	state, err := s.stateMgr.GetRoomState(roomID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get room state: %w", err)
+	}
+
+	return state.IsRevealed, nil
+}
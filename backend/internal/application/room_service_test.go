@@ -52,17 +52,24 @@ type mockStateManager struct {
 	getRoomStateFunc   func(roomID string) (*ports.LiveRoomState, error)
 	roomExistsFunc     func(roomID string) bool
 	deleteRoomFunc     func(roomID string) error
-	addUserFunc        func(roomID string, user *room.User) error
+	addUserFunc        func(roomID string, user *room.User, limits room.RoomLimits) error
 	removeUserFunc     func(roomID, userID string) error
 	getUserFunc        func(roomID, userID string) (*room.User, error)
 	updateUserFunc     func(roomID string, user *room.User) error
 	getUserCountFunc   func(roomID string) (int, error)
-	submitVoteFunc     func(roomID, userID, voteValue string) error
+	submitVoteFunc     func(roomID, userID, voteValue, comment string) error
+	submitDimVoteFunc  func(roomID, userID, voteValue string, dimensionValues map[string]string) error
+	submitPertVoteFunc func(roomID, userID, voteValue string, estimate room.ThreePointEstimate) error
+	raiseHandFunc      func(roomID, userID string, signal room.HandSignal) error
+	lowerHandFunc      func(roomID, userID string) error
 	revealVotesFunc    func(roomID string) error
 	clearVotesFunc     func(roomID string) error
 	updateTaskDescFunc func(roomID, description string) error
 	setActiveTaskFunc  func(roomID, taskID string) error
 	getActiveTaskFunc  func(roomID string) (string, error)
+	pushUndoFunc       func(roomID string, entry ports.UndoEntry) error
+	popUndoFunc        func(roomID string) error
+	restoreRoundFunc   func(roomID string, round *ports.LiveRoomState) error
 }
 
 func (m *mockStateManager) NewRoom(roomID string) error {
@@ -98,9 +105,9 @@ func (m *mockStateManager) DeleteRoom(roomID string) error {
 	return nil
 }
 
-func (m *mockStateManager) AddUser(roomID string, user *room.User) error {
+func (m *mockStateManager) AddUser(roomID string, user *room.User, limits room.RoomLimits) error {
 	if m.addUserFunc != nil {
-		return m.addUserFunc(roomID, user)
+		return m.addUserFunc(roomID, user, limits)
 	}
 	return nil
 }
@@ -133,9 +140,37 @@ func (m *mockStateManager) GetUserCount(roomID string) (int, error) {
 	return 0, nil
 }
 
-func (m *mockStateManager) SubmitVote(roomID, userID, voteValue string) error {
+func (m *mockStateManager) SubmitVote(roomID, userID, voteValue, comment string) error {
 	if m.submitVoteFunc != nil {
-		return m.submitVoteFunc(roomID, userID, voteValue)
+		return m.submitVoteFunc(roomID, userID, voteValue, comment)
+	}
+	return nil
+}
+
+func (m *mockStateManager) SubmitDimensionVote(roomID, userID, voteValue string, dimensionValues map[string]string, comment string) error {
+	if m.submitDimVoteFunc != nil {
+		return m.submitDimVoteFunc(roomID, userID, voteValue, dimensionValues)
+	}
+	return nil
+}
+
+func (m *mockStateManager) SubmitThreePointVote(roomID, userID, voteValue string, estimate room.ThreePointEstimate, comment string) error {
+	if m.submitPertVoteFunc != nil {
+		return m.submitPertVoteFunc(roomID, userID, voteValue, estimate)
+	}
+	return nil
+}
+
+func (m *mockStateManager) RaiseHand(roomID, userID string, signal room.HandSignal) error {
+	if m.raiseHandFunc != nil {
+		return m.raiseHandFunc(roomID, userID, signal)
+	}
+	return nil
+}
+
+func (m *mockStateManager) LowerHand(roomID, userID string) error {
+	if m.lowerHandFunc != nil {
+		return m.lowerHandFunc(roomID, userID)
 	}
 	return nil
 }
@@ -175,6 +210,27 @@ func (m *mockStateManager) GetActiveTask(roomID string) (string, error) {
 	return "", nil
 }
 
+func (m *mockStateManager) PushUndo(roomID string, entry ports.UndoEntry) error {
+	if m.pushUndoFunc != nil {
+		return m.pushUndoFunc(roomID, entry)
+	}
+	return nil
+}
+
+func (m *mockStateManager) PopUndo(roomID string) error {
+	if m.popUndoFunc != nil {
+		return m.popUndoFunc(roomID)
+	}
+	return nil
+}
+
+func (m *mockStateManager) RestoreRound(roomID string, round *ports.LiveRoomState) error {
+	if m.restoreRoundFunc != nil {
+		return m.restoreRoundFunc(roomID, round)
+	}
+	return nil
+}
+
 // Tests for RoomService
 
 func TestRoomService_NewRoom_Success(t *testing.T) {
@@ -385,6 +441,93 @@ func TestRoomService_GetRoomState_Success(t *testing.T) {
 	}
 }
 
+func TestRoomService_GetRoomState_RaisedHands(t *testing.T) {
+	alice, _ := room.CreateUser("user1", "Alice")
+	bob, _ := room.CreateUser("user2", "Bob")
+
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return &room.Room{ID: id, RoomSettings: room.RoomSettings{VotingSystem: room.DbsFibo}}, nil
+		},
+	}
+	stateMgr := &mockStateManager{
+		roomExistsFunc: func(rID string) bool { return true },
+		getRoomStateFunc: func(rID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{
+				RoomID: rID,
+				Users:  map[string]*room.User{"user1": alice, "user2": bob},
+				Votes:  map[string]string{},
+				RaisedHands: []room.RaisedHand{
+					{UserID: "user2", Signal: room.SignalSplit},
+					{UserID: "user1", Signal: room.SignalQuestion},
+				},
+			}, nil
+		},
+	}
+	service := NewRoomService(repo, stateMgr)
+
+	resp, err := service.GetRoomState(context.Background(), "test1234")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, user := range resp.Users {
+		switch user.ID {
+		case "user1":
+			if user.RaisedHand != "question" || user.HandPosition != 2 {
+				t.Errorf("expected Alice second with a question, got %q/%d", user.RaisedHand, user.HandPosition)
+			}
+		case "user2":
+			if user.RaisedHand != "split" || user.HandPosition != 1 {
+				t.Errorf("expected Bob first asking to split, got %q/%d", user.RaisedHand, user.HandPosition)
+			}
+		}
+	}
+}
+
+func TestRoomService_GetRoomState_AnonymousVotes(t *testing.T) {
+	alice, _ := room.CreateUser("user1", "Alice")
+	bob, _ := room.CreateUser("user2", "Bob")
+
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return &room.Room{ID: id, RoomSettings: room.RoomSettings{VotingSystem: room.DbsFibo, AnonymousVotes: true}}, nil
+		},
+	}
+	stateMgr := &mockStateManager{
+		roomExistsFunc: func(rID string) bool { return true },
+		getRoomStateFunc: func(rID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{
+				RoomID:     rID,
+				Users:      map[string]*room.User{"user1": alice, "user2": bob},
+				Votes:      map[string]string{"user1": "8", "user2": "3"},
+				IsRevealed: true,
+			}, nil
+		},
+	}
+	service := NewRoomService(repo, stateMgr)
+
+	resp, err := service.GetRoomState(context.Background(), "test1234")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(resp.Votes) != 2 {
+		t.Fatalf("expected 2 votes, got %d", len(resp.Votes))
+	}
+	for _, vote := range resp.Votes {
+		if vote.UserID != "" || vote.UserName != "" {
+			t.Errorf("expected anonymous vote, got %+v", vote)
+		}
+	}
+	if resp.Votes[0].Value != "3" || resp.Votes[1].Value != "8" {
+		t.Errorf("expected sorted distribution [3 8], got [%s %s]", resp.Votes[0].Value, resp.Votes[1].Value)
+	}
+	if len(resp.Users) != 2 {
+		t.Errorf("expected users to stay visible, got %d", len(resp.Users))
+	}
+}
+
 func TestRoomService_GetRoomState_EmptyID(t *testing.T) {
 	repo := &mockRoomRepo{}
 	stateMgr := &mockStateManager{}
@@ -506,3 +649,21 @@ func TestRoomService_GetRoomState_FibonacciDoesNotRound(t *testing.T) {
 		t.Errorf("expected average %.1f (not rounded for Fibonacci), got %.1f", expectedAvg, *resp.Average)
 	}
 }
+
+func TestRoomService_IsRevealed(t *testing.T) {
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{RoomID: roomID, IsRevealed: true}, nil
+		},
+	}
+	service := NewRoomService(&mockRoomRepo{}, stateMgr)
+
+	revealed, err := service.IsRevealed("room123")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !revealed {
+		t.Error("expected room to be revealed")
+	}
+}
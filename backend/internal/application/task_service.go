@@ -2,7 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/vitaly-stepin/agile_party/internal/application/dto"
 	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
@@ -10,21 +13,33 @@ import (
 )
 
 type TaskService struct {
-	taskRepo ports.TaskRepo
-	roomRepo ports.RoomRepo
+	taskRepo    ports.TaskRepo
+	roomRepo    ports.RoomRepo
+	historyRepo ports.EstimationHistoryRepo
+	limits      room.RoomLimits
 }
 
 func NewTaskService(
 	taskRepo ports.TaskRepo,
 	roomRepo ports.RoomRepo,
+	historyRepo ports.EstimationHistoryRepo,
 ) *TaskService {
 	return &TaskService{
-		taskRepo: taskRepo,
-		roomRepo: roomRepo,
+		taskRepo:    taskRepo,
+		roomRepo:    roomRepo,
+		historyRepo: historyRepo,
 	}
 }
 
+// SetLimits applies per-room caps to subsequent task creation
+func (s *TaskService) SetLimits(limits room.RoomLimits) {
+	s.limits = limits
+}
+
 func (s *TaskService) CreateTask(ctx context.Context, roomID string, req *dto.CreateTaskReq) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.CreateTask", roomIDKey.String(roomID))
+	defer span.End()
+
 	if req == nil {
 		return nil, fmt.Errorf("request cannot be nil")
 	}
@@ -37,57 +52,105 @@ func (s *TaskService) CreateTask(ctx context.Context, roomID string, req *dto.Cr
 		return nil, room.ErrRoomNotFound
 	}
 
-	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tasks: %w", err)
-	}
+	var task *room.Task
+	err = retryRankTaken(func() error {
+		tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
 
-	nextPosition := len(tasks) + 1
+		if err := s.limits.CheckTaskCount(len(tasks)); err != nil {
+			return err
+		}
 
-	task, err := room.NewTask(roomID, req.Headline, nextPosition)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
-	}
+		task, err = room.NewTask(roomID, req.Headline, len(tasks)+1)
+		if err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
 
-	if req.Description != "" {
-		task.UpdateDescription(req.Description)
-	}
-	if req.TrackerLink != "" {
-		task.UpdateTrackerLink(req.TrackerLink)
-	}
+		if req.Description != "" {
+			task.UpdateDescription(req.Description)
+		}
+		if req.TrackerLink != "" {
+			task.UpdateTrackerLink(req.TrackerLink)
+		}
+		if err := task.SetType(req.Type); err != nil {
+			return err
+		}
+		if err := task.SetLabels(req.Labels); err != nil {
+			return err
+		}
 
-	if err := s.taskRepo.Create(ctx, task); err != nil {
-		return nil, fmt.Errorf("failed to persist task: %w", err)
+		// The task joins the end of its epic's subtasks, or the end of the backlog
+		tree := room.NewTaskTree(append(tasks, task))
+		if err := tree.SetParent(task.ID, req.ParentID); err != nil {
+			return err
+		}
+		tree.Number()
+
+		if err := s.taskRepo.Create(ctx, task); err != nil {
+			return fmt.Errorf("failed to persist task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return dto.FromDomainTask(task), nil
 }
 
-func (s *TaskService) GetTask(ctx context.Context, taskID string) (*dto.TaskResp, error) {
-	task, err := s.taskRepo.GetByID(ctx, taskID)
+// GetRoomTasks returns the room's tasks matching filter in backlog order; a zero filter returns them all
+func (s *TaskService) GetRoomTasks(ctx context.Context, roomID string, filter dto.TaskFilterReq) ([]*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.GetRoomTasks", roomIDKey.String(roomID))
+	defer span.End()
+
+	taskFilter, err := filter.ToDomain()
 	if err != nil {
 		return nil, err
 	}
-	return dto.FromDomainTask(task), nil
-}
 
-func (s *TaskService) GetRoomTasks(ctx context.Context, roomID string) ([]*dto.TaskResp, error) {
 	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
 	if err != nil {
 		return nil, err
 	}
-	return dto.FromDomainTasks(tasks), nil
+	return dto.FromDomainTasksWithRollups(taskFilter.Apply(tasks), room.NewTaskTree(tasks)), nil
 }
 
-func (s *TaskService) UpdateTask(ctx context.Context, taskID string, req *dto.UpdateTaskReq) (*dto.TaskResp, error) {
+// TaskConflictError rejects an edit made on an outdated copy of a task.
+// Current is the task as saved, so the client can merge its change into it.
+type TaskConflictError struct {
+	Current *dto.TaskResp
+}
+
+func (e *TaskConflictError) Error() string {
+	return room.ErrTaskVersionConflict.Error()
+}
+
+func (e *TaskConflictError) Unwrap() error {
+	return room.ErrTaskVersionConflict
+}
+
+// UpdateTask applies an edit made on version req.Version of the task. When the task was
+// saved since, nothing is changed and a *TaskConflictError carrying the current task is returned.
+func (s *TaskService) UpdateTask(ctx context.Context, roomID, taskID string, req *dto.UpdateTaskReq) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.UpdateTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
 	if req == nil {
 		return nil, fmt.Errorf("request cannot be nil")
 	}
+	if req.Version == nil {
+		return nil, room.ErrTaskVersionRequired
+	}
 
-	task, err := s.taskRepo.GetByID(ctx, taskID)
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
 	if err != nil {
 		return nil, err
 	}
+	if task.Version != *req.Version {
+		return nil, &TaskConflictError{Current: dto.FromDomainTask(task)}
+	}
 
 	if req.Headline != "" {
 		if err := task.UpdateHeadline(req.Headline); err != nil {
@@ -103,25 +166,280 @@ func (s *TaskService) UpdateTask(ctx context.Context, taskID string, req *dto.Up
 		task.UpdateTrackerLink(req.TrackerLink)
 	}
 
+	if req.Type != "" {
+		if err := task.SetType(req.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Labels != nil {
+		if err := task.SetLabels(*req.Labels); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.taskRepo.Update(ctx, task); err != nil {
+		if errors.Is(err, room.ErrTaskVersionConflict) {
+			return nil, s.conflict(ctx, roomID, taskID)
+		}
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
 	return dto.FromDomainTask(task), nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, taskID string) error {
-	return s.taskRepo.Delete(ctx, taskID)
+// conflict reports a lost race for the task together with the version that won it
+func (s *TaskService) conflict(ctx context.Context, roomID, taskID string) error {
+	current, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	if err != nil {
+		return err
+	}
+	return &TaskConflictError{Current: dto.FromDomainTask(current)}
+}
+
+// DeleteTask removes a task. Its subtasks are not deleted, they move up to the
+// task's own parent and are returned so clients can follow the move.
+func (s *TaskService) DeleteTask(ctx context.Context, roomID, taskID string) ([]*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.DeleteTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	if _, err := s.taskRepo.GetByID(ctx, roomID, taskID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	// The subtasks take the task's place, in their current order
+	tree := room.NewTaskTree(tasks)
+	subtasks := append([]*room.Task(nil), tree.Subtasks(taskID)...)
+	anchorID := taskID
+	for _, subtask := range subtasks {
+		if err := tree.MoveAfter(subtask.ID, anchorID); err != nil {
+			return nil, err
+		}
+		if err := s.taskRepo.Move(ctx, subtask); err != nil {
+			return nil, fmt.Errorf("failed to move subtask: %w", err)
+		}
+		anchorID = subtask.ID
+	}
+
+	if err := s.taskRepo.Delete(ctx, roomID, taskID); err != nil {
+		return nil, err
+	}
+	return dto.FromDomainTasks(subtasks), nil
+}
+
+// PurgeDeletedTasks removes for good the tasks deleted more than retention ago, in every room.
+// Their deletion can no longer be undone.
+func (s *TaskService) PurgeDeletedTasks(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := startSpan(ctx, "TaskService.PurgeDeletedTasks")
+	defer span.End()
+
+	purged, err := s.taskRepo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// SetTaskParent files a task under an epic, or moves it to the top level when parentID is empty.
+// The task lands after its new siblings; the room's tasks are returned in their new order.
+func (s *TaskService) SetTaskParent(ctx context.Context, roomID, taskID, parentID string) ([]*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.SetTaskParent", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	var tree *room.TaskTree
+	err := retryRankTaken(func() error {
+		tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
+
+		tree = room.NewTaskTree(tasks)
+		task, err := tree.Find(taskID)
+		if err != nil {
+			return err
+		}
+		if tree.Parent(taskID) == parentID {
+			return nil
+		}
+		if err := tree.SetParent(taskID, parentID); err != nil {
+			return err
+		}
+		return s.taskRepo.Move(ctx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromDomainTasksWithRollups(tree.Number(), tree), nil
+}
+
+// MoveTask places a task right before or right after another task of the room, under that task's parent.
+// Only the moved task is written; a move losing a race for the same place is retried on fresh data.
+// It returns the moved task and the room's task IDs in their new order.
+func (s *TaskService) MoveTask(ctx context.Context, roomID string, req *dto.MoveTaskReq) (*dto.TaskResp, []string, error) {
+	ctx, span := startSpan(ctx, "TaskService.MoveTask", roomIDKey.String(roomID))
+	defer span.End()
+
+	if req == nil || (req.BeforeID == "") == (req.AfterID == "") {
+		return nil, nil, room.ErrInvalidTaskMove
+	}
+
+	var task *room.Task
+	var ordered []*room.Task
+	err := retryRankTaken(func() error {
+		tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
+
+		tree := room.NewTaskTree(tasks)
+		if req.BeforeID != "" {
+			err = tree.MoveBefore(req.TaskID, req.BeforeID)
+		} else {
+			err = tree.MoveAfter(req.TaskID, req.AfterID)
+		}
+		if err != nil {
+			return err
+		}
+
+		ordered = tree.Number()
+		if task, err = tree.Find(req.TaskID); err != nil {
+			return err
+		}
+		return s.taskRepo.Move(ctx, task)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dto.FromDomainTask(task), taskIDs(ordered), nil
+}
+
+// maxRankAttempts bounds how often a write losing a race for a rank is retried
+const maxRankAttempts = 3
+
+// retryRankTaken reruns attempt while it fails because a concurrent write took the same rank
+func retryRankTaken(attempt func() error) error {
+	var err error
+	for range maxRankAttempts {
+		if err = attempt(); !errors.Is(err, room.ErrTaskRankTaken) {
+			return err
+		}
+	}
+	return err
+}
+
+func taskIDs(tasks []*room.Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// GetRoomTaskTree returns the room's tasks nested under their epics, with rolled-up estimates
+func (s *TaskService) GetRoomTaskTree(ctx context.Context, roomID string) ([]*dto.TaskNodeResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.GetRoomTaskTree", roomIDKey.String(roomID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromDomainTaskTree(room.NewTaskTree(tasks)), nil
+}
+
+// rerank saves the tree's order as fresh ranks for every task, keeping every epic
+// directly followed by its subtasks
+func (s *TaskService) rerank(ctx context.Context, roomID string, tree *room.TaskTree) ([]*room.Task, error) {
+	tree.Rerank()
+	ordered := tree.Number()
+	if err := s.taskRepo.UpdateRanks(ctx, roomID, ordered); err != nil {
+		return nil, fmt.Errorf("failed to update task ranks: %w", err)
+	}
+	return ordered, nil
+}
+
+// AddDependency marks a task as blocked by another task of the same room.
+// Dependencies that would form a cycle are rejected; adding an existing one changes nothing.
+func (s *TaskService) AddDependency(ctx context.Context, roomID, taskID, dependsOnID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.AddDependency", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	task, err := room.NewBacklog(tasks).Find(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.DependsOnTask(dependsOnID) {
+		return dto.FromDomainTask(task), nil
+	}
+
+	if err := room.ValidateDependency(tasks, taskID, dependsOnID); err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.AddDependency(ctx, roomID, taskID, dependsOnID); err != nil {
+		return nil, err
+	}
+
+	task.DependsOn = append(task.DependsOn, dependsOnID)
+	return dto.FromDomainTask(task), nil
+}
+
+func (s *TaskService) RemoveDependency(ctx context.Context, roomID, taskID, dependsOnID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.RemoveDependency", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.RemoveDependency(ctx, roomID, taskID, dependsOnID); err != nil {
+		return nil, err
+	}
+
+	task.DependsOn = slices.DeleteFunc(task.DependsOn, func(id string) bool { return id == dependsOnID })
+	return dto.FromDomainTask(task), nil
+}
+
+// ProposeTaskOrder suggests a backlog order in which every task follows the tasks it depends on.
+// Nothing is saved; the order is meant to be reviewed and applied with ReorderTasks.
+func (s *TaskService) ProposeTaskOrder(ctx context.Context, roomID string) ([]string, error) {
+	ctx, span := startSpan(ctx, "TaskService.ProposeTaskOrder", roomIDKey.String(roomID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	return taskIDs(room.TopologicalOrder(tasks)), nil
 }
 
-func (s *TaskService) ReorderTasks(ctx context.Context, roomID string, req *dto.ReorderTasksReq) error {
+// ReorderTasks applies a new backlog order and returns it. Subtasks stay grouped right
+// after their epic: moving an epic moves its subtasks, and a subtask only moves among its siblings.
+// Every task gets a new rank, so single moves should go through MoveTask.
+func (s *TaskService) ReorderTasks(ctx context.Context, roomID string, req *dto.ReorderTasksReq) ([]string, error) {
+	ctx, span := startSpan(ctx, "TaskService.ReorderTasks", roomIDKey.String(roomID))
+	defer span.End()
+
 	if req == nil || len(req.TaskIDs) == 0 {
-		return fmt.Errorf("task IDs required")
+		return nil, fmt.Errorf("task IDs required")
 	}
 
 	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
 	if err != nil {
-		return fmt.Errorf("failed to get tasks: %w", err)
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
 	taskMap := make(map[string]*room.Task)
@@ -130,24 +448,36 @@ func (s *TaskService) ReorderTasks(ctx context.Context, roomID string, req *dto.
 	}
 
 	if len(req.TaskIDs) != len(tasks) {
-		return fmt.Errorf("task count mismatch: expected %d, got %d", len(tasks), len(req.TaskIDs))
+		return nil, fmt.Errorf("task count mismatch: expected %d, got %d", len(tasks), len(req.TaskIDs))
 	}
 
 	reorderedTasks := make([]*room.Task, len(req.TaskIDs))
 	for i, taskID := range req.TaskIDs {
 		task, exists := taskMap[taskID]
 		if !exists {
-			return fmt.Errorf("task not found: %s", taskID)
+			return nil, fmt.Errorf("task not found: %s", taskID)
 		}
 		task.Position = i + 1
 		reorderedTasks[i] = task
 	}
 
-	return s.taskRepo.UpdatePositions(ctx, reorderedTasks)
+	ordered, err := s.rerank(ctx, roomID, room.NewTaskTree(reorderedTasks))
+	if err != nil {
+		return nil, err
+	}
+	return taskIDs(ordered), nil
 }
 
 func (s *TaskService) GetNextUnestimatedTask(ctx context.Context, roomID string) (*dto.TaskResp, error) {
-	task, err := s.taskRepo.GetNextUnestimatedTask(ctx, roomID)
+	ctx, span := startSpan(ctx, "TaskService.GetNextUnestimatedTask", roomIDKey.String(roomID))
+	defer span.End()
+
+	rm, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetNextUnestimatedTask(ctx, roomID, rm.TaskFilter)
 	if err != nil {
 		if err == room.ErrTaskNotFound {
 			return nil, nil
@@ -157,82 +487,322 @@ func (s *TaskService) GetNextUnestimatedTask(ctx context.Context, roomID string)
 	return dto.FromDomainTask(task), nil
 }
 
-func (s *TaskService) SaveEstimation(ctx context.Context, roomID, estimation string) error {
+// GetRoomTask returns the task only when it belongs to roomID's backlog
+func (s *TaskService) GetRoomTask(ctx context.Context, roomID, taskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.GetRoomTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromDomainTask(task), nil
+}
+
+// NextTask returns the first open task after currentTaskID in backlog order that matches
+// the room's saved task filter. A current task that is no longer in the backlog is treated as none.
+func (s *TaskService) NextTask(ctx context.Context, roomID, currentTaskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.NextTask", roomIDKey.String(roomID), taskIDKey.String(currentTaskID))
+	defer span.End()
+
 	rm, err := s.roomRepo.GetByID(ctx, roomID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	currentTask, err := s.taskRepo.GetNextUnestimatedTask(ctx, roomID)
+	backlog, err := s.backlog(ctx, roomID)
 	if err != nil {
-		if err == room.ErrTaskNotFound {
-			return nil
-		}
-		return err
+		return nil, err
 	}
 
-	if err := currentTask.SetEstimation(estimation, rm.VotingSystem); err != nil {
-		return fmt.Errorf("failed to set estimation: %w", err)
+	task, err := backlog.NextMatching(currentTaskID, rm.TaskFilter)
+	if errors.Is(err, room.ErrTaskNotFound) {
+		task, err = backlog.NextMatching("", rm.TaskFilter)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromDomainTask(task), nil
+}
 
-	if err := s.taskRepo.Update(ctx, currentTask); err != nil {
-		return fmt.Errorf("failed to save estimation: %w", err)
+// PreviousTask returns the task right before currentTaskID, whatever its status
+func (s *TaskService) PreviousTask(ctx context.Context, roomID, currentTaskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.PreviousTask", roomIDKey.String(roomID), taskIDKey.String(currentTaskID))
+	defer span.End()
+
+	backlog, err := s.backlog(ctx, roomID)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	task, err := backlog.Previous(currentTaskID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromDomainTask(task), nil
 }
 
-func (s *TaskService) SaveEstimationToTask(ctx context.Context, taskID, estimation string) error {
-	task, err := s.taskRepo.GetByID(ctx, taskID)
+func (s *TaskService) backlog(ctx context.Context, roomID string) (room.Backlog, error) {
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
 	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
+	return room.NewBacklog(tasks), nil
+}
+
+// SkipTask takes a task out of the estimation queue without estimating it
+func (s *TaskService) SkipTask(ctx context.Context, roomID, taskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.SkipTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.changeStatus(ctx, roomID, taskID, (*room.Task).Skip)
+}
+
+// DeferTask parks a task that needs more information before it can be estimated
+func (s *TaskService) DeferTask(ctx context.Context, roomID, taskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.DeferTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.changeStatus(ctx, roomID, taskID, (*room.Task).Defer)
+}
 
-	rm, err := s.roomRepo.GetByID(ctx, task.RoomID)
+// ReopenTask puts a skipped, deferred or estimated task back into the queue
+func (s *TaskService) ReopenTask(ctx context.Context, roomID, taskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.ReopenTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.changeStatus(ctx, roomID, taskID, (*room.Task).Reopen)
+}
+
+func (s *TaskService) changeStatus(ctx context.Context, roomID, taskID string, change func(*room.Task) error) (*dto.TaskResp, error) {
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
 	if err != nil {
-		return fmt.Errorf("failed to get room: %w", err)
+		return nil, err
 	}
 
-	if err := task.SetEstimation(estimation, rm.VotingSystem); err != nil {
-		return fmt.Errorf("failed to set estimation: %w", err)
+	if err := change(task); err != nil {
+		return nil, err
 	}
 
 	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return fmt.Errorf("failed to save estimation: %w", err)
+		return nil, fmt.Errorf("failed to update task status: %w", err)
+	}
+
+	return dto.FromDomainTask(task), nil
+}
+
+// StartDiscussion marks taskID as the task under discussion and returns any other
+// task of the room that was being discussed to pending. Tasks that are not open
+// (estimated, skipped, deferred) keep their status. It returns every task whose status changed.
+func (s *TaskService) StartDiscussion(ctx context.Context, roomID, taskID string) ([]*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.StartDiscussion", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	return nil
+	var changed []*room.Task
+	for _, task := range tasks {
+		var err error
+		switch {
+		case task.ID == taskID && task.Status == room.TaskStatusPending:
+			err = task.StartDiscussion()
+		case task.ID != taskID && task.Status == room.TaskStatusInDiscussion:
+			err = task.StopDiscussion()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, task)
+	}
+
+	for _, task := range changed {
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return nil, fmt.Errorf("failed to update task status: %w", err)
+		}
+	}
+
+	return dto.FromDomainTasks(changed), nil
 }
 
-func (s *TaskService) SaveEstimationAndMoveNext(ctx context.Context, roomID, estimation string) (*dto.TaskResp, error) {
+// AcceptEstimate stores the final estimation on the given task, or on the room's next
+// open task when req.TaskID is empty. Only the facilitator may override the suggestion.
+func (s *TaskService) AcceptEstimate(ctx context.Context, roomID string, req *dto.AcceptEstimateReq) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.AcceptEstimate", roomIDKey.String(roomID))
+	defer span.End()
+
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
 	rm, err := s.roomRepo.GetByID(ctx, roomID)
 	if err != nil {
 		return nil, err
 	}
 
-	currentTask, err := s.taskRepo.GetNextUnestimatedTask(ctx, roomID)
+	var task *room.Task
+	if req.TaskID != "" {
+		task, err = s.taskRepo.GetByID(ctx, roomID, req.TaskID)
+	} else {
+		task, err = s.taskRepo.GetNextUnestimatedTask(ctx, roomID, rm.TaskFilter)
+	}
 	if err != nil {
-		if err == room.ErrTaskNotFound {
-			return nil, nil
-		}
 		return nil, err
 	}
 
-	if err := currentTask.SetEstimation(estimation, rm.VotingSystem); err != nil {
-		return nil, fmt.Errorf("failed to set estimation: %w", err)
+	if err := task.AcceptEstimation(req.Value, req.Suggested, req.Reason, rm.VotingSystem); err != nil {
+		return nil, err
+	}
+	if task.EstimationOverridden && !req.ByFacilitator {
+		return nil, room.ErrOverrideNotAllowed
+	}
+	if err := task.SetEstimationBreakdown(req.Breakdown, rm.Dimensions); err != nil {
+		return nil, err
+	}
+	if rm.IsThreePoint() {
+		task.SetPertEstimate(dto.ToDomainPert(req.Pert))
+	} else {
+		task.SetPertEstimate(nil)
+	}
+	comments, err := dto.ToDomainVoteComments(req.Comments)
+	if err != nil {
+		return nil, err
+	}
+
+	// The record is written first and removed again when the task cannot be saved,
+	// so an estimated task always has its history entry
+	record := room.NewEstimationRecord(task, req.Participants)
+	record.Comments = comments
+	record.Changes = dto.ToDomainVoteChanges(req.Changes)
+	record.VoterNames = req.VoterNames
+	if err := s.historyRepo.Add(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to record estimation history: %w", err)
 	}
 
-	if err := s.taskRepo.Update(ctx, currentTask); err != nil {
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		if delErr := s.historyRepo.Delete(ctx, record.ID); delErr != nil {
+			return nil, fmt.Errorf("failed to save estimation: %w; failed to remove its history record: %v", err, delErr)
+		}
 		return nil, fmt.Errorf("failed to save estimation: %w", err)
 	}
 
-	nextTask, err := s.taskRepo.GetNextUnestimatedTask(ctx, roomID)
+	return dto.FromDomainTask(task), nil
+}
+
+// ReestimateTask reopens an estimated task so it can be voted on again
+func (s *TaskService) ReestimateTask(ctx context.Context, roomID, taskID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.ReestimateTask", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.changeStatus(ctx, roomID, taskID, (*room.Task).Reestimate)
+}
+
+// GetEstimationHistory returns every estimation accepted for a task of the room, oldest first
+func (s *TaskService) GetEstimationHistory(ctx context.Context, roomID, taskID string) ([]*dto.EstimationRecordResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.GetEstimationHistory", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	if _, err := s.taskRepo.GetByID(ctx, roomID, taskID); err != nil {
+		return nil, err
+	}
+
+	records, err := s.historyRepo.ListByTask(ctx, taskID)
 	if err != nil {
-		if err == room.ErrTaskNotFound {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find next task: %w", err)
+		return nil, fmt.Errorf("failed to get estimation history: %w", err)
 	}
 
-	return dto.FromDomainTask(nextTask), nil
+	return dto.FromDomainEstimationRecords(records), nil
+}
+
+// UpdateNotes replaces the decision log of a task of the room, keeping the previous revision.
+// Only the facilitator keeps the log.
+func (s *TaskService) UpdateNotes(ctx context.Context, roomID, taskID, author string, notes []string, byFacilitator bool) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.UpdateNotes", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	if !byFacilitator {
+		return nil, room.ErrNotesNotAllowed
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := task.UpdateNotes(notes, author)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return dto.FromDomainTask(task), nil
+	}
+
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update decision log: %w", err)
+	}
+
+	return dto.FromDomainTask(task), nil
+}
+
+// AddCriterion appends an acceptance criterion to a task of the room
+func (s *TaskService) AddCriterion(ctx context.Context, roomID, taskID, text string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.AddCriterion", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.updateCriteria(ctx, roomID, taskID, func(task *room.Task) error {
+		_, err := task.AddCriterion(text)
+		return err
+	})
+}
+
+func (s *TaskService) UpdateCriterion(ctx context.Context, roomID, taskID, criterionID string, req *dto.UpdateCriterionReq) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.UpdateCriterion", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	return s.updateCriteria(ctx, roomID, taskID, func(task *room.Task) error {
+		return task.UpdateCriterion(criterionID, req.Text, req.Done)
+	})
+}
+
+func (s *TaskService) DeleteCriterion(ctx context.Context, roomID, taskID, criterionID string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.DeleteCriterion", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.updateCriteria(ctx, roomID, taskID, func(task *room.Task) error {
+		return task.RemoveCriterion(criterionID)
+	})
+}
+
+func (s *TaskService) ReorderCriteria(ctx context.Context, roomID, taskID string, criterionIDs []string) (*dto.TaskResp, error) {
+	ctx, span := startSpan(ctx, "TaskService.ReorderCriteria", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	return s.updateCriteria(ctx, roomID, taskID, func(task *room.Task) error {
+		return task.ReorderCriteria(criterionIDs)
+	})
+}
+
+func (s *TaskService) updateCriteria(ctx context.Context, roomID, taskID string, change func(*room.Task) error) (*dto.TaskResp, error) {
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(task); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update acceptance criteria: %w", err)
+	}
+
+	return dto.FromDomainTask(task), nil
 }
@@ -0,0 +1,1040 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+// Mock TaskRepo backed by an in-memory map
+type mockTaskRepo struct {
+	tasks     map[string]*room.Task
+	deleted   map[string]*room.Task // soft-deleted tasks, kept for Restore
+	deletedAt map[string]time.Time
+
+	rankConflicts int // number of upcoming moves to fail as if a concurrent move took the rank
+}
+
+func newMockTaskRepo(tasks ...*room.Task) *mockTaskRepo {
+	m := &mockTaskRepo{
+		tasks:     make(map[string]*room.Task),
+		deleted:   make(map[string]*room.Task),
+		deletedAt: make(map[string]time.Time),
+	}
+	for _, task := range tasks {
+		m.tasks[task.ID] = task
+	}
+	return m
+}
+
+func (m *mockTaskRepo) Create(ctx context.Context, task *room.Task) error {
+	m.tasks[task.ID] = task
+	return nil
+}
+
+func (m *mockTaskRepo) GetByID(ctx context.Context, roomID, id string) (*room.Task, error) {
+	task, ok := m.tasks[id]
+	if !ok || task.RoomID != roomID {
+		return nil, room.ErrTaskNotFound
+	}
+	taskCopy := *task
+	return &taskCopy, nil
+}
+
+// GetByRoomID derives the positions from the ranks, like the database does
+func (m *mockTaskRepo) GetByRoomID(ctx context.Context, roomID string) ([]*room.Task, error) {
+	var tasks []*room.Task
+	for _, task := range m.tasks {
+		if task.RoomID == roomID {
+			taskCopy := *task
+			tasks = append(tasks, &taskCopy)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Rank < tasks[j].Rank })
+	for i, task := range tasks {
+		task.Position = i + 1
+	}
+	return room.NewTaskTree(tasks).Number(), nil
+}
+
+func (m *mockTaskRepo) Update(ctx context.Context, task *room.Task) error {
+	stored, ok := m.tasks[task.ID]
+	if !ok || stored.RoomID != task.RoomID {
+		return room.ErrTaskNotFound
+	}
+	if stored != task && stored.Version != task.Version {
+		return room.ErrTaskVersionConflict
+	}
+	// Like the database, an update leaves the task's place to Move and UpdateRanks
+	task.Rank, task.ParentID = stored.Rank, stored.ParentID
+	task.Version++
+	m.tasks[task.ID] = task
+	return nil
+}
+
+func (m *mockTaskRepo) Delete(ctx context.Context, roomID, id string) error {
+	task, ok := m.tasks[id]
+	if !ok || task.RoomID != roomID {
+		return room.ErrTaskNotFound
+	}
+	m.deleted[id] = task
+	m.deletedAt[id] = time.Now()
+	delete(m.tasks, id)
+	return nil
+}
+
+func (m *mockTaskRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	for id, deletedAt := range m.deletedAt {
+		if deletedAt.Before(deletedBefore) {
+			delete(m.deleted, id)
+			delete(m.deletedAt, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *mockTaskRepo) Restore(ctx context.Context, task *room.Task) error {
+	stored, ok := m.deleted[task.ID]
+	if !ok || stored.RoomID != task.RoomID {
+		return room.ErrTaskNotFound
+	}
+	for _, other := range m.tasks {
+		if other.RoomID == task.RoomID && other.ParentID == task.ParentID && other.Rank == task.Rank {
+			return room.ErrTaskRankTaken
+		}
+	}
+	stored.Rank = task.Rank
+	stored.ParentID = task.ParentID
+	m.tasks[task.ID] = stored
+	delete(m.deleted, task.ID)
+	delete(m.deletedAt, task.ID)
+	return nil
+}
+
+func (m *mockTaskRepo) UpdateRanks(ctx context.Context, roomID string, tasks []*room.Task) error {
+	for _, task := range tasks {
+		if stored, ok := m.tasks[task.ID]; !ok || stored.RoomID != roomID {
+			return room.ErrTaskNotFound
+		}
+	}
+	for _, task := range tasks {
+		m.tasks[task.ID] = task
+	}
+	return nil
+}
+
+func (m *mockTaskRepo) Move(ctx context.Context, task *room.Task) error {
+	stored, ok := m.tasks[task.ID]
+	if !ok || stored.RoomID != task.RoomID {
+		return room.ErrTaskNotFound
+	}
+	if m.rankConflicts > 0 {
+		m.rankConflicts--
+		return room.ErrTaskRankTaken
+	}
+	for _, other := range m.tasks {
+		if other.ID != task.ID && other.RoomID == task.RoomID && other.ParentID == task.ParentID && other.Rank == task.Rank {
+			return room.ErrTaskRankTaken
+		}
+	}
+	stored.Rank = task.Rank
+	stored.ParentID = task.ParentID
+	return nil
+}
+
+func (m *mockTaskRepo) GetNextUnestimatedTask(ctx context.Context, roomID string, filter room.TaskFilter) (*room.Task, error) {
+	tasks, _ := m.GetByRoomID(ctx, roomID)
+	for _, task := range tasks {
+		if task.IsOpen() && filter.Matches(task) && !m.hasSubtasks(task.ID) {
+			return task, nil
+		}
+	}
+	return nil, room.ErrTaskNotFound
+}
+
+func (m *mockTaskRepo) AddDependency(ctx context.Context, roomID, taskID, dependsOnID string) error {
+	task, ok := m.tasks[taskID]
+	dependency, found := m.tasks[dependsOnID]
+	if !ok || !found || task.RoomID != roomID || dependency.RoomID != roomID {
+		return room.ErrTaskNotFound
+	}
+	if !task.DependsOnTask(dependsOnID) {
+		task.DependsOn = append(task.DependsOn, dependsOnID)
+	}
+	return nil
+}
+
+func (m *mockTaskRepo) RemoveDependency(ctx context.Context, roomID, taskID, dependsOnID string) error {
+	task, ok := m.tasks[taskID]
+	if !ok || task.RoomID != roomID || !task.DependsOnTask(dependsOnID) {
+		return room.ErrTaskDependencyNotFound
+	}
+	task.DependsOn = slices.DeleteFunc(slices.Clone(task.DependsOn), func(id string) bool { return id == dependsOnID })
+	return nil
+}
+
+func (m *mockTaskRepo) hasSubtasks(taskID string) bool {
+	for _, task := range m.tasks {
+		if task.ParentID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// Mock EstimationHistoryRepo keeping records per task in insertion order
+type mockHistoryRepo struct {
+	records map[string][]*room.EstimationRecord
+}
+
+func newMockHistoryRepo() *mockHistoryRepo {
+	return &mockHistoryRepo{records: make(map[string][]*room.EstimationRecord)}
+}
+
+func (m *mockHistoryRepo) Add(ctx context.Context, record *room.EstimationRecord) error {
+	m.records[record.TaskID] = append(m.records[record.TaskID], record)
+	return nil
+}
+
+func (m *mockHistoryRepo) ListByTask(ctx context.Context, taskID string) ([]*room.EstimationRecord, error) {
+	return m.records[taskID], nil
+}
+
+func (m *mockHistoryRepo) Delete(ctx context.Context, id string) error {
+	for taskID, records := range m.records {
+		m.records[taskID] = slices.DeleteFunc(records, func(record *room.EstimationRecord) bool { return record.ID == id })
+	}
+	return nil
+}
+
+func existingRoomRepo() *mockRoomRepo {
+	return &mockRoomRepo{
+		existsFunc: func(ctx context.Context, id string) (bool, error) {
+			return true, nil
+		},
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return &room.Room{ID: id, RoomSettings: room.RoomSettings{VotingSystem: room.DbsFibo}}, nil
+		},
+	}
+}
+
+func TestTaskService_CreateTask_Success(t *testing.T) {
+	service := NewTaskService(newMockTaskRepo(), existingRoomRepo(), newMockHistoryRepo())
+
+	task, err := service.CreateTask(context.Background(), "room123", &dto.CreateTaskReq{Headline: "Login page"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.Position != 1 {
+		t.Errorf("expected position 1, got %d", task.Position)
+	}
+}
+
+func TestTaskService_CreateTask_TaskLimitReached(t *testing.T) {
+	existing, _ := room.NewTask("room123", "Existing", 1)
+	service := NewTaskService(newMockTaskRepo(existing), existingRoomRepo(), newMockHistoryRepo())
+	service.SetLimits(room.RoomLimits{MaxTasks: 1})
+
+	_, err := service.CreateTask(context.Background(), "room123", &dto.CreateTaskReq{Headline: "One too many"})
+
+	if !errors.Is(err, room.ErrTaskLimitReached) {
+		t.Errorf("expected ErrTaskLimitReached, got %v", err)
+	}
+}
+
+func TestTaskService_SkipTask_RemovesFromQueue(t *testing.T) {
+	first, _ := room.NewTask("room123", "First", 1)
+	second, _ := room.NewTask("room123", "Second", 2)
+	service := NewTaskService(newMockTaskRepo(first, second), existingRoomRepo(), newMockHistoryRepo())
+
+	skipped, err := service.SkipTask(context.Background(), "room123", first.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if skipped.Status != string(room.TaskStatusSkipped) {
+		t.Errorf("expected status skipped, got %s", skipped.Status)
+	}
+
+	next, err := service.GetNextUnestimatedTask(context.Background(), "room123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if next == nil || next.ID != second.ID {
+		t.Errorf("expected next task %s, got %+v", second.ID, next)
+	}
+}
+
+func TestTaskService_DeferTask_EstimatedTaskRejected(t *testing.T) {
+	task, _ := room.NewTask("room123", "Done", 1)
+	_ = task.SetEstimation("5", room.DbsFibo)
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+
+	_, err := service.DeferTask(context.Background(), "room123", task.ID)
+
+	if !errors.Is(err, room.ErrInvalidTaskTransition) {
+		t.Errorf("expected ErrInvalidTaskTransition, got %v", err)
+	}
+}
+
+func TestTaskService_ReopenTask_ReturnsToQueue(t *testing.T) {
+	task, _ := room.NewTask("room123", "Later", 1)
+	_ = task.Defer()
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+
+	reopened, err := service.ReopenTask(context.Background(), "room123", task.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reopened.Status != string(room.TaskStatusPending) {
+		t.Errorf("expected status pending, got %s", reopened.Status)
+	}
+}
+
+func TestTaskService_StartDiscussion_HandsOver(t *testing.T) {
+	current, _ := room.NewTask("room123", "Current", 1)
+	_ = current.StartDiscussion()
+	next, _ := room.NewTask("room123", "Next", 2)
+	done, _ := room.NewTask("room123", "Done", 3)
+	_ = done.SetEstimation("3", room.DbsFibo)
+	repo := newMockTaskRepo(current, next, done)
+	service := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+
+	changed, err := service.StartDiscussion(context.Background(), "room123", next.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(changed) != 2 {
+		t.Fatalf("expected 2 changed tasks, got %d", len(changed))
+	}
+	if repo.tasks[current.ID].Status != room.TaskStatusPending {
+		t.Errorf("expected previous task to return to pending, got %s", repo.tasks[current.ID].Status)
+	}
+	if repo.tasks[next.ID].Status != room.TaskStatusInDiscussion {
+		t.Errorf("expected next task in discussion, got %s", repo.tasks[next.ID].Status)
+	}
+
+	// Viewing an estimated task must not reopen it
+	if _, err := service.StartDiscussion(context.Background(), "room123", done.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.tasks[done.ID].Status != room.TaskStatusEstimated {
+		t.Errorf("expected estimated task to keep its status, got %s", repo.tasks[done.ID].Status)
+	}
+}
+
+func TestTaskService_AcceptEstimate_RecordsOverride(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	repo := newMockTaskRepo(task)
+	service := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+
+	accepted, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{
+		TaskID:        task.ID,
+		Value:         "13",
+		Suggested:     "8",
+		Reason:        "unknown API",
+		ByFacilitator: true,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if accepted.Estimation != "13" || !accepted.EstimationOverridden || accepted.OverrideReason != "unknown API" {
+		t.Errorf("expected overridden estimation 13 with reason, got %+v", accepted)
+	}
+	if repo.tasks[task.ID].Status != room.TaskStatusEstimated {
+		t.Errorf("expected stored task to be estimated, got %s", repo.tasks[task.ID].Status)
+	}
+}
+
+// conflictingTaskRepo fails every update as if someone else saved the task first
+type conflictingTaskRepo struct {
+	*mockTaskRepo
+}
+
+func (m conflictingTaskRepo) Update(ctx context.Context, task *room.Task) error {
+	return room.ErrTaskVersionConflict
+}
+
+func TestTaskService_AcceptEstimate_FailedSaveLeavesNoHistory(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	history := newMockHistoryRepo()
+	service := NewTaskService(conflictingTaskRepo{newMockTaskRepo(task)}, existingRoomRepo(), history)
+
+	_, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{TaskID: task.ID, Value: "8", Suggested: "8"})
+
+	if !errors.Is(err, room.ErrTaskVersionConflict) {
+		t.Fatalf("expected ErrTaskVersionConflict, got %v", err)
+	}
+	if len(history.records[task.ID]) != 0 {
+		t.Errorf("expected no history record for an unsaved estimation, got %d", len(history.records[task.ID]))
+	}
+}
+
+func TestTaskService_AcceptEstimate_OverrideRequiresFacilitator(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	repo := newMockTaskRepo(task)
+	history := newMockHistoryRepo()
+	service := NewTaskService(repo, existingRoomRepo(), history)
+	ctx := context.Background()
+
+	_, err := service.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{TaskID: task.ID, Value: "13", Suggested: "8"})
+	if !errors.Is(err, room.ErrOverrideNotAllowed) {
+		t.Fatalf("expected ErrOverrideNotAllowed, got %v", err)
+	}
+	if repo.tasks[task.ID].Status == room.TaskStatusEstimated || len(history.records[task.ID]) != 0 {
+		t.Errorf("expected nothing to be saved, got status %s", repo.tasks[task.ID].Status)
+	}
+
+	// Anyone may accept the suggested value
+	if _, err := service.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{TaskID: task.ID, Value: "8", Suggested: "8"}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTaskService_AcceptEstimate_StoresBreakdown(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	repo := newMockTaskRepo(task)
+	roomRepo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return &room.Room{ID: id, RoomSettings: room.RoomSettings{
+				VotingSystem: room.DbsFibo,
+				Dimensions: []room.EstimationDimension{
+					{Key: "complexity", VotingSystem: room.DbsFibo},
+					{Key: "effort", VotingSystem: room.DbsFibo},
+				},
+				Combination: room.CombineSum,
+			}}, nil
+		},
+	}
+	service := NewTaskService(repo, roomRepo, newMockHistoryRepo())
+
+	accepted, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{
+		TaskID:    task.ID,
+		Value:     "8",
+		Suggested: "8",
+		Breakdown: map[string]string{"complexity": "3", "effort": "5"},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if accepted.EstimationBreakdown["complexity"] != "3" || accepted.EstimationBreakdown["effort"] != "5" {
+		t.Errorf("expected breakdown complexity=3 effort=5, got %v", accepted.EstimationBreakdown)
+	}
+
+	_, err = service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{
+		TaskID:    task.ID,
+		Value:     "8",
+		Breakdown: map[string]string{"risk": "3"},
+	})
+	if !errors.Is(err, room.ErrInvalidDimension) {
+		t.Errorf("expected ErrInvalidDimension, got %v", err)
+	}
+}
+
+func TestTaskService_AcceptEstimate_StoresPert(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	roomRepo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return &room.Room{ID: id, RoomSettings: room.RoomSettings{VotingSystem: room.DbsFibo, Mode: room.ModeThreePoint}}, nil
+		},
+	}
+	service := NewTaskService(newMockTaskRepo(task), roomRepo, newMockHistoryRepo())
+
+	pert := &dto.PertResp{
+		ThreePointResp: dto.ThreePointResp{Optimistic: 2, MostLikely: 3.5, Pessimistic: 9},
+		Expected:       4.17,
+		StdDev:         1.17,
+	}
+	accepted, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{
+		TaskID:    task.ID,
+		Value:     "5",
+		Suggested: "5",
+		Pert:      pert,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if accepted.Pert == nil || accepted.Pert.Expected != 4.17 || accepted.Pert.Pessimistic != 9 {
+		t.Errorf("expected PERT 4.17 within 2..9, got %+v", accepted.Pert)
+	}
+}
+
+func TestTaskService_AcceptEstimate_StoresCommentsAndChanges(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	_, _ = task.UpdateNotes([]string{"excludes migration"}, "Alice")
+	other, _ := room.NewTask("room123", "Signup page", 2)
+	historyRepo := newMockHistoryRepo()
+	service := NewTaskService(newMockTaskRepo(task, other), existingRoomRepo(), historyRepo)
+
+	_, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{
+		TaskID:    task.ID,
+		Value:     "5",
+		Suggested: "5",
+		Comments: []dto.VoteCommentResp{
+			{Author: "Alice", Value: "13", Comment: " legacy\ncode "},
+			{Author: "Bob", Value: "3", Comment: "  "},
+		},
+		Changes:    []dto.VoteChangeResp{{UserID: "bob", From: "3", To: "5"}},
+		VoterNames: map[string]string{"bob": "Bob"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	records := historyRepo.records[task.ID]
+	if len(records) != 1 {
+		t.Fatalf("expected 1 history record, got %d", len(records))
+	}
+	if notes := records[0].Notes; len(notes) != 1 || notes[0] != "excludes migration" {
+		t.Errorf("expected the decision log to be kept with the estimate, got %q", notes)
+	}
+	comments := records[0].Comments
+	if len(comments) != 1 || comments[0].Author != "Alice" || comments[0].Comment != "legacy code" {
+		t.Errorf("expected Alice's sanitized comment only, got %+v", comments)
+	}
+	if changes := records[0].Changes; len(changes) != 1 || changes[0] != (room.VoteChange{Voter: "bob", From: "3", To: "5"}) {
+		t.Errorf("expected Bob's change from 3 to 5 to be kept by user ID, got %+v", changes)
+	}
+
+	history, err := service.GetEstimationHistory(context.Background(), "room123", task.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if changes := history[0].Changes; len(changes) != 1 || changes[0] != (dto.VoteChangeResp{Voter: "Bob", From: "3", To: "5"}) {
+		t.Errorf("expected the history to name Bob, got %+v", changes)
+	}
+
+	_, err = service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{
+		Value:    "5",
+		Comments: []dto.VoteCommentResp{{Value: "5", Comment: strings.Repeat("a", room.MaxVoteCommentLength+1)}},
+	})
+	if !errors.Is(err, room.ErrVoteCommentTooLong) {
+		t.Errorf("expected ErrVoteCommentTooLong, got %v", err)
+	}
+}
+
+func TestTaskService_AcceptEstimate_FallsBackToNextOpenTask(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+
+	accepted, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{Value: "5", Suggested: "5"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if accepted.ID != task.ID || accepted.EstimationOverridden {
+		t.Errorf("expected task %s accepted without override, got %+v", task.ID, accepted)
+	}
+}
+
+func TestTaskService_AcceptEstimate_RejectsValueOutsideDeck(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+
+	_, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{TaskID: task.ID, Value: "7"})
+
+	if !errors.Is(err, room.ErrInvalidEstimation) {
+		t.Errorf("expected ErrInvalidEstimation, got %v", err)
+	}
+}
+
+func TestTaskService_AcceptEstimate_OtherRoomTask(t *testing.T) {
+	task, _ := room.NewTask("other", "Login page", 1)
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+
+	_, err := service.AcceptEstimate(context.Background(), "room123", &dto.AcceptEstimateReq{TaskID: task.ID, Value: "5"})
+
+	if !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestTaskService_NextTask_SkipsClosedTasks(t *testing.T) {
+	current, _ := room.NewTask("room123", "Current", 1)
+	done, _ := room.NewTask("room123", "Done", 2)
+	_ = done.SetEstimation("3", room.DbsFibo)
+	open, _ := room.NewTask("room123", "Open", 3)
+	service := NewTaskService(newMockTaskRepo(current, done, open), existingRoomRepo(), newMockHistoryRepo())
+
+	next, err := service.NextTask(context.Background(), "room123", current.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if next.ID != open.ID {
+		t.Errorf("expected task %s, got %s", open.ID, next.ID)
+	}
+}
+
+func TestTaskService_NextTask_StaleCurrentStartsFromTop(t *testing.T) {
+	first, _ := room.NewTask("room123", "First", 1)
+	service := NewTaskService(newMockTaskRepo(first), existingRoomRepo(), newMockHistoryRepo())
+
+	next, err := service.NextTask(context.Background(), "room123", "deleted-task")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if next.ID != first.ID {
+		t.Errorf("expected task %s, got %s", first.ID, next.ID)
+	}
+}
+
+func TestTaskService_GetRoomTask_OtherRoom(t *testing.T) {
+	foreign, _ := room.NewTask("other", "Foreign", 1)
+	service := NewTaskService(newMockTaskRepo(foreign), existingRoomRepo(), newMockHistoryRepo())
+
+	_, err := service.GetRoomTask(context.Background(), "room123", foreign.ID)
+
+	if !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestTaskService_Reestimate_KeepsHistory(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	history := newMockHistoryRepo()
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), history)
+	ctx := context.Background()
+
+	if _, err := service.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{
+		TaskID: task.ID, Value: "5", Suggested: "5", Participants: []string{"Alice", "Bob"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	reopened, err := service.ReestimateTask(ctx, "room123", task.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reopened.Status != string(room.TaskStatusPending) {
+		t.Errorf("expected status pending, got %s", reopened.Status)
+	}
+
+	resized, err := service.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{
+		TaskID: task.ID, Value: "13", Suggested: "8", Reason: "new integration", ByFacilitator: true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resized.PreviousEstimation != "5" {
+		t.Errorf("expected previous estimation 5, got %q", resized.PreviousEstimation)
+	}
+
+	records, err := service.GetEstimationHistory(ctx, "room123", task.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 history records, got %d", len(records))
+	}
+	if records[0].Source != "vote" || len(records[0].Participants) != 2 {
+		t.Errorf("unexpected first record %+v", records[0])
+	}
+	if records[1].Source != "override" || records[1].Value != "13" {
+		t.Errorf("unexpected second record %+v", records[1])
+	}
+}
+
+func TestTaskService_GetEstimationHistory_OtherRoom(t *testing.T) {
+	task, _ := room.NewTask("other", "Foreign", 1)
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+
+	_, err := service.GetEstimationHistory(context.Background(), "room123", task.ID)
+
+	if !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestTaskService_UpdateNotes_KeepsRevisions(t *testing.T) {
+	task, _ := room.NewTask("room123", "Checkout", 1)
+	taskRepo := newMockTaskRepo(task)
+	service := NewTaskService(taskRepo, existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+
+	if _, err := service.UpdateNotes(ctx, "room123", task.ID, "Alice", []string{"excludes migration"}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := service.UpdateNotes(ctx, "room123", task.ID, "Bob", []string{"excludes migration", "API already exists"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Notes == nil || len(resp.Notes.Items) != 2 || resp.Notes.Author != "Bob" {
+		t.Errorf("expected Bob's two notes, got %+v", resp.Notes)
+	}
+	if len(resp.NotesHistory) != 1 || resp.NotesHistory[0].Author != "Alice" {
+		t.Errorf("expected Alice's revision in the history, got %+v", resp.NotesHistory)
+	}
+	if stored := taskRepo.tasks[task.ID]; len(stored.NotesHistory) != 1 {
+		t.Errorf("expected the history to be persisted, got %d revisions", len(stored.NotesHistory))
+	}
+
+	if _, err := service.UpdateNotes(ctx, "other", task.ID, "Eve", []string{"x"}, true); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for another room, got %v", err)
+	}
+}
+
+func TestTaskService_UpdateNotes_FacilitatorOnly(t *testing.T) {
+	task, _ := room.NewTask("room123", "Checkout", 1)
+	taskRepo := newMockTaskRepo(task)
+	service := NewTaskService(taskRepo, existingRoomRepo(), newMockHistoryRepo())
+
+	_, err := service.UpdateNotes(context.Background(), "room123", task.ID, "Bob", []string{"excludes migration"}, false)
+	if !errors.Is(err, room.ErrNotesNotAllowed) {
+		t.Errorf("expected ErrNotesNotAllowed, got %v", err)
+	}
+	if stored := taskRepo.tasks[task.ID]; stored.Notes != nil {
+		t.Errorf("expected the decision log to stay empty, got %+v", stored.Notes)
+	}
+}
+
+func TestTaskService_AcceptanceCriteria(t *testing.T) {
+	task, _ := room.NewTask("room123", "Checkout", 1)
+	service := NewTaskService(newMockTaskRepo(task), existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+
+	if _, err := service.AddCriterion(ctx, "room123", task.ID, "Pays with a saved card"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := service.AddCriterion(ctx, "room123", task.ID, "Shows a receipt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.AcceptanceCriteria) != 2 {
+		t.Fatalf("expected 2 criteria, got %d", len(resp.AcceptanceCriteria))
+	}
+	first, second := resp.AcceptanceCriteria[0].ID, resp.AcceptanceCriteria[1].ID
+
+	done := true
+	resp, err = service.UpdateCriterion(ctx, "room123", task.ID, first, &dto.UpdateCriterionReq{Done: &done})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.AcceptanceCriteria[0].Done || resp.AcceptanceCriteria[0].Text != "Pays with a saved card" {
+		t.Errorf("expected first criterion checked with its text kept, got %+v", resp.AcceptanceCriteria[0])
+	}
+
+	resp, err = service.ReorderCriteria(ctx, "room123", task.ID, []string{second, first})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.AcceptanceCriteria[0].ID != second {
+		t.Errorf("expected %s first, got %s", second, resp.AcceptanceCriteria[0].ID)
+	}
+
+	resp, err = service.DeleteCriterion(ctx, "room123", task.ID, second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.AcceptanceCriteria) != 1 {
+		t.Errorf("expected 1 criterion left, got %d", len(resp.AcceptanceCriteria))
+	}
+
+	if _, err := service.AddCriterion(ctx, "other", task.ID, "Sneaky"); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for another room, got %v", err)
+	}
+}
+
+func TestTaskService_GetRoomTasks_Filtered(t *testing.T) {
+	story, _ := room.NewTask("room123", "Story", 1)
+	_ = story.SetLabels([]string{"backend"})
+	bug, _ := room.NewTask("room123", "Bug", 2)
+	_ = bug.SetType("bug")
+	service := NewTaskService(newMockTaskRepo(story, bug), existingRoomRepo(), newMockHistoryRepo())
+
+	tasks, err := service.GetRoomTasks(context.Background(), "room123", dto.TaskFilterReq{Types: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != bug.ID {
+		t.Errorf("expected only the bug, got %+v", tasks)
+	}
+
+	if _, err := service.GetRoomTasks(context.Background(), "room123", dto.TaskFilterReq{Types: []string{"epic"}}); !errors.Is(err, room.ErrUnknownTaskType) {
+		t.Errorf("expected ErrUnknownTaskType, got %v", err)
+	}
+}
+
+func TestTaskService_NextTask_UsesSavedFilter(t *testing.T) {
+	first, _ := room.NewTask("room123", "First story", 1)
+	bug, _ := room.NewTask("room123", "Bug", 2)
+	_ = bug.SetType("bug")
+	second, _ := room.NewTask("room123", "Second story", 3)
+	filter, _ := room.NewTaskFilter(nil, []string{"story"}, nil)
+	roomRepo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return &room.Room{ID: id, RoomSettings: room.RoomSettings{VotingSystem: room.DbsFibo, TaskFilter: filter}}, nil
+		},
+	}
+	service := NewTaskService(newMockTaskRepo(first, bug, second), roomRepo, newMockHistoryRepo())
+
+	next, err := service.NextTask(context.Background(), "room123", first.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if next.ID != second.ID {
+		t.Errorf("expected the bug to be skipped, got %s", next.Headline)
+	}
+
+	_ = first.SetEstimation("3", room.DbsFibo)
+	unestimated, err := service.GetNextUnestimatedTask(context.Background(), "room123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if unestimated.ID != second.ID {
+		t.Errorf("expected next unestimated story, got %s", unestimated.Headline)
+	}
+}
+
+func TestTaskService_Hierarchy(t *testing.T) {
+	epic, _ := room.NewTask("room123", "Checkout", 1)
+	other, _ := room.NewTask("room123", "Search", 2)
+	repo := newMockTaskRepo(epic, other)
+	service := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+
+	sub, err := service.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Pay by card", ParentID: epic.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sub.Position != 2 {
+		t.Errorf("expected the subtask right after its epic, got position %d", sub.Position)
+	}
+	if tasks, _ := repo.GetByRoomID(ctx, "room123"); tasks[1].ID != sub.ID || tasks[2].ID != other.ID {
+		t.Errorf("expected the subtask between its epic and the next task, got %+v", tasks)
+	}
+
+	if _, err := service.SetTaskParent(ctx, "room123", epic.ID, sub.ID); !errors.Is(err, room.ErrTaskHierarchyCycle) {
+		t.Errorf("expected ErrTaskHierarchyCycle, got %v", err)
+	}
+
+	// Moving the epic to the end takes its subtask along
+	order, err := service.ReorderTasks(ctx, "room123", &dto.ReorderTasksReq{TaskIDs: []string{other.ID, sub.ID, epic.ID}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(order, ",") != strings.Join([]string{other.ID, epic.ID, sub.ID}, ",") {
+		t.Errorf("expected the subtask to follow its epic, got %v", order)
+	}
+
+	_, _ = service.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{TaskID: sub.ID, Value: "5", Suggested: "5"})
+	tree, err := service.GetRoomTaskTree(ctx, "room123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tree) != 2 || tree[1].Rollup == nil || tree[1].Rollup.Points != "5" || len(tree[1].Subtasks) != 1 {
+		t.Errorf("expected the epic to roll up 5 from its subtask, got %+v", tree)
+	}
+
+	moved, err := service.DeleteTask(ctx, "room123", epic.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(moved) != 1 || repo.tasks[sub.ID].ParentID != "" {
+		t.Errorf("expected the subtask to move to the top level, got %+v", moved)
+	}
+}
+
+func TestTaskService_Dependencies(t *testing.T) {
+	first, _ := room.NewTask("room123", "API", 1)
+	second, _ := room.NewTask("room123", "Database", 2)
+	stranger, _ := room.NewTask("other", "Elsewhere", 1)
+	service := NewTaskService(newMockTaskRepo(first, second, stranger), existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+
+	resp, err := service.AddDependency(ctx, "room123", first.ID, second.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.DependsOn) != 1 || resp.DependsOn[0] != second.ID {
+		t.Errorf("expected the API to depend on the database, got %v", resp.DependsOn)
+	}
+
+	if _, err := service.AddDependency(ctx, "room123", second.ID, first.ID); !errors.Is(err, room.ErrTaskDependencyCycle) {
+		t.Errorf("expected ErrTaskDependencyCycle, got %v", err)
+	}
+	if _, err := service.AddDependency(ctx, "room123", first.ID, stranger.ID); !errors.Is(err, room.ErrDependencyTaskNotFound) {
+		t.Errorf("expected ErrDependencyTaskNotFound for another room's task, got %v", err)
+	}
+
+	order, err := service.ProposeTaskOrder(ctx, "room123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(order) != 2 || order[0] != second.ID {
+		t.Errorf("expected the database first, got %v", order)
+	}
+
+	resp, err = service.RemoveDependency(ctx, "room123", first.ID, second.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.DependsOn) != 0 {
+		t.Errorf("expected no dependencies left, got %v", resp.DependsOn)
+	}
+}
+
+func TestTaskService_MoveTask(t *testing.T) {
+	first, _ := room.NewTask("room123", "First", 1)
+	second, _ := room.NewTask("room123", "Second", 2)
+	third, _ := room.NewTask("room123", "Third", 3)
+	elsewhere, _ := room.NewTask("room456", "Elsewhere", 1)
+	repo := newMockTaskRepo(first, second, third, elsewhere)
+	service := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+	firstRank, secondRank := first.Rank, second.Rank
+
+	moved, order, err := service.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: third.ID, BeforeID: first.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if moved.Position != 1 || strings.Join(order, ",") != strings.Join([]string{third.ID, first.ID, second.ID}, ",") {
+		t.Errorf("expected the third task to move to the top, got position %d and order %v", moved.Position, order)
+	}
+	if first.Rank != firstRank || second.Rank != secondRank {
+		t.Error("expected only the moved task to be re-ranked")
+	}
+
+	// A move racing with another client for the same place is retried
+	repo.rankConflicts = 2
+	if _, order, err = service.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: third.ID, AfterID: second.ID}); err != nil {
+		t.Fatalf("expected the move to succeed after retrying, got %v", err)
+	}
+	if order[2] != third.ID {
+		t.Errorf("expected the third task back at the end, got %v", order)
+	}
+	repo.rankConflicts = maxRankAttempts
+	if _, _, err := service.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: third.ID, BeforeID: first.ID}); !errors.Is(err, room.ErrTaskRankTaken) {
+		t.Errorf("expected ErrTaskRankTaken once retries run out, got %v", err)
+	}
+
+	if _, _, err := service.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: third.ID, BeforeID: first.ID, AfterID: second.ID}); !errors.Is(err, room.ErrInvalidTaskMove) {
+		t.Errorf("expected ErrInvalidTaskMove with two anchors, got %v", err)
+	}
+	if _, _, err := service.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: third.ID, AfterID: elsewhere.ID}); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for an anchor of another room, got %v", err)
+	}
+}
+
+func TestTaskService_UpdateTask_RejectsOutdatedVersion(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login", 1)
+	repo := newMockTaskRepo(task)
+	service := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+
+	if _, err := service.UpdateTask(ctx, "room123", task.ID, &dto.UpdateTaskReq{Headline: "Sign in"}); !errors.Is(err, room.ErrTaskVersionRequired) {
+		t.Errorf("expected ErrTaskVersionRequired, got %v", err)
+	}
+
+	// Alice and Bob both start from version 1, Alice saves first
+	version := task.Version
+	updated, err := service.UpdateTask(ctx, "room123", task.ID, &dto.UpdateTaskReq{Version: &version, Description: "Alice's notes"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Version != version+1 {
+		t.Errorf("expected version %d after saving, got %d", version+1, updated.Version)
+	}
+
+	_, err = service.UpdateTask(ctx, "room123", task.ID, &dto.UpdateTaskReq{Version: &version, Description: "Bob's notes"})
+	var conflict *TaskConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, room.ErrTaskVersionConflict) {
+		t.Fatalf("expected a TaskConflictError, got %v", err)
+	}
+	if conflict.Current.Description != "Alice's notes" || conflict.Current.Version != updated.Version {
+		t.Errorf("expected the conflict to carry Alice's version, got %+v", conflict.Current)
+	}
+	if repo.tasks[task.ID].Description != "Alice's notes" {
+		t.Errorf("expected Bob's edit not to be saved, got %q", repo.tasks[task.ID].Description)
+	}
+
+	// Bob merges on top of the current version
+	_, err = service.UpdateTask(ctx, "room123", task.ID, &dto.UpdateTaskReq{Version: &conflict.Current.Version, Description: "Alice's and Bob's notes"})
+	if err != nil {
+		t.Fatalf("expected the merged edit to be saved, got %v", err)
+	}
+}
+
+func TestTaskService_TasksOfOtherRoomsAreNotFound(t *testing.T) {
+	foreign, _ := room.NewTask("other", "Foreign", 1)
+	blocker, _ := room.NewTask("other", "Foreign blocker", 2)
+	foreign.DependsOn = []string{blocker.ID}
+	own, _ := room.NewTask("room123", "Own", 1)
+	repo := newMockTaskRepo(foreign, blocker, own)
+	service := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	ctx := context.Background()
+	version := foreign.Version
+
+	attempts := []struct {
+		name    string
+		attempt func() error
+	}{
+		{"update", func() error {
+			_, err := service.UpdateTask(ctx, "room123", foreign.ID, &dto.UpdateTaskReq{Version: &version, Headline: "Hijacked"})
+			return err
+		}},
+		{"delete", func() error {
+			_, err := service.DeleteTask(ctx, "room123", foreign.ID)
+			return err
+		}},
+		{"skip", func() error {
+			_, err := service.SkipTask(ctx, "room123", foreign.ID)
+			return err
+		}},
+		{"defer", func() error {
+			_, err := service.DeferTask(ctx, "room123", foreign.ID)
+			return err
+		}},
+		{"reestimate", func() error {
+			_, err := service.ReestimateTask(ctx, "room123", foreign.ID)
+			return err
+		}},
+		{"accept estimate", func() error {
+			_, err := service.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{TaskID: foreign.ID, Value: "5", Suggested: "5"})
+			return err
+		}},
+		{"move", func() error {
+			_, _, err := service.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: foreign.ID, AfterID: own.ID})
+			return err
+		}},
+		{"remove dependency", func() error {
+			_, err := service.RemoveDependency(ctx, "room123", foreign.ID, blocker.ID)
+			return err
+		}},
+	}
+
+	for _, tt := range attempts {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.attempt(); !errors.Is(err, room.ErrTaskNotFound) {
+				t.Errorf("expected ErrTaskNotFound, got %v", err)
+			}
+		})
+	}
+
+	stored, ok := repo.tasks[foreign.ID]
+	if !ok {
+		t.Fatal("expected the foreign task to still exist")
+	}
+	if stored.Headline != "Foreign" || stored.Status != room.TaskStatusPending || stored.Estimation != "" || stored.Version != version {
+		t.Errorf("expected the foreign task to be untouched, got %+v", stored)
+	}
+	if len(stored.DependsOn) != 1 {
+		t.Errorf("expected the foreign dependency to be kept, got %v", stored.DependsOn)
+	}
+}
@@ -0,0 +1,23 @@
+package application
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/vitaly-stepin/agile_party/internal/application"
+
+const (
+	roomIDKey = attribute.Key("room.id")
+	userIDKey = attribute.Key("user.id")
+	taskIDKey = attribute.Key("task.id")
+)
+
+// startSpan opens a span for a service call; the tracer is resolved per call
+// so a provider registered after package init (e.g. in tests) is picked up
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
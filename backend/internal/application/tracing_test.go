@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vitaly-stepin/agile_party/internal/adapters/tracing"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+func TestVotingService_SubmitVote_RecordsSpan(t *testing.T) {
+	tp, exporter := tracing.NewInMemoryProvider()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{
+		VotingSystem: room.DbsFibo,
+		AutoReveal:   false,
+	})
+
+	var repoCtx context.Context
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			repoCtx = ctx
+			return testRoom, nil
+		},
+	}
+	service := NewVotingService(repo, &mockStateManager{}, newMockTaskRepo())
+
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "VotingService.SubmitVote" {
+		t.Errorf("expected span name 'VotingService.SubmitVote', got '%s'", span.Name)
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["room.id"] != testRoom.ID {
+		t.Errorf("expected room.id '%s', got '%s'", testRoom.ID, attrs["room.id"])
+	}
+	if attrs["user.id"] != "user1" {
+		t.Errorf("expected user.id 'user1', got '%s'", attrs["user.id"])
+	}
+
+	// The repository must receive the span context so SQL spans nest under the service span
+	if got := trace.SpanContextFromContext(repoCtx).SpanID(); got != span.SpanContext.SpanID() {
+		t.Errorf("expected repo call to run inside service span %s, got %s", span.SpanContext.SpanID(), got)
+	}
+}
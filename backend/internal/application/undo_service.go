@@ -0,0 +1,347 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+// UndoService keeps a short per-room history of destructive changes (task deletion,
+// backlog reorder or move, accepted estimate and cleared round) and reverts the latest one
+type UndoService struct {
+	roomRepo    ports.RoomRepo
+	taskRepo    ports.TaskRepo
+	historyRepo ports.EstimationHistoryRepo
+	stateMgr    ports.RoomStateManager
+}
+
+func NewUndoService(
+	roomRepo ports.RoomRepo,
+	taskRepo ports.TaskRepo,
+	historyRepo ports.EstimationHistoryRepo,
+	stateMgr ports.RoomStateManager,
+) *UndoService {
+	return &UndoService{
+		roomRepo:    roomRepo,
+		taskRepo:    taskRepo,
+		historyRepo: historyRepo,
+		stateMgr:    stateMgr,
+	}
+}
+
+// Change is a snapshot taken right before a destructive change; once the change
+// has succeeded it is handed to Record to make it undoable
+type Change struct {
+	entry ports.UndoEntry
+}
+
+// PrepareDelete snapshots a task and its subtasks before DeleteTask
+func (s *UndoService) PrepareDelete(ctx context.Context, roomID, userID, taskID string) (*Change, error) {
+	ctx, span := startSpan(ctx, "UndoService.PrepareDelete", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	tree := room.NewTaskTree(tasks)
+	task, err := tree.Find(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Change{entry: ports.UndoEntry{
+		Action:   ports.UndoDeleteTask,
+		UserID:   userID,
+		Task:     task,
+		Subtasks: taskIDs(tree.Subtasks(taskID)),
+	}}, nil
+}
+
+// PrepareReorder snapshots the backlog order before ReorderTasks
+func (s *UndoService) PrepareReorder(ctx context.Context, roomID, userID string) (*Change, error) {
+	ctx, span := startSpan(ctx, "UndoService.PrepareReorder", roomIDKey.String(roomID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	return &Change{entry: ports.UndoEntry{
+		Action: ports.UndoReorderTasks,
+		UserID: userID,
+		Order:  taskIDs(tasks),
+	}}, nil
+}
+
+// PrepareMove snapshots a task's parent and the backlog order before MoveTask or SetTaskParent
+func (s *UndoService) PrepareMove(ctx context.Context, roomID, userID, taskID string) (*Change, error) {
+	ctx, span := startSpan(ctx, "UndoService.PrepareMove", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	task, err := room.NewTaskTree(tasks).Find(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Change{entry: ports.UndoEntry{
+		Action: ports.UndoMoveTask,
+		UserID: userID,
+		Task:   task,
+		Order:  taskIDs(tasks),
+	}}, nil
+}
+
+// PrepareClear snapshots the voting round before it is cleared
+func (s *UndoService) PrepareClear(ctx context.Context, roomID, userID string) (*Change, error) {
+	round, err := s.round(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Change{entry: ports.UndoEntry{
+		Action: ports.UndoClear,
+		UserID: userID,
+		Round:  round,
+	}}, nil
+}
+
+// PrepareAcceptEstimate snapshots the task about to be estimated, the room's next open
+// task when taskID is empty, together with the round that estimates it
+func (s *UndoService) PrepareAcceptEstimate(ctx context.Context, roomID, userID, taskID string) (*Change, error) {
+	ctx, span := startSpan(ctx, "UndoService.PrepareAcceptEstimate", roomIDKey.String(roomID), taskIDKey.String(taskID))
+	defer span.End()
+
+	var task *room.Task
+	var err error
+	if taskID != "" {
+		task, err = s.taskRepo.GetByID(ctx, roomID, taskID)
+	} else {
+		var rm *room.Room
+		if rm, err = s.roomRepo.GetByID(ctx, roomID); err != nil {
+			return nil, err
+		}
+		task, err = s.taskRepo.GetNextUnestimatedTask(ctx, roomID, rm.TaskFilter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	round, err := s.round(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Change{entry: ports.UndoEntry{
+		Action: ports.UndoAcceptEstimate,
+		UserID: userID,
+		Task:   task,
+		Round:  round,
+	}}, nil
+}
+
+func (s *UndoService) round(roomID string) (*ports.LiveRoomState, error) {
+	round, err := s.stateMgr.GetRoomState(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room state: %w", err)
+	}
+	round.LastChange = nil
+	return round, nil
+}
+
+// Record makes a change undoable and returns the room's latest undoable change
+func (s *UndoService) Record(roomID string, change *Change) (*dto.ChangeResp, error) {
+	if err := s.stateMgr.PushUndo(roomID, change.entry); err != nil {
+		return nil, fmt.Errorf("failed to record change: %w", err)
+	}
+	return dto.FromDomainChange(&change.entry), nil
+}
+
+// Undo reverts the room's latest change. Only the user who made it or the facilitator may undo it.
+// The change is dropped before it is reverted, so one that can no longer be reverted does not
+// keep the older ones from being undone.
+func (s *UndoService) Undo(ctx context.Context, roomID, userID string) (*dto.UndoResp, error) {
+	ctx, span := startSpan(ctx, "UndoService.Undo", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
+	state, err := s.stateMgr.GetRoomState(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room state: %w", err)
+	}
+	entry := state.LastChange
+	if entry == nil {
+		return nil, room.ErrNothingToUndo
+	}
+	if entry.UserID != userID && state.FacilitatorID != userID {
+		return nil, room.ErrUndoNotAllowed
+	}
+
+	if err := s.stateMgr.PopUndo(roomID); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.UndoResp{Undone: *dto.FromDomainChange(entry)}
+	switch entry.Action {
+	case ports.UndoDeleteTask:
+		err = s.restoreTask(ctx, roomID, entry)
+		resp.RestoredTasks = true
+	case ports.UndoReorderTasks:
+		err = s.restoreOrder(ctx, roomID, entry.Order)
+		resp.RestoredTasks = true
+	case ports.UndoMoveTask:
+		err = s.restoreMove(ctx, roomID, entry)
+		resp.RestoredTasks = true
+	case ports.UndoAcceptEstimate:
+		err = s.revertEstimate(ctx, roomID, entry)
+		resp.RestoredTasks = true
+		resp.RestoredRound = true
+	case ports.UndoClear:
+		err = s.stateMgr.RestoreRound(roomID, entry.Round)
+		resp.RestoredRound = true
+	default:
+		err = fmt.Errorf("unknown change: %s", entry.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state, err = s.stateMgr.GetRoomState(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room state: %w", err)
+	}
+	resp.LastChange = dto.FromDomainChange(state.LastChange)
+	return resp, nil
+}
+
+// restoreTask brings a deleted task back at its old place, moving its subtasks back under it
+// unless they have left the parent they moved up to since
+func (s *UndoService) restoreTask(ctx context.Context, roomID string, entry *ports.UndoEntry) error {
+	var tree *room.TaskTree
+	var restored *room.Task
+	err := retryRankTaken(func() error {
+		tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
+
+		taskCopy := *entry.Task
+		restored = &taskCopy
+		tree = room.NewTaskTree(tasks)
+		if err := tree.Insert(restored); err != nil {
+			return err
+		}
+		return s.taskRepo.Restore(ctx, restored)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	for _, subtaskID := range entry.Subtasks {
+		subtask, err := tree.Find(subtaskID)
+		if err != nil || tree.Parent(subtaskID) != entry.Task.ParentID {
+			continue
+		}
+		if err := tree.SetParent(subtaskID, restored.ID); err != nil {
+			return err
+		}
+		if err := s.taskRepo.Move(ctx, subtask); err != nil {
+			return fmt.Errorf("failed to move subtask: %w", err)
+		}
+	}
+	return nil
+}
+
+// restoreMove puts a moved task back under its old parent, when that parent is still there,
+// and the backlog back in its old order
+func (s *UndoService) restoreMove(ctx context.Context, roomID string, entry *ports.UndoEntry) error {
+	err := retryRankTaken(func() error {
+		tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
+
+		tree := room.NewTaskTree(tasks)
+		task, err := tree.Find(entry.Task.ID)
+		if err != nil || tree.Parent(task.ID) == entry.Task.ParentID {
+			return nil
+		}
+		if err := tree.SetParent(task.ID, entry.Task.ParentID); err != nil {
+			if errors.Is(err, room.ErrParentTaskNotFound) || errors.Is(err, room.ErrTaskHierarchyCycle) {
+				return nil
+			}
+			return err
+		}
+		return s.taskRepo.Move(ctx, task)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move task back: %w", err)
+	}
+
+	return s.restoreOrder(ctx, roomID, entry.Order)
+}
+
+// restoreOrder puts the backlog back in the given order; tasks created since keep
+// their relative order after the others
+func (s *UndoService) restoreOrder(ctx context.Context, roomID string, order []string) error {
+	tasks, err := s.taskRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	place := make(map[string]int, len(order))
+	for i, taskID := range order {
+		place[taskID] = i + 1
+	}
+	for _, task := range tasks {
+		if position, ok := place[task.ID]; ok {
+			task.Position = position
+		} else {
+			task.Position = len(order) + task.Position
+		}
+	}
+
+	tree := room.NewTaskTree(tasks)
+	tree.Rerank()
+	if err := s.taskRepo.UpdateRanks(ctx, roomID, tree.Number()); err != nil {
+		return fmt.Errorf("failed to update task ranks: %w", err)
+	}
+	return nil
+}
+
+// revertEstimate gives the task back the estimation it had, drops the estimate
+// from its history and reopens the round that produced it
+func (s *UndoService) revertEstimate(ctx context.Context, roomID string, entry *ports.UndoEntry) error {
+	task, err := s.taskRepo.GetByID(ctx, roomID, entry.Task.ID)
+	if err != nil {
+		return err
+	}
+	if err := task.RevertEstimation(entry.Task); err != nil {
+		return err
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return fmt.Errorf("failed to revert estimation: %w", err)
+	}
+
+	records, err := s.historyRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get estimation history: %w", err)
+	}
+	if len(records) > 0 {
+		if err := s.historyRepo.Delete(ctx, records[len(records)-1].ID); err != nil {
+			return fmt.Errorf("failed to remove estimation record: %w", err)
+		}
+	}
+
+	return s.stateMgr.RestoreRound(roomID, entry.Round)
+}
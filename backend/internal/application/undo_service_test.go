@@ -0,0 +1,287 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+// undoStateManager keeps an undo stack and the live round like the memory adapter does
+func undoStateManager(facilitatorID string, round *ports.LiveRoomState) *mockStateManager {
+	var stack []ports.UndoEntry
+	return &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			state := *round
+			state.FacilitatorID = facilitatorID
+			state.LastChange = nil
+			if len(stack) > 0 {
+				state.LastChange = &stack[len(stack)-1]
+			}
+			return &state, nil
+		},
+		pushUndoFunc: func(roomID string, entry ports.UndoEntry) error {
+			stack = append(stack, entry)
+			return nil
+		},
+		popUndoFunc: func(roomID string) error {
+			if len(stack) == 0 {
+				return room.ErrNothingToUndo
+			}
+			stack = stack[:len(stack)-1]
+			return nil
+		},
+		restoreRoundFunc: func(roomID string, restored *ports.LiveRoomState) error {
+			*round = *restored
+			return nil
+		},
+	}
+}
+
+func TestUndoService_UndoDeleteRestoresTaskAndSubtasks(t *testing.T) {
+	repo := newMockTaskRepo()
+	tasks := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	undo := NewUndoService(existingRoomRepo(), repo, newMockHistoryRepo(), undoStateManager("alice", &ports.LiveRoomState{}))
+	ctx := context.Background()
+
+	epic, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Checkout"})
+	sub, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Pay by card", ParentID: epic.ID})
+	other, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Search"})
+
+	change, err := undo.PrepareDelete(ctx, "room123", "bob", epic.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := tasks.DeleteTask(ctx, "room123", epic.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := undo.Record("room123", change); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	resp, err := undo.Undo(ctx, "room123", "bob")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.RestoredTasks || resp.Undone.Action != string(ports.UndoDeleteTask) || resp.LastChange != nil {
+		t.Errorf("expected the deletion to be undone with nothing left, got %+v", resp)
+	}
+
+	order, _ := repo.GetByRoomID(ctx, "room123")
+	if strings.Join(taskIDs(order), ",") != strings.Join([]string{epic.ID, sub.ID, other.ID}, ",") {
+		t.Errorf("expected the epic back in front with its subtask, got %v", taskIDs(order))
+	}
+	if repo.tasks[sub.ID].ParentID != epic.ID {
+		t.Errorf("expected the subtask back under its epic, got parent %q", repo.tasks[sub.ID].ParentID)
+	}
+}
+
+func TestUndoService_UndoDeleteRestoresNestedEpic(t *testing.T) {
+	repo := newMockTaskRepo()
+	tasks := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	undo := NewUndoService(existingRoomRepo(), repo, newMockHistoryRepo(), undoStateManager("alice", &ports.LiveRoomState{}))
+	ctx := context.Background()
+
+	initiative, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Payments"})
+	epic, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Checkout", ParentID: initiative.ID})
+	sub, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Pay by card", ParentID: epic.ID})
+
+	change, _ := undo.PrepareDelete(ctx, "room123", "bob", epic.ID)
+	if _, err := tasks.DeleteTask(ctx, "room123", epic.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, _ = undo.Record("room123", change)
+	if repo.tasks[sub.ID].ParentID != initiative.ID {
+		t.Fatalf("expected the subtask to move up to the outer epic, got parent %q", repo.tasks[sub.ID].ParentID)
+	}
+
+	if _, err := undo.Undo(ctx, "room123", "bob"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if repo.tasks[epic.ID].ParentID != initiative.ID {
+		t.Errorf("expected the epic back under the outer epic, got parent %q", repo.tasks[epic.ID].ParentID)
+	}
+	if repo.tasks[sub.ID].ParentID != epic.ID {
+		t.Errorf("expected the subtask back under its epic, got parent %q", repo.tasks[sub.ID].ParentID)
+	}
+}
+
+func TestUndoService_UndoDeleteAfterPurge(t *testing.T) {
+	repo := newMockTaskRepo()
+	tasks := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	undo := NewUndoService(existingRoomRepo(), repo, newMockHistoryRepo(), undoStateManager("alice", &ports.LiveRoomState{}))
+	ctx := context.Background()
+
+	old, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Checkout"})
+	recent, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Search"})
+
+	change, _ := undo.PrepareDelete(ctx, "room123", "bob", old.ID)
+	_, _ = tasks.DeleteTask(ctx, "room123", old.ID)
+	_, _ = undo.Record("room123", change)
+	_, _ = tasks.DeleteTask(ctx, "room123", recent.ID)
+	repo.deletedAt[old.ID] = time.Now().Add(-8 * 24 * time.Hour)
+
+	purged, err := tasks.PurgeDeletedTasks(ctx, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purged != 1 || repo.deleted[recent.ID] == nil {
+		t.Errorf("expected only the task deleted before the retention to be purged, got %d", purged)
+	}
+
+	if _, err := undo.Undo(ctx, "room123", "bob"); !errors.Is(err, room.ErrTaskNotFound) {
+		t.Errorf("expected a purged task not to come back, got %v", err)
+	}
+}
+
+func TestUndoService_UndoReorderRestoresOrder(t *testing.T) {
+	repo := newMockTaskRepo()
+	tasks := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	undo := NewUndoService(existingRoomRepo(), repo, newMockHistoryRepo(), undoStateManager("alice", &ports.LiveRoomState{}))
+	ctx := context.Background()
+
+	first, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "First"})
+	second, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Second"})
+
+	change, _ := undo.PrepareReorder(ctx, "room123", "bob")
+	if _, err := tasks.ReorderTasks(ctx, "room123", &dto.ReorderTasksReq{TaskIDs: []string{second.ID, first.ID}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, _ = undo.Record("room123", change)
+	added, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Added later"})
+
+	if _, err := undo.Undo(ctx, "room123", "bob"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	order, _ := repo.GetByRoomID(ctx, "room123")
+	if strings.Join(taskIDs(order), ",") != strings.Join([]string{first.ID, second.ID, added.ID}, ",") {
+		t.Errorf("expected the previous order with the new task last, got %v", taskIDs(order))
+	}
+}
+
+func TestUndoService_UndoMoveRestoresParentAndOrder(t *testing.T) {
+	repo := newMockTaskRepo()
+	tasks := NewTaskService(repo, existingRoomRepo(), newMockHistoryRepo())
+	undo := NewUndoService(existingRoomRepo(), repo, newMockHistoryRepo(), undoStateManager("alice", &ports.LiveRoomState{}))
+	ctx := context.Background()
+
+	epic, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Checkout"})
+	sub, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Pay by card", ParentID: epic.ID})
+	other, _ := tasks.CreateTask(ctx, "room123", &dto.CreateTaskReq{Headline: "Search"})
+
+	change, err := undo.PrepareMove(ctx, "room123", "bob", sub.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, _, err := tasks.MoveTask(ctx, "room123", &dto.MoveTaskReq{TaskID: sub.ID, AfterID: other.ID}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, _ = undo.Record("room123", change)
+	if repo.tasks[sub.ID].ParentID != "" {
+		t.Fatalf("expected the move to take the task out of its epic, got parent %q", repo.tasks[sub.ID].ParentID)
+	}
+
+	resp, err := undo.Undo(ctx, "room123", "bob")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.RestoredTasks || resp.Undone.Action != string(ports.UndoMoveTask) {
+		t.Errorf("expected the move to be undone, got %+v", resp)
+	}
+
+	if repo.tasks[sub.ID].ParentID != epic.ID {
+		t.Errorf("expected the task back under its epic, got parent %q", repo.tasks[sub.ID].ParentID)
+	}
+	order, _ := repo.GetByRoomID(ctx, "room123")
+	if strings.Join(taskIDs(order), ",") != strings.Join([]string{epic.ID, sub.ID, other.ID}, ",") {
+		t.Errorf("expected the previous order, got %v", taskIDs(order))
+	}
+}
+
+func TestUndoService_UndoAcceptEstimateReopensRound(t *testing.T) {
+	task, _ := room.NewTask("room123", "Login page", 1)
+	_ = task.StartDiscussion()
+	repo := newMockTaskRepo(task)
+	history := newMockHistoryRepo()
+	tasks := NewTaskService(repo, existingRoomRepo(), history)
+	round := &ports.LiveRoomState{Votes: map[string]string{"bob": "5"}, IsRevealed: true, ActiveTaskID: task.ID}
+	undo := NewUndoService(existingRoomRepo(), repo, history, undoStateManager("alice", round))
+	ctx := context.Background()
+
+	change, err := undo.PrepareAcceptEstimate(ctx, "room123", "bob", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := tasks.AcceptEstimate(ctx, "room123", &dto.AcceptEstimateReq{TaskID: task.ID, Value: "5", Suggested: "5"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, _ = undo.Record("room123", change)
+	*round = ports.LiveRoomState{Votes: map[string]string{}, ActiveTaskID: "next"}
+
+	resp, err := undo.Undo(ctx, "room123", "bob")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.RestoredTasks || !resp.RestoredRound {
+		t.Errorf("expected both the backlog and the round to be restored, got %+v", resp)
+	}
+
+	stored := repo.tasks[task.ID]
+	if stored.Status != room.TaskStatusInDiscussion || stored.Estimation != "" {
+		t.Errorf("expected the task back in discussion without estimation, got %s %q", stored.Status, stored.Estimation)
+	}
+	if len(history.records[task.ID]) != 0 {
+		t.Errorf("expected the estimation to leave the history, got %d records", len(history.records[task.ID]))
+	}
+	if !round.IsRevealed || round.Votes["bob"] != "5" || round.ActiveTaskID != task.ID {
+		t.Errorf("expected the revealed round to come back, got %+v", round)
+	}
+}
+
+func TestUndoService_UndoClearRestoresRound(t *testing.T) {
+	round := &ports.LiveRoomState{Votes: map[string]string{"bob": "3"}, IsRevealed: true}
+	undo := NewUndoService(existingRoomRepo(), newMockTaskRepo(), newMockHistoryRepo(), undoStateManager("alice", round))
+	ctx := context.Background()
+
+	change, _ := undo.PrepareClear(ctx, "room123", "bob")
+	_, _ = undo.Record("room123", change)
+	*round = ports.LiveRoomState{Votes: map[string]string{}}
+
+	// The facilitator may undo anyone's change
+	resp, err := undo.Undo(ctx, "room123", "alice")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.RestoredTasks || !resp.RestoredRound {
+		t.Errorf("expected only the round to be restored, got %+v", resp)
+	}
+	if round.Votes["bob"] != "3" || !round.IsRevealed {
+		t.Errorf("expected the cleared votes back, got %+v", round)
+	}
+}
+
+func TestUndoService_UndoRestrictions(t *testing.T) {
+	undo := NewUndoService(existingRoomRepo(), newMockTaskRepo(), newMockHistoryRepo(), undoStateManager("alice", &ports.LiveRoomState{}))
+	ctx := context.Background()
+
+	if _, err := undo.Undo(ctx, "room123", "alice"); !errors.Is(err, room.ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+
+	change, _ := undo.PrepareClear(ctx, "room123", "bob")
+	_, _ = undo.Record("room123", change)
+
+	if _, err := undo.Undo(ctx, "room123", "carol"); !errors.Is(err, room.ErrUndoNotAllowed) {
+		t.Errorf("expected ErrUndoNotAllowed, got %v", err)
+	}
+	if _, err := undo.Undo(ctx, "room123", "bob"); err != nil {
+		t.Errorf("expected the author to undo their change, got %v", err)
+	}
+}
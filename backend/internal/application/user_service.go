@@ -2,8 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
 	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
@@ -11,6 +13,7 @@ import (
 type UserService struct {
 	roomRepo ports.RoomRepo
 	stateMgr ports.RoomStateManager
+	limits   room.RoomLimits
 }
 
 func NewUserService(roomRepo ports.RoomRepo, stateMgr ports.RoomStateManager) *UserService {
@@ -20,7 +23,15 @@ func NewUserService(roomRepo ports.RoomRepo, stateMgr ports.RoomStateManager) *U
 	}
 }
 
+// SetLimits applies per-room caps to subsequent joins
+func (s *UserService) SetLimits(limits room.RoomLimits) {
+	s.limits = limits
+}
+
 func (s *UserService) JoinRoom(ctx context.Context, roomID, userID, userName string) error {
+	ctx, span := startSpan(ctx, "UserService.JoinRoom", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
 	if roomID == "" {
 		return room.ErrInvalidRoomID
 	}
@@ -45,7 +56,10 @@ func (s *UserService) JoinRoom(ctx context.Context, roomID, userID, userName str
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
-	if err := s.stateMgr.AddUser(roomID, user); err != nil {
+	if err := s.stateMgr.AddUser(roomID, user, s.limits); err != nil {
+		if errors.Is(err, room.ErrRoomFull) {
+			return err
+		}
 		return fmt.Errorf("failed to add user to room: %w", err)
 	}
 
@@ -53,6 +67,9 @@ func (s *UserService) JoinRoom(ctx context.Context, roomID, userID, userName str
 }
 
 func (s *UserService) LeaveRoom(ctx context.Context, roomID, userID string) error {
+	_, span := startSpan(ctx, "UserService.LeaveRoom", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
 	if roomID == "" {
 		return room.ErrInvalidRoomID
 	}
@@ -68,6 +85,9 @@ func (s *UserService) LeaveRoom(ctx context.Context, roomID, userID string) erro
 }
 
 func (s *UserService) UpdateUserName(ctx context.Context, roomID, userID, newName string) error {
+	_, span := startSpan(ctx, "UserService.UpdateUserName", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
 	if roomID == "" {
 		return room.ErrInvalidRoomID
 	}
@@ -90,3 +110,74 @@ func (s *UserService) UpdateUserName(ctx context.Context, roomID, userID, newNam
 
 	return nil
 }
+
+// RaiseHand puts the user in the room's hand queue with a question or split signal
+func (s *UserService) RaiseHand(ctx context.Context, roomID, userID, signal string) error {
+	_, span := startSpan(ctx, "UserService.RaiseHand", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
+	if roomID == "" {
+		return room.ErrInvalidRoomID
+	}
+	if userID == "" {
+		return room.ErrInvalidUserID
+	}
+
+	handSignal, err := room.ParseHandSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	if err := s.stateMgr.RaiseHand(roomID, userID, handSignal); err != nil {
+		return fmt.Errorf("failed to raise hand: %w", err)
+	}
+
+	return nil
+}
+
+// LowerHand takes userID out of the hand queue, whether they lower it themselves or the
+// facilitator (byUserID) calls on them
+func (s *UserService) LowerHand(ctx context.Context, roomID, byUserID, userID string) error {
+	_, span := startSpan(ctx, "UserService.LowerHand", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
+	if roomID == "" {
+		return room.ErrInvalidRoomID
+	}
+	if userID == "" || byUserID == "" {
+		return room.ErrInvalidUserID
+	}
+
+	if byUserID != userID {
+		state, err := s.stateMgr.GetRoomState(roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get room state: %w", err)
+		}
+		if state.FacilitatorID != byUserID {
+			return room.ErrLowerHandNotAllowed
+		}
+	}
+
+	if err := s.stateMgr.LowerHand(roomID, userID); err != nil {
+		return fmt.Errorf("failed to lower hand: %w", err)
+	}
+
+	return nil
+}
+
+// GetHandQueue lists the raised hands, first raised first
+func (s *UserService) GetHandQueue(ctx context.Context, roomID string) ([]dto.RaisedHandResp, error) {
+	_, span := startSpan(ctx, "UserService.GetHandQueue", roomIDKey.String(roomID))
+	defer span.End()
+
+	if roomID == "" {
+		return nil, room.ErrInvalidRoomID
+	}
+
+	state, err := s.stateMgr.GetRoomState(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromDomainRaisedHands(state.RaisedHands), nil
+}
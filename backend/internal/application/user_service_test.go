@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
@@ -75,7 +76,7 @@ func TestUserService_JoinRoom_StateMgrError(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{
-		addUserFunc: func(roomID string, user *room.User) error {
+		addUserFunc: func(roomID string, user *room.User, limits room.RoomLimits) error {
 			return errors.New("state manager error")
 		},
 	}
@@ -88,6 +89,36 @@ func TestUserService_JoinRoom_StateMgrError(t *testing.T) {
 	}
 }
 
+func TestUserService_JoinRoom_RoomFull(t *testing.T) {
+	repo := &mockRoomRepo{
+		existsFunc: func(ctx context.Context, id string) (bool, error) {
+			return true, nil
+		},
+	}
+	added := false
+	stateMgr := &mockStateManager{
+		addUserFunc: func(roomID string, user *room.User, limits room.RoomLimits) error {
+			// The room already holds two users
+			if err := limits.CheckParticipantCount(2); err != nil {
+				return err
+			}
+			added = true
+			return nil
+		},
+	}
+	service := NewUserService(repo, stateMgr)
+	service.SetLimits(room.RoomLimits{MaxParticipants: 2})
+
+	err := service.JoinRoom(context.Background(), "room123", "user3", "Carol")
+
+	if !errors.Is(err, room.ErrRoomFull) {
+		t.Errorf("expected ErrRoomFull, got %v", err)
+	}
+	if added {
+		t.Error("expected user not to be added to a full room")
+	}
+}
+
 func TestUserService_LeaveRoom_Success(t *testing.T) {
 	repo := &mockRoomRepo{}
 	stateMgr := &mockStateManager{}
@@ -236,3 +267,53 @@ func TestUserService_UpdateUserName_StateMgrError(t *testing.T) {
 		t.Fatal("expected error from state manager, got nil")
 	}
 }
+
+func TestUserService_LowerHand(t *testing.T) {
+	var lowered []string
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{FacilitatorID: "alice"}, nil
+		},
+		lowerHandFunc: func(roomID, userID string) error {
+			lowered = append(lowered, userID)
+			return nil
+		},
+	}
+	service := NewUserService(&mockRoomRepo{}, stateMgr)
+	ctx := context.Background()
+
+	if err := service.LowerHand(ctx, "room123", "bob", "bob"); err != nil {
+		t.Errorf("expected users to lower their own hand, got %v", err)
+	}
+	if err := service.LowerHand(ctx, "room123", "alice", "bob"); err != nil {
+		t.Errorf("expected the facilitator to call on bob, got %v", err)
+	}
+	if err := service.LowerHand(ctx, "room123", "carol", "bob"); !errors.Is(err, room.ErrLowerHandNotAllowed) {
+		t.Errorf("expected ErrLowerHandNotAllowed, got %v", err)
+	}
+	if len(lowered) != 2 {
+		t.Errorf("expected 2 hands lowered, got %v", lowered)
+	}
+}
+
+func TestUserService_RaiseHand(t *testing.T) {
+	var raised room.HandSignal
+	stateMgr := &mockStateManager{
+		raiseHandFunc: func(roomID, userID string, signal room.HandSignal) error {
+			raised = signal
+			return nil
+		},
+	}
+	service := NewUserService(&mockRoomRepo{}, stateMgr)
+
+	if err := service.RaiseHand(context.Background(), "room123", "user1", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if raised != room.SignalQuestion {
+		t.Errorf("expected default signal %q, got %q", room.SignalQuestion, raised)
+	}
+
+	if err := service.RaiseHand(context.Background(), "room123", "user1", "coffee"); !errors.Is(err, room.ErrUnknownHandSignal) {
+		t.Errorf("expected ErrUnknownHandSignal, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vitaly-stepin/agile_party/internal/application/dto"
@@ -12,50 +13,170 @@ import (
 type VotingService struct {
 	roomRepo      ports.RoomRepo
 	stateMgr      ports.RoomStateManager
+	taskRepo      ports.TaskRepo
 	estimationSvc *room.EstimationService
 }
 
-func NewVotingService(roomRepo ports.RoomRepo, stateMgr ports.RoomStateManager) *VotingService {
+func NewVotingService(roomRepo ports.RoomRepo, stateMgr ports.RoomStateManager, taskRepo ports.TaskRepo) *VotingService {
 	return &VotingService{
 		roomRepo:      roomRepo,
 		stateMgr:      stateMgr,
+		taskRepo:      taskRepo,
 		estimationSvc: room.NewEstimationService(),
 	}
 }
 
-func (s *VotingService) SubmitVote(ctx context.Context, roomID, userID, voteValue string) error {
+// SubmitVote records a single-card vote with its comment; an empty comment removes the previous one
+func (s *VotingService) SubmitVote(ctx context.Context, roomID, userID, voteValue, comment string) error {
+	ctx, span := startSpan(ctx, "VotingService.SubmitVote", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
+	r, comment, err := s.openVote(ctx, roomID, userID, comment)
+	if err != nil {
+		return err
+	}
+
+	if r.IsMultiDimensional() {
+		return room.ErrDimensionVotesMismatch
+	}
+	if r.IsThreePoint() {
+		return room.ErrThreePointVoteRequired
+	}
+
+	_, err = room.CreateVote(voteValue, r.VotingSystem)
+	if err != nil {
+		return fmt.Errorf("invalid vote: %w", err)
+	}
+
+	if err := s.ensureVotingOpen(ctx, r); err != nil {
+		return err
+	}
+
+	if err := s.stateMgr.SubmitVote(roomID, userID, voteValue, comment); err != nil {
+		return fmt.Errorf("failed to submit vote: %w", err)
+	}
+
+	return nil
+}
+
+// SubmitDimensionVote records one card per dimension in a multi-dimensional room, with its comment.
+// The user's overall vote is their values combined with the room's formula.
+func (s *VotingService) SubmitDimensionVote(ctx context.Context, roomID, userID string, values map[string]string, comment string) error {
+	ctx, span := startSpan(ctx, "VotingService.SubmitDimensionVote", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
+	r, comment, err := s.openVote(ctx, roomID, userID, comment)
+	if err != nil {
+		return err
+	}
+	if !r.IsMultiDimensional() {
+		return fmt.Errorf("invalid vote: %w", room.ErrInvalidVote)
+	}
+
+	if err := room.ValidateDimensionVotes(values, r.Dimensions); err != nil {
+		return fmt.Errorf("invalid vote: %w", err)
+	}
+
+	if err := s.ensureVotingOpen(ctx, r); err != nil {
+		return err
+	}
+
+	combined := s.estimationSvc.CombineDimensions(values, r.Dimensions, r.Combination)
+	if err := s.stateMgr.SubmitDimensionVote(roomID, userID, combined, values, comment); err != nil {
+		return fmt.Errorf("failed to submit vote: %w", err)
+	}
+
+	return nil
+}
+
+// SubmitThreePointVote records an optimistic / most likely / pessimistic vote in a three-point room,
+// with its comment. The user's overall vote is the card closest to the triple's PERT expected value.
+func (s *VotingService) SubmitThreePointVote(ctx context.Context, roomID, userID string, req *dto.ThreePointReq, comment string) error {
+	ctx, span := startSpan(ctx, "VotingService.SubmitThreePointVote", roomIDKey.String(roomID), userIDKey.String(userID))
+	defer span.End()
+
+	if req == nil {
+		return fmt.Errorf("request cannot be nil")
+	}
+
+	r, comment, err := s.openVote(ctx, roomID, userID, comment)
+	if err != nil {
+		return err
+	}
+	if !r.IsThreePoint() {
+		return room.ErrThreePointVoteNotExpected
+	}
+
+	estimate, err := room.NewThreePointEstimate(req.Optimistic, req.MostLikely, req.Pessimistic, r.VotingSystem)
+	if err != nil {
+		return fmt.Errorf("invalid vote: %w", err)
+	}
+
+	if err := s.ensureVotingOpen(ctx, r); err != nil {
+		return err
+	}
+
+	if err := s.stateMgr.SubmitThreePointVote(roomID, userID, estimate.Card(), estimate, comment); err != nil {
+		return fmt.Errorf("failed to submit vote: %w", err)
+	}
+
+	return nil
+}
+
+// openVote checks the voter and the comment and loads the room voted in, returning the sanitized comment
+func (s *VotingService) openVote(ctx context.Context, roomID, userID, comment string) (*room.Room, string, error) {
 	if roomID == "" {
-		return room.ErrInvalidRoomID
+		return nil, "", room.ErrInvalidRoomID
 	}
 	if userID == "" {
-		return room.ErrInvalidUserID
+		return nil, "", room.ErrInvalidUserID
+	}
+
+	comment, err := room.SanitizeVoteComment(comment)
+	if err != nil {
+		return nil, "", err
 	}
 
 	r, err := s.roomRepo.GetByID(ctx, roomID)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	// Ensure room exists in memory (lazy initialization after restart)
 	if !s.stateMgr.RoomExists(roomID) {
 		if err := s.stateMgr.NewRoom(roomID); err != nil {
-			return fmt.Errorf("failed to initialize room state: %w", err)
+			return nil, "", fmt.Errorf("failed to initialize room state: %w", err)
 		}
 	}
 
-	_, err = room.CreateVote(voteValue, r.VotingSystem)
-	if err != nil {
-		return fmt.Errorf("invalid vote: %w", err)
-	}
+	return r, comment, nil
+}
 
-	if err := s.stateMgr.SubmitVote(roomID, userID, voteValue); err != nil {
-		return fmt.Errorf("failed to submit vote: %w", err)
+// CastVote submits a single, per-dimension or three-point vote together with its comment.
+// The vote and the comment are stored in one step, so a rejected vote leaves the user's previous
+// vote and comment untouched; a vote without comment clears the previous one.
+func (s *VotingService) CastVote(ctx context.Context, roomID string, req *dto.SubmitVoteReq) error {
+	ctx, span := startSpan(ctx, "VotingService.CastVote", roomIDKey.String(roomID))
+	defer span.End()
+
+	if req == nil {
+		return fmt.Errorf("request cannot be nil")
 	}
 
-	return nil
+	switch {
+	case req.ThreePoint != nil:
+		return s.SubmitThreePointVote(ctx, roomID, req.UserID, req.ThreePoint, req.Comment)
+	case len(req.Values) > 0:
+		return s.SubmitDimensionVote(ctx, roomID, req.UserID, req.Values, req.Comment)
+	default:
+		return s.SubmitVote(ctx, roomID, req.UserID, req.Value, req.Comment)
+	}
 }
 
 func (s *VotingService) RevealVotes(ctx context.Context, roomID string) (*dto.RevealVotesResp, error) {
+	ctx, span := startSpan(ctx, "VotingService.RevealVotes", roomIDKey.String(roomID))
+	defer span.End()
+
 	if roomID == "" {
 		return nil, room.ErrInvalidRoomID
 	}
@@ -82,7 +203,9 @@ func (s *VotingService) RevealVotes(ctx context.Context, roomID string) (*dto.Re
 	}
 
 	response := &dto.RevealVotesResp{
-		Votes: state.Votes, // Already map[string]string
+		Votes:    state.Votes, // Already map[string]string
+		Comments: votedComments(state),
+		Changes:  dto.FromDomainVoteChanges(state.VoteChanges),
 	}
 
 	if len(state.Votes) > 0 {
@@ -94,6 +217,18 @@ func (s *VotingService) RevealVotes(ctx context.Context, roomID string) (*dto.Re
 			response.Average = &avg
 		}
 		// If error or all votes non-numeric, Average stays nil
+
+		response.Suggested = s.estimationSvc.SuggestEstimate(state.Votes, r.VotingSystem)
+	}
+
+	if r.IsMultiDimensional() {
+		s.revealDimensions(r, state, response)
+	}
+	if r.IsThreePoint() {
+		s.revealThreePoint(state, response)
+	}
+	if r.AnonymousVotes {
+		response.Anonymize()
 	}
 
 	return response, nil
@@ -101,6 +236,9 @@ func (s *VotingService) RevealVotes(ctx context.Context, roomID string) (*dto.Re
 
 // ClearVotes clears all votes in a room for a new round
 func (s *VotingService) ClearVotes(ctx context.Context, roomID string) error {
+	ctx, span := startSpan(ctx, "VotingService.ClearVotes", roomIDKey.String(roomID))
+	defer span.End()
+
 	if roomID == "" {
 		return room.ErrInvalidRoomID
 	}
@@ -129,6 +267,105 @@ func (s *VotingService) ClearVotes(ctx context.Context, roomID string) error {
 	return nil
 }
 
+// revealDimensions adds a result per dimension and replaces the suggestion with
+// the combination of the per-dimension suggestions
+func (s *VotingService) revealDimensions(r *room.Room, state *ports.LiveRoomState, response *dto.RevealVotesResp) {
+	results := s.estimationSvc.EstimateDimensions(state.DimensionVotes, r.Dimensions)
+
+	suggestions := make(map[string]string, len(results))
+	response.Dimensions = make([]dto.DimensionResultResp, len(results))
+	for i, result := range results {
+		response.Dimensions[i] = dto.DimensionResultResp{
+			Key:       result.Key,
+			Name:      r.Dimensions[i].Name,
+			Votes:     result.Votes,
+			Average:   result.Average,
+			Suggested: result.Suggested,
+		}
+		suggestions[result.Key] = result.Suggested
+	}
+
+	response.Suggested = ""
+	if combined := s.estimationSvc.CombineDimensions(suggestions, r.Dimensions, r.Combination); combined != "?" {
+		response.Suggested = combined
+	}
+}
+
+// revealThreePoint adds the participants' triples and the PERT result, suggesting the card closest to the expected value
+func (s *VotingService) revealThreePoint(state *ports.LiveRoomState, response *dto.RevealVotesResp) {
+	response.ThreePointVotes = make(map[string]dto.ThreePointResp, len(state.ThreePointVotes))
+	for userID, estimate := range state.ThreePointVotes {
+		response.ThreePointVotes[userID] = *dto.FromDomainThreePoint(estimate)
+	}
+
+	pert, err := s.estimationSvc.CalculatePert(state.ThreePointVotes)
+	if err != nil {
+		return
+	}
+	response.Pert = dto.FromDomainPert(pert)
+	response.Suggested = pert.Card()
+}
+
+// ensureVotingOpen refuses votes on a revealed round when the room locks them,
+// and votes on a task without acceptance criteria when the room requires them
+func (s *VotingService) ensureVotingOpen(ctx context.Context, r *room.Room) error {
+	if r.RequireAcceptanceCriteria {
+		if err := s.ensureActiveTaskHasCriteria(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+
+	if !r.VotesLockedAfterReveal() {
+		return nil
+	}
+
+	state, err := s.stateMgr.GetRoomState(r.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get room state: %w", err)
+	}
+	if state.IsRevealed {
+		return room.ErrVotesLocked
+	}
+	return nil
+}
+
+// ensureActiveTaskHasCriteria lets a room without an active task vote freely
+func (s *VotingService) ensureActiveTaskHasCriteria(ctx context.Context, roomID string) error {
+	taskID, err := s.stateMgr.GetActiveTask(roomID)
+	if err != nil {
+		return fmt.Errorf("failed to get active task: %w", err)
+	}
+	if taskID == "" {
+		return nil
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, roomID, taskID)
+	if err != nil {
+		if errors.Is(err, room.ErrTaskNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get active task: %w", err)
+	}
+	if !task.HasAcceptanceCriteria() {
+		return room.ErrMissingAcceptanceCriteria
+	}
+	return nil
+}
+
+// votedComments keeps the comments of users who actually have a vote in the round
+func votedComments(state *ports.LiveRoomState) map[string]string {
+	comments := make(map[string]string, len(state.Comments))
+	for userID, comment := range state.Comments {
+		if _, voted := state.Votes[userID]; voted {
+			comments[userID] = comment
+		}
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	return comments
+}
+
 // hasOnlyNonNumericVotes checks if all votes are non-numeric (e.g., "?")
 func (s *VotingService) hasOnlyNonNumericVotes(votes map[string]string, votingSystem room.VotingSystem) bool {
 	for _, voteValue := range votes {
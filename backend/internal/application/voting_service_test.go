@@ -3,8 +3,10 @@ package application
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
 	"github.com/vitaly-stepin/agile_party/internal/domain/ports"
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
@@ -21,9 +23,9 @@ func TestVotingService_SubmitVote_Success(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
-	err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5")
+	err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", "")
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -33,9 +35,9 @@ func TestVotingService_SubmitVote_Success(t *testing.T) {
 func TestVotingService_SubmitVote_EmptyRoomID(t *testing.T) {
 	repo := &mockRoomRepo{}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
-	err := service.SubmitVote(context.Background(), "", "user1", "5")
+	err := service.SubmitVote(context.Background(), "", "user1", "5", "")
 
 	if err != room.ErrInvalidRoomID {
 		t.Errorf("expected ErrInvalidRoomID, got %v", err)
@@ -45,9 +47,9 @@ func TestVotingService_SubmitVote_EmptyRoomID(t *testing.T) {
 func TestVotingService_SubmitVote_EmptyUserID(t *testing.T) {
 	repo := &mockRoomRepo{}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
-	err := service.SubmitVote(context.Background(), "room123", "", "5")
+	err := service.SubmitVote(context.Background(), "room123", "", "5", "")
 
 	if err != room.ErrInvalidUserID {
 		t.Errorf("expected ErrInvalidUserID, got %v", err)
@@ -61,9 +63,9 @@ func TestVotingService_SubmitVote_RoomNotFound(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
-	err := service.SubmitVote(context.Background(), "room123", "user1", "5")
+	err := service.SubmitVote(context.Background(), "room123", "user1", "5", "")
 
 	if err != room.ErrRoomNotFound {
 		t.Errorf("expected ErrRoomNotFound, got %v", err)
@@ -82,9 +84,9 @@ func TestVotingService_SubmitVote_InvalidVote(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
-	err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "99")
+	err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "99", "")
 
 	if err == nil {
 		t.Fatal("expected error for invalid vote, got nil")
@@ -103,13 +105,13 @@ func TestVotingService_SubmitVote_StateMgrError(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{
-		submitVoteFunc: func(roomID, userID, voteValue string) error {
+		submitVoteFunc: func(roomID, userID, voteValue, comment string) error {
 			return errors.New("state manager error")
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
-	err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5")
+	err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", "")
 
 	if err == nil {
 		t.Fatal("expected error from state manager, got nil")
@@ -140,7 +142,7 @@ func TestVotingService_RevealVotes_Success(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
 
@@ -161,12 +163,484 @@ func TestVotingService_RevealVotes_Success(t *testing.T) {
 	if *resp.Average != expectedAvg {
 		t.Errorf("expected average %.1f, got %.1f", expectedAvg, *resp.Average)
 	}
+	if resp.Suggested != "8" {
+		t.Errorf("expected suggested estimate 8, got %q", resp.Suggested)
+	}
+}
+
+func multiDimensionalRoom() *room.Room {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{
+		VotingSystem: room.DbsFibo,
+		Dimensions: []room.EstimationDimension{
+			{Key: "complexity", Name: "Complexity"},
+			{Key: "effort", Name: "Effort"},
+		},
+		Combination: room.CombineSum,
+	})
+	return testRoom
+}
+
+func TestVotingService_SubmitDimensionVote_StoresCombinedVote(t *testing.T) {
+	testRoom := multiDimensionalRoom()
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+
+	var storedVote string
+	var storedValues map[string]string
+	stateMgr := &mockStateManager{
+		submitDimVoteFunc: func(roomID, userID, voteValue string, dimensionValues map[string]string) error {
+			storedVote = voteValue
+			storedValues = dimensionValues
+			return nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+
+	values := map[string]string{"complexity": "3", "effort": "2"}
+	if err := service.SubmitDimensionVote(context.Background(), testRoom.ID, "user1", values, ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if storedVote != "5" {
+		t.Errorf("expected combined vote 5, got %q", storedVote)
+	}
+	if storedValues["effort"] != "2" {
+		t.Errorf("expected effort vote 2, got %q", storedValues["effort"])
+	}
+
+	err := service.SubmitDimensionVote(context.Background(), testRoom.ID, "user1", map[string]string{"complexity": "3"}, "")
+	if !errors.Is(err, room.ErrDimensionVotesMismatch) {
+		t.Errorf("expected ErrDimensionVotesMismatch, got %v", err)
+	}
+
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", ""); !errors.Is(err, room.ErrDimensionVotesMismatch) {
+		t.Errorf("expected single-value vote to be rejected, got %v", err)
+	}
+}
+
+func TestVotingService_RevealVotes_Dimensions(t *testing.T) {
+	testRoom := multiDimensionalRoom()
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{
+				RoomID: roomID,
+				Users:  make(map[string]*room.User),
+				Votes:  map[string]string{"user1": "5", "user2": "8"},
+				DimensionVotes: map[string]map[string]string{
+					"user1": {"complexity": "3", "effort": "3"},
+					"user2": {"complexity": "3", "effort": "5"},
+				},
+				IsRevealed: true,
+			}, nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+
+	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.Dimensions) != 2 {
+		t.Fatalf("expected 2 dimension results, got %d", len(resp.Dimensions))
+	}
+	if resp.Dimensions[0].Name != "Complexity" || resp.Dimensions[0].Suggested != "3" {
+		t.Errorf("expected complexity suggestion 3, got %q/%q", resp.Dimensions[0].Name, resp.Dimensions[0].Suggested)
+	}
+	// effort [3,5] -> avg 4 -> rounds to 5 (equidistant, rounds up)
+	if resp.Dimensions[1].Suggested != "5" {
+		t.Errorf("expected effort suggestion 5, got %q", resp.Dimensions[1].Suggested)
+	}
+	// 3 + 5 = 8
+	if resp.Suggested != "8" {
+		t.Errorf("expected combined suggestion 8, got %q", resp.Suggested)
+	}
+	if breakdown := resp.Breakdown(); breakdown["complexity"] != "3" || breakdown["effort"] != "5" {
+		t.Errorf("expected breakdown complexity=3 effort=5, got %v", breakdown)
+	}
+}
+
+func threePointRoomRepo() *mockRoomRepo {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{
+		VotingSystem: room.DbsFibo,
+		Mode:         room.ModeThreePoint,
+	})
+	return &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+}
+
+func TestVotingService_SubmitThreePointVote(t *testing.T) {
+	var storedVote string
+	var storedEstimate room.ThreePointEstimate
+	stateMgr := &mockStateManager{
+		submitPertVoteFunc: func(roomID, userID, voteValue string, estimate room.ThreePointEstimate) error {
+			storedVote = voteValue
+			storedEstimate = estimate
+			return nil
+		},
+	}
+	service := NewVotingService(threePointRoomRepo(), stateMgr, newMockTaskRepo())
+
+	err := service.SubmitThreePointVote(context.Background(), "room1", "user1", &dto.ThreePointReq{
+		Optimistic: "2", MostLikely: "3", Pessimistic: "8",
+	}, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// (2 + 12 + 8) / 6 = 3.67 -> 3
+	if storedVote != "3" {
+		t.Errorf("expected vote 3, got %q", storedVote)
+	}
+	if storedEstimate.Pessimistic != 8 {
+		t.Errorf("expected pessimistic 8, got %v", storedEstimate.Pessimistic)
+	}
+
+	err = service.SubmitThreePointVote(context.Background(), "room1", "user1", &dto.ThreePointReq{
+		Optimistic: "8", MostLikely: "3", Pessimistic: "2",
+	}, "")
+	if !errors.Is(err, room.ErrInvalidThreePointVote) {
+		t.Errorf("expected ErrInvalidThreePointVote, got %v", err)
+	}
+
+	if err := service.SubmitVote(context.Background(), "room1", "user1", "5", ""); !errors.Is(err, room.ErrThreePointVoteRequired) {
+		t.Errorf("expected ErrThreePointVoteRequired, got %v", err)
+	}
+}
+
+func TestVotingService_RevealVotes_Pert(t *testing.T) {
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{
+				RoomID: roomID,
+				Users:  make(map[string]*room.User),
+				Votes:  map[string]string{"user1": "2", "user2": "5"},
+				ThreePointVotes: map[string]room.ThreePointEstimate{
+					"user1": {Optimistic: 1, MostLikely: 2, Pessimistic: 5},
+					"user2": {Optimistic: 3, MostLikely: 5, Pessimistic: 13},
+				},
+				IsRevealed: true,
+			}, nil
+		},
+	}
+	service := NewVotingService(threePointRoomRepo(), stateMgr, newMockTaskRepo())
+
+	resp, err := service.RevealVotes(context.Background(), "room1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Pert == nil {
+		t.Fatal("expected PERT result, got nil")
+	}
+	if resp.Pert.Expected != 4.17 || resp.Pert.StdDev != 1.17 {
+		t.Errorf("expected 4.17 +/- 1.17, got %v +/- %v", resp.Pert.Expected, resp.Pert.StdDev)
+	}
+	if resp.Suggested != "5" {
+		t.Errorf("expected suggested card 5, got %q", resp.Suggested)
+	}
+	if resp.ThreePointVotes["user2"].Pessimistic != 13 {
+		t.Errorf("expected user2 pessimistic 13, got %v", resp.ThreePointVotes["user2"].Pessimistic)
+	}
+}
+
+func TestVotingService_RevealVotes_Anonymous(t *testing.T) {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{
+		VotingSystem:   room.DbsFibo,
+		AnonymousVotes: true,
+	})
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{
+				RoomID:     roomID,
+				Users:      make(map[string]*room.User),
+				Votes:      map[string]string{"user2": "?", "user1": "8", "user3": "5"},
+				IsRevealed: true,
+			}, nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+
+	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resp.Votes != nil {
+		t.Errorf("expected no per-user votes, got %v", resp.Votes)
+	}
+	expected := []string{"5", "8", "?"}
+	if len(resp.Distribution) != len(expected) {
+		t.Fatalf("expected distribution %v, got %v", expected, resp.Distribution)
+	}
+	for i, value := range expected {
+		if resp.Distribution[i] != value {
+			t.Errorf("expected distribution %v, got %v", expected, resp.Distribution)
+			break
+		}
+	}
+	if voters := resp.VoterIDs(); len(voters) != 3 || voters[0] != "user1" {
+		t.Errorf("expected sorted voters [user1 user2 user3], got %v", voters)
+	}
+	if resp.Suggested == "" || resp.Average == nil {
+		t.Error("expected aggregated results to be kept")
+	}
+}
+
+func TestVotingService_SubmitVote_Comment(t *testing.T) {
+	var stored string
+	stateMgr := &mockStateManager{
+		roomExistsFunc: func(roomID string) bool { return true },
+		submitVoteFunc: func(roomID, userID, voteValue, comment string) error {
+			stored = comment
+			return nil
+		},
+	}
+	service := NewVotingService(existingRoomRepo(), stateMgr, newMockTaskRepo())
+
+	if err := service.SubmitVote(context.Background(), "room1", "user1", "5", "  touches\nbilling  "); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stored != "touches billing" {
+		t.Errorf("expected sanitized comment %q, got %q", "touches billing", stored)
+	}
+
+	tooLong := strings.Repeat("a", room.MaxVoteCommentLength+1)
+	if err := service.SubmitVote(context.Background(), "room1", "user1", "5", tooLong); !errors.Is(err, room.ErrVoteCommentTooLong) {
+		t.Errorf("expected ErrVoteCommentTooLong, got %v", err)
+	}
+}
+
+func TestVotingService_CastVote_StoresVoteAndCommentTogether(t *testing.T) {
+	repo := existingRoomRepo()
+	loads := 0
+	getRoom := repo.getFunc
+	repo.getFunc = func(ctx context.Context, id string) (*room.Room, error) {
+		loads++
+		return getRoom(ctx, id)
+	}
+	var stored []string
+	stateMgr := &mockStateManager{
+		roomExistsFunc: func(roomID string) bool { return true },
+		submitVoteFunc: func(roomID, userID, voteValue, comment string) error {
+			stored = append(stored, voteValue+" "+comment)
+			return nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+	ctx := context.Background()
+
+	err := service.CastVote(ctx, "room1", &dto.SubmitVoteReq{UserID: "user1", Value: "42", Comment: "new reason"})
+	if err == nil {
+		t.Fatal("expected the invalid vote to be rejected")
+	}
+
+	tooLong := strings.Repeat("a", room.MaxVoteCommentLength+1)
+	if err := service.CastVote(ctx, "room1", &dto.SubmitVoteReq{UserID: "user1", Value: "5", Comment: tooLong}); !errors.Is(err, room.ErrVoteCommentTooLong) {
+		t.Errorf("expected ErrVoteCommentTooLong, got %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("expected rejected votes to leave the previous vote and comment alone, got %v", stored)
+	}
+
+	loads = 0
+	if err := service.CastVote(ctx, "room1", &dto.SubmitVoteReq{UserID: "user1", Value: "5", Comment: "touches billing"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(stored) != 1 || stored[0] != "5 touches billing" {
+		t.Errorf("expected the vote to be stored with its comment, got %v", stored)
+	}
+	if loads != 1 {
+		t.Errorf("expected the room to be loaded once, got %d", loads)
+	}
+}
+
+func TestVotingService_RevealVotes_Comments(t *testing.T) {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{VotingSystem: room.DbsFibo})
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+	state := &ports.LiveRoomState{
+		Users:      make(map[string]*room.User),
+		Votes:      map[string]string{"user1": "13", "user2": "3", "user3": "3"},
+		Comments:   map[string]string{"user1": "legacy code", "user4": "left before voting"},
+		IsRevealed: true,
+	}
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return state, nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+
+	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.Comments) != 1 || resp.Comments["user1"] != "legacy code" {
+		t.Errorf("expected only the voter's comment, got %v", resp.Comments)
+	}
+
+	testRoom.AnonymousVotes = true
+	resp, err = service.RevealVotes(context.Background(), testRoom.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Comments != nil {
+		t.Errorf("expected no attributed comments, got %v", resp.Comments)
+	}
+	if len(resp.DistributionComments) != 3 || resp.Distribution[2] != "13" || resp.DistributionComments[2] != "legacy code" {
+		t.Errorf("expected the comment next to its value, got %v / %v", resp.Distribution, resp.DistributionComments)
+	}
+	comments := resp.VoteComments(map[string]string{"user1": "Alice"})
+	if len(comments) != 1 || comments[0].Author != "" || comments[0].Value != "13" {
+		t.Errorf("expected one unattributed comment on 13, got %+v", comments)
+	}
+}
+
+func TestVotingService_SubmitVote_LockedAfterReveal(t *testing.T) {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{
+		VotingSystem:     room.DbsFibo,
+		VoteChangePolicy: room.VoteChangesLocked,
+	})
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+	revealed := false
+	submitted := 0
+	stateMgr := &mockStateManager{
+		roomExistsFunc: func(roomID string) bool { return true },
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{RoomID: roomID, IsRevealed: revealed}, nil
+		},
+		submitVoteFunc: func(roomID, userID, voteValue, comment string) error {
+			submitted++
+			return nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", ""); err != nil {
+		t.Fatalf("expected vote before reveal to be accepted, got %v", err)
+	}
+
+	revealed = true
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "8", ""); !errors.Is(err, room.ErrVotesLocked) {
+		t.Errorf("expected ErrVotesLocked, got %v", err)
+	}
+	if err := service.CastVote(context.Background(), testRoom.ID, &dto.SubmitVoteReq{UserID: "user1", Value: "5", Comment: "changed my mind"}); !errors.Is(err, room.ErrVotesLocked) {
+		t.Errorf("expected ErrVotesLocked for the comment, got %v", err)
+	}
+	if submitted != 1 {
+		t.Errorf("expected 1 stored vote, got %d", submitted)
+	}
+}
+
+func TestVotingService_SubmitVote_RequiresAcceptanceCriteria(t *testing.T) {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{
+		VotingSystem:              room.DbsFibo,
+		RequireAcceptanceCriteria: true,
+	})
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+	task, _ := room.NewTask(testRoom.ID, "Checkout", 1)
+	activeTaskID := task.ID
+	submitted := 0
+	stateMgr := &mockStateManager{
+		roomExistsFunc:    func(roomID string) bool { return true },
+		getActiveTaskFunc: func(roomID string) (string, error) { return activeTaskID, nil },
+		submitVoteFunc: func(roomID, userID, voteValue, comment string) error {
+			submitted++
+			return nil
+		},
+	}
+	taskRepo := newMockTaskRepo(task)
+	service := NewVotingService(repo, stateMgr, taskRepo)
+
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", ""); !errors.Is(err, room.ErrMissingAcceptanceCriteria) {
+		t.Errorf("expected ErrMissingAcceptanceCriteria, got %v", err)
+	}
+
+	if _, err := taskRepo.tasks[task.ID].AddCriterion("Pays with a saved card"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "5", ""); err != nil {
+		t.Errorf("expected vote on a task with criteria to be accepted, got %v", err)
+	}
+
+	activeTaskID = ""
+	if err := service.SubmitVote(context.Background(), testRoom.ID, "user1", "8", ""); err != nil {
+		t.Errorf("expected vote without an active task to be accepted, got %v", err)
+	}
+	if submitted != 2 {
+		t.Errorf("expected 2 stored votes, got %d", submitted)
+	}
+}
+
+func TestVotingService_RevealVotes_Changes(t *testing.T) {
+	testRoom, _ := room.NewRoom("Test Room", room.RoomSettings{VotingSystem: room.DbsFibo})
+	repo := &mockRoomRepo{
+		getFunc: func(ctx context.Context, id string) (*room.Room, error) {
+			return testRoom, nil
+		},
+	}
+	stateMgr := &mockStateManager{
+		getRoomStateFunc: func(roomID string) (*ports.LiveRoomState, error) {
+			return &ports.LiveRoomState{
+				Users:       make(map[string]*room.User),
+				Votes:       map[string]string{"user1": "8", "user2": "5"},
+				VoteChanges: []room.VoteChange{{Voter: "user1", From: "3", To: "8"}},
+				IsRevealed:  true,
+			}, nil
+		},
+	}
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
+
+	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.Changes) != 1 || resp.Changes[0] != (dto.VoteChangeResp{UserID: "user1", From: "3", To: "8"}) {
+		t.Errorf("expected user1 changed from 3 to 8, got %+v", resp.Changes)
+	}
+	named := resp.NamedChanges(map[string]string{"user1": "Alice"})
+	if len(named) != 1 || named[0].Voter != "Alice" || named[0].UserID != "" {
+		t.Errorf("expected change named after Alice, got %+v", named)
+	}
+
+	testRoom.AnonymousVotes = true
+	resp, err = service.RevealVotes(context.Background(), testRoom.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.Changes) != 1 || resp.Changes[0].UserID != "" || resp.Changes[0].From != "3" {
+		t.Errorf("expected an unattributed change from 3, got %+v", resp.Changes)
+	}
 }
 
 func TestVotingService_RevealVotes_EmptyRoomID(t *testing.T) {
 	repo := &mockRoomRepo{}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	_, err := service.RevealVotes(context.Background(), "")
 
@@ -182,7 +656,7 @@ func TestVotingService_RevealVotes_RoomNotFound(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	_, err := service.RevealVotes(context.Background(), "room123")
 
@@ -212,7 +686,7 @@ func TestVotingService_RevealVotes_NoVotes(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
 
@@ -248,7 +722,7 @@ func TestVotingService_RevealVotes_OnlyQuestionMarks(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
 
@@ -276,7 +750,7 @@ func TestVotingService_RevealVotes_StateMgrError(t *testing.T) {
 			return errors.New("state manager error")
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	_, err := service.RevealVotes(context.Background(), testRoom.ID)
 
@@ -310,7 +784,7 @@ func TestVotingService_RevealVotes_AllZeroVotes(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	resp, err := service.RevealVotes(context.Background(), testRoom.ID)
 
@@ -333,7 +807,7 @@ func TestVotingService_ClearVotes_Success(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	err := service.ClearVotes(context.Background(), "room123")
 
@@ -345,7 +819,7 @@ func TestVotingService_ClearVotes_Success(t *testing.T) {
 func TestVotingService_ClearVotes_EmptyRoomID(t *testing.T) {
 	repo := &mockRoomRepo{}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	err := service.ClearVotes(context.Background(), "")
 
@@ -361,7 +835,7 @@ func TestVotingService_ClearVotes_RoomNotFound(t *testing.T) {
 		},
 	}
 	stateMgr := &mockStateManager{}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	err := service.ClearVotes(context.Background(), "room123")
 
@@ -381,7 +855,7 @@ func TestVotingService_ClearVotes_StateMgrError(t *testing.T) {
 			return errors.New("state manager error")
 		},
 	}
-	service := NewVotingService(repo, stateMgr)
+	service := NewVotingService(repo, stateMgr, newMockTaskRepo())
 
 	err := service.ClearVotes(context.Background(), "room123")
 
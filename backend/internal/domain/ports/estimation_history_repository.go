@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+type EstimationHistoryRepo interface {
+	Add(ctx context.Context, record *room.EstimationRecord) error
+	// ListByTask returns the task's estimations, oldest first
+	ListByTask(ctx context.Context, taskID string) ([]*room.EstimationRecord, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -6,11 +6,38 @@ import (
 
 type LiveRoomState struct {
 	RoomID          string
-	Users           map[string]*room.User // userID -> User
-	Votes           map[string]string     // userID -> vote value
+	Users           map[string]*room.User              // userID -> User
+	Votes           map[string]string                  // userID -> vote value
+	DimensionVotes  map[string]map[string]string       // userID -> dimension key -> vote value, multi-dimensional rooms only
+	ThreePointVotes map[string]room.ThreePointEstimate // userID -> triple, three-point rooms only
+	Comments        map[string]string                  // userID -> rationale left with the vote
+	VoteChanges     []room.VoteChange                  // votes changed after the reveal, oldest first
+	RaisedHands     []room.RaisedHand                  // hand queue, first raised first
 	IsRevealed      bool
 	TaskDescription string
-	ActiveTaskID    string // ID of the task currently being estimated
+	ActiveTaskID    string     // ID of the task currently being estimated
+	FacilitatorID   string     // user in the room the longest, may undo anyone's change
+	LastChange      *UndoEntry // latest change that can still be undone, nil when there is none
+}
+
+type UndoAction string
+
+const (
+	UndoDeleteTask     UndoAction = "delete_task"
+	UndoReorderTasks   UndoAction = "reorder_tasks"
+	UndoMoveTask       UndoAction = "move_task"
+	UndoAcceptEstimate UndoAction = "accept_estimate"
+	UndoClear          UndoAction = "clear"
+)
+
+// UndoEntry keeps what is needed to revert one destructive change
+type UndoEntry struct {
+	Action   UndoAction
+	UserID   string         // who made the change
+	Task     *room.Task     // deleted or moved task, or estimated task as it was before the estimate
+	Subtasks []string       // subtasks the deleted task held, in order
+	Order    []string       // backlog order before a reorder or move
+	Round    *LiveRoomState // voting round before it was cleared or estimated
 }
 
 type RoomStateManager interface {
@@ -19,16 +46,33 @@ type RoomStateManager interface {
 	RoomExists(roomID string) bool
 	DeleteRoom(roomID string) error
 
-	AddUser(roomID string, user *room.User) error
+	// AddUser counts the room's users and adds the user in one step, failing with room.ErrRoomFull
+	// once the room holds limits.MaxParticipants
+	AddUser(roomID string, user *room.User, limits room.RoomLimits) error
 	RemoveUser(roomID, userID string) error
 	GetUser(roomID, userID string) (*room.User, error)
 	UpdateUser(roomID string, user *room.User) error
 	GetUserCount(roomID string) (int, error)
+	// RaiseHand queues the user's hand; raising it again only changes the signal, not the position
+	RaiseHand(roomID, userID string, signal room.HandSignal) error
+	LowerHand(roomID, userID string) error
 
-	SubmitVote(roomID, userID, voteValue string) error
+	// SubmitVote stores a user's vote together with its comment; an empty comment removes the previous one
+	SubmitVote(roomID, userID, voteValue, comment string) error
+	// SubmitDimensionVote stores a user's per-dimension values along with the combined vote and its comment
+	SubmitDimensionVote(roomID, userID, voteValue string, dimensionValues map[string]string, comment string) error
+	// SubmitThreePointVote stores a user's triple along with the card closest to its expected value and its comment
+	SubmitThreePointVote(roomID, userID, voteValue string, estimate room.ThreePointEstimate, comment string) error
 	RevealVotes(roomID string) error
 	ClearVotes(roomID string) error
 	UpdateTaskDescription(roomID, description string) error
 	SetActiveTask(roomID, taskID string) error
 	GetActiveTask(roomID string) (string, error)
-}
\ No newline at end of file
+
+	// PushUndo records a change that can be undone; only the latest few changes are kept
+	PushUndo(roomID string, entry UndoEntry) error
+	// PopUndo drops the latest change, the one reported as LastChange
+	PopUndo(roomID string) error
+	// RestoreRound brings back the votes, hands and active task of a saved round
+	RestoreRound(roomID string, round *LiveRoomState) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+type TaskCommentRepo interface {
+	Create(ctx context.Context, comment *room.TaskComment) error
+	GetByID(ctx context.Context, id string) (*room.TaskComment, error)
+	Update(ctx context.Context, comment *room.TaskComment) error
+	Delete(ctx context.Context, id string) error
+	// ListByTask returns a page of the task's comments, oldest first, and the total count
+	ListByTask(ctx context.Context, taskID string, limit, offset int) ([]*room.TaskComment, int, error)
+}
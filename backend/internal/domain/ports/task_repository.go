@@ -2,17 +2,40 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
+// TaskRepo stores the rooms' backlogs. Every lookup and write is scoped to a room:
+// a task of another room is reported as room.ErrTaskNotFound and left untouched.
 type TaskRepo interface {
 	Create(ctx context.Context, task *room.Task) error
-	GetByID(ctx context.Context, id string) (*room.Task, error)
+	GetByID(ctx context.Context, roomID, id string) (*room.Task, error)
 	GetByRoomID(ctx context.Context, roomID string) ([]*room.Task, error)
+	// Update saves the task only if it is still at task.Version, then increments the version.
+	// It fails with room.ErrTaskVersionConflict when someone else saved the task in the meantime.
+	// The task's place (rank and parent) is left alone, Move and UpdateRanks change it.
 	Update(ctx context.Context, task *room.Task) error
-	Delete(ctx context.Context, id string) error
+	// Delete hides the task from the backlog; it is kept so the deletion can be undone with Restore
+	Delete(ctx context.Context, roomID, id string) error
+	// Restore brings a deleted task back under its parent at its rank. It fails with
+	// room.ErrTaskRankTaken when a sibling holds that rank by now.
+	Restore(ctx context.Context, task *room.Task) error
+	// PurgeDeleted removes for good the tasks of every room deleted before deletedBefore
+	// and reports how many were removed
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 
-	UpdatePositions(ctx context.Context, tasks []*room.Task) error
-	GetNextUnestimatedTask(ctx context.Context, roomID string) (*room.Task, error)
+	// UpdateRanks rewrites the rank of every given task at once, for whole-backlog reorders
+	UpdateRanks(ctx context.Context, roomID string, tasks []*room.Task) error
+	// Move saves only the task's rank and parent. It fails with room.ErrTaskRankTaken when
+	// a sibling got the same rank in the meantime, the caller reloads and retries.
+	Move(ctx context.Context, task *room.Task) error
+	// GetNextUnestimatedTask returns the first task in backlog order that is still open
+	// (pending or in discussion) and matches filter; skipped, deferred and estimated tasks are passed over
+	GetNextUnestimatedTask(ctx context.Context, roomID string, filter room.TaskFilter) (*room.Task, error)
+
+	// AddDependency marks taskID as blocked by dependsOnID; adding an existing dependency is a no-op
+	AddDependency(ctx context.Context, roomID, taskID, dependsOnID string) error
+	RemoveDependency(ctx context.Context, roomID, taskID, dependsOnID string) error
 }
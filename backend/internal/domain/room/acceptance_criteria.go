@@ -0,0 +1,111 @@
+package room
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxAcceptanceCriteria = 30
+	MaxCriterionLength    = 500
+)
+
+// AcceptanceCriterion is one item of a task's acceptance criteria checklist
+type AcceptanceCriterion struct {
+	ID   string
+	Text string
+	Done bool
+}
+
+func (t *Task) HasAcceptanceCriteria() bool {
+	return len(t.AcceptanceCriteria) > 0
+}
+
+// AddCriterion appends a criterion to the end of the checklist
+func (t *Task) AddCriterion(text string) (*AcceptanceCriterion, error) {
+	text, err := validateCriterionText(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.AcceptanceCriteria) >= MaxAcceptanceCriteria {
+		return nil, ErrTooManyCriteria
+	}
+
+	t.AcceptanceCriteria = append(t.AcceptanceCriteria, AcceptanceCriterion{
+		ID:   uuid.New().String(),
+		Text: text,
+	})
+	return &t.AcceptanceCriteria[len(t.AcceptanceCriteria)-1], nil
+}
+
+// UpdateCriterion changes the text and/or the checked state; nil leaves a field as is
+func (t *Task) UpdateCriterion(id string, text *string, done *bool) error {
+	i := t.criterionIndex(id)
+	if i < 0 {
+		return ErrCriterionNotFound
+	}
+
+	if text != nil {
+		cleaned, err := validateCriterionText(*text)
+		if err != nil {
+			return err
+		}
+		t.AcceptanceCriteria[i].Text = cleaned
+	}
+	if done != nil {
+		t.AcceptanceCriteria[i].Done = *done
+	}
+	return nil
+}
+
+func (t *Task) RemoveCriterion(id string) error {
+	i := t.criterionIndex(id)
+	if i < 0 {
+		return ErrCriterionNotFound
+	}
+	t.AcceptanceCriteria = append(t.AcceptanceCriteria[:i], t.AcceptanceCriteria[i+1:]...)
+	return nil
+}
+
+// ReorderCriteria puts the checklist in the given order, which must name every criterion once
+func (t *Task) ReorderCriteria(ids []string) error {
+	if len(ids) != len(t.AcceptanceCriteria) {
+		return ErrInvalidCriteriaOrder
+	}
+
+	reordered := make([]AcceptanceCriterion, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		i := t.criterionIndex(id)
+		if i < 0 || seen[id] {
+			return ErrInvalidCriteriaOrder
+		}
+		seen[id] = true
+		reordered = append(reordered, t.AcceptanceCriteria[i])
+	}
+
+	t.AcceptanceCriteria = reordered
+	return nil
+}
+
+func (t *Task) criterionIndex(id string) int {
+	for i, criterion := range t.AcceptanceCriteria {
+		if criterion.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateCriterionText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmptyCriterion
+	}
+	if utf8.RuneCountInString(text) > MaxCriterionLength {
+		return "", ErrCriterionTooLong
+	}
+	return text, nil
+}
@@ -0,0 +1,102 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTask_AddCriterion(t *testing.T) {
+	task, _ := NewTask("room1", "Checkout", 1)
+
+	if task.HasAcceptanceCriteria() {
+		t.Error("expected a new task to have no criteria")
+	}
+	if _, err := task.AddCriterion("  "); !errors.Is(err, ErrEmptyCriterion) {
+		t.Errorf("expected ErrEmptyCriterion, got %v", err)
+	}
+	if _, err := task.AddCriterion(strings.Repeat("a", MaxCriterionLength+1)); !errors.Is(err, ErrCriterionTooLong) {
+		t.Errorf("expected ErrCriterionTooLong, got %v", err)
+	}
+
+	criterion, err := task.AddCriterion(" Pays with a saved card ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if criterion.Text != "Pays with a saved card" || criterion.Done {
+		t.Errorf("expected an unchecked trimmed criterion, got %+v", criterion)
+	}
+	if !task.HasAcceptanceCriteria() {
+		t.Error("expected the task to have criteria")
+	}
+
+	for len(task.AcceptanceCriteria) < MaxAcceptanceCriteria {
+		task.AddCriterion("more")
+	}
+	if _, err := task.AddCriterion("one too many"); !errors.Is(err, ErrTooManyCriteria) {
+		t.Errorf("expected ErrTooManyCriteria, got %v", err)
+	}
+}
+
+func TestTask_UpdateAndRemoveCriterion(t *testing.T) {
+	task, _ := NewTask("room1", "Checkout", 1)
+	criterion, _ := task.AddCriterion("Pays with a saved card")
+	id := criterion.ID
+
+	done := true
+	if err := task.UpdateCriterion(id, nil, &done); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := "Pays with any card"
+	if err := task.UpdateCriterion(id, &text, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := task.AcceptanceCriteria[0]; got.Text != text || !got.Done {
+		t.Errorf("expected checked %q, got %+v", text, got)
+	}
+
+	empty := ""
+	if err := task.UpdateCriterion(id, &empty, nil); !errors.Is(err, ErrEmptyCriterion) {
+		t.Errorf("expected ErrEmptyCriterion, got %v", err)
+	}
+	if err := task.UpdateCriterion("missing", nil, &done); !errors.Is(err, ErrCriterionNotFound) {
+		t.Errorf("expected ErrCriterionNotFound, got %v", err)
+	}
+
+	if err := task.RemoveCriterion(id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := task.RemoveCriterion(id); !errors.Is(err, ErrCriterionNotFound) {
+		t.Errorf("expected ErrCriterionNotFound, got %v", err)
+	}
+}
+
+func TestTask_ReorderCriteria(t *testing.T) {
+	task, _ := NewTask("room1", "Checkout", 1)
+	a, _ := task.AddCriterion("a")
+	b, _ := task.AddCriterion("b")
+	idA, idB := a.ID, b.ID
+
+	tests := []struct {
+		name string
+		ids  []string
+	}{
+		{"Missing criterion", []string{idB}},
+		{"Duplicate criterion", []string{idB, idB}},
+		{"Unknown criterion", []string{idB, "missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := task.ReorderCriteria(tt.ids); !errors.Is(err, ErrInvalidCriteriaOrder) {
+				t.Errorf("expected ErrInvalidCriteriaOrder, got %v", err)
+			}
+		})
+	}
+
+	if err := task.ReorderCriteria([]string{idB, idA}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.AcceptanceCriteria[0].Text != "b" || task.AcceptanceCriteria[1].Text != "a" {
+		t.Errorf("expected order b, a, got %+v", task.AcceptanceCriteria)
+	}
+}
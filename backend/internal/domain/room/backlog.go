@@ -0,0 +1,81 @@
+package room
+
+import "sort"
+
+// Backlog is a room's task list in position order, used to navigate between tasks
+type Backlog []*Task
+
+func NewBacklog(tasks []*Task) Backlog {
+	backlog := make(Backlog, len(tasks))
+	copy(backlog, tasks)
+	sort.SliceStable(backlog, func(i, j int) bool {
+		return backlog[i].Position < backlog[j].Position
+	})
+	return backlog
+}
+
+// Find returns the task with the given ID, or ErrTaskNotFound when it is not part of this backlog
+func (b Backlog) Find(taskID string) (*Task, error) {
+	if taskID == "" {
+		return nil, ErrInvalidTaskID
+	}
+	for _, task := range b {
+		if task.ID == taskID {
+			return task, nil
+		}
+	}
+	return nil, ErrTaskNotFound
+}
+
+// Next returns the first open task after currentID, wrapping around to the start
+// of the backlog. An empty currentID starts from the top.
+func (b Backlog) Next(currentID string) (*Task, error) {
+	return b.NextMatching(currentID, TaskFilter{})
+}
+
+// NextMatching is Next restricted to the open tasks matching filter.
+// Epics are skipped, their estimate rolls up from their subtasks.
+func (b Backlog) NextMatching(currentID string, filter TaskFilter) (*Task, error) {
+	start := 0
+	if currentID != "" {
+		idx := b.indexOf(currentID)
+		if idx < 0 {
+			return nil, ErrTaskNotFound
+		}
+		start = idx + 1
+	}
+
+	tree := NewTaskTree(b)
+	for i := 0; i < len(b); i++ {
+		task := b[(start+i)%len(b)]
+		if task.ID != currentID && task.IsOpen() && filter.Matches(task) && !tree.IsEpic(task.ID) {
+			return task, nil
+		}
+	}
+	return nil, ErrNoOpenTasks
+}
+
+// Previous returns the task right before currentID regardless of its status,
+// so already estimated or skipped tasks can be revisited
+func (b Backlog) Previous(currentID string) (*Task, error) {
+	if currentID == "" {
+		return nil, ErrNoPreviousTask
+	}
+	idx := b.indexOf(currentID)
+	if idx < 0 {
+		return nil, ErrTaskNotFound
+	}
+	if idx == 0 {
+		return nil, ErrNoPreviousTask
+	}
+	return b[idx-1], nil
+}
+
+func (b Backlog) indexOf(taskID string) int {
+	for i, task := range b {
+		if task.ID == taskID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,102 @@
+package room
+
+import "testing"
+
+func newTestBacklog(statuses ...TaskStatus) (Backlog, []*Task) {
+	tasks := make([]*Task, len(statuses))
+	for i, status := range statuses {
+		task, _ := NewTask("room123", "Task", i+1)
+		task.Status = status
+		tasks[i] = task
+	}
+	// Shuffle input order to make sure the backlog sorts by position
+	shuffled := append([]*Task{}, tasks[len(tasks)-1])
+	shuffled = append(shuffled, tasks[:len(tasks)-1]...)
+	return NewBacklog(shuffled), tasks
+}
+
+func TestBacklogNext(t *testing.T) {
+	backlog, tasks := newTestBacklog(TaskStatusEstimated, TaskStatusInDiscussion, TaskStatusSkipped, TaskStatusPending)
+
+	next, err := backlog.Next(tasks[1].ID)
+	if err != nil || next.ID != tasks[3].ID {
+		t.Errorf("expected task 4, got %v (%v)", next, err)
+	}
+
+	next, err = backlog.Next("")
+	if err != nil || next.ID != tasks[1].ID {
+		t.Errorf("expected first open task 2, got %v (%v)", next, err)
+	}
+
+	// Wraps around to open tasks before the current one
+	next, err = backlog.Next(tasks[3].ID)
+	if err != nil || next.ID != tasks[1].ID {
+		t.Errorf("expected wrap to task 2, got %v (%v)", next, err)
+	}
+}
+
+func TestBacklogNext_NoOpenTasks(t *testing.T) {
+	backlog, tasks := newTestBacklog(TaskStatusEstimated, TaskStatusInDiscussion)
+
+	if _, err := backlog.Next(tasks[1].ID); err != ErrNoOpenTasks {
+		t.Errorf("expected ErrNoOpenTasks, got %v", err)
+	}
+	if _, err := backlog.Next("missing"); err != ErrTaskNotFound {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestBacklogPrevious(t *testing.T) {
+	backlog, tasks := newTestBacklog(TaskStatusEstimated, TaskStatusPending)
+
+	prev, err := backlog.Previous(tasks[1].ID)
+	if err != nil || prev.ID != tasks[0].ID {
+		t.Errorf("expected estimated task 1, got %v (%v)", prev, err)
+	}
+	if _, err := backlog.Previous(tasks[0].ID); err != ErrNoPreviousTask {
+		t.Errorf("expected ErrNoPreviousTask, got %v", err)
+	}
+	if _, err := backlog.Previous(""); err != ErrNoPreviousTask {
+		t.Errorf("expected ErrNoPreviousTask, got %v", err)
+	}
+}
+
+func TestBacklogFind(t *testing.T) {
+	backlog, tasks := newTestBacklog(TaskStatusPending)
+
+	if task, err := backlog.Find(tasks[0].ID); err != nil || task != tasks[0] {
+		t.Errorf("expected task, got %v (%v)", task, err)
+	}
+	if _, err := backlog.Find("other-room-task"); err != ErrTaskNotFound {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+	if _, err := backlog.Find(""); err != ErrInvalidTaskID {
+		t.Errorf("expected ErrInvalidTaskID, got %v", err)
+	}
+}
+
+func TestBacklogNextMatching(t *testing.T) {
+	backlog, tasks := newTestBacklog(TaskStatusPending, TaskStatusPending, TaskStatusPending)
+	_ = tasks[1].SetType("bug")
+	filter, _ := NewTaskFilter(nil, []string{"story"}, nil)
+
+	next, err := backlog.NextMatching(tasks[0].ID, filter)
+	if err != nil || next.ID != tasks[2].ID {
+		t.Errorf("expected bug to be skipped for task 3, got %v (%v)", next, err)
+	}
+
+	filter, _ = NewTaskFilter([]string{"frontend"}, nil, nil)
+	if _, err := backlog.NextMatching("", filter); err != ErrNoOpenTasks {
+		t.Errorf("expected ErrNoOpenTasks, got %v", err)
+	}
+}
+
+func TestBacklogNext_SkipsEpics(t *testing.T) {
+	backlog, tasks := newTestBacklog(TaskStatusPending, TaskStatusPending)
+	tasks[1].ParentID = tasks[0].ID
+
+	next, err := backlog.Next("")
+	if err != nil || next.ID != tasks[1].ID {
+		t.Errorf("expected the subtask instead of its epic, got %v (%v)", next, err)
+	}
+}
@@ -0,0 +1,155 @@
+package room
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const MaxEstimationDimensions = 5
+
+// EstimationDimension is one axis a room estimates on (e.g. complexity, effort), with its own deck
+type EstimationDimension struct {
+	Key          string
+	Name         string
+	VotingSystem VotingSystem
+	Weight       float64 // only used by the weighted combination; zero counts as 1
+}
+
+// DimensionCombination is the formula that turns per-dimension values into the final estimate
+type DimensionCombination string
+
+const (
+	CombineSum      DimensionCombination = "sum"
+	CombineAverage  DimensionCombination = "average"
+	CombineMax      DimensionCombination = "max"
+	CombineWeighted DimensionCombination = "weighted"
+)
+
+func ParseDimensionCombination(value string) (DimensionCombination, error) {
+	switch combination := DimensionCombination(value); combination {
+	case CombineSum, CombineAverage, CombineMax, CombineWeighted:
+		return combination, nil
+	default:
+		return "", ErrUnknownCombination
+	}
+}
+
+// ValidateDimensions checks a room's dimension setup. No dimensions means a
+// regular single-value room, in which case the combination is ignored.
+func ValidateDimensions(dimensions []EstimationDimension, combination DimensionCombination) error {
+	if len(dimensions) == 0 {
+		return nil
+	}
+	if len(dimensions) > MaxEstimationDimensions {
+		return ErrTooManyDimensions
+	}
+	if _, err := ParseDimensionCombination(string(combination)); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(dimensions))
+	for _, dimension := range dimensions {
+		key := strings.TrimSpace(dimension.Key)
+		if key == "" || len(key) > 50 || seen[key] || dimension.Weight < 0 {
+			return ErrInvalidDimension
+		}
+		seen[key] = true
+
+		if err := ValidateVote("?", dimension.VotingSystem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateDimensionVotes requires exactly one valid card per dimension
+func ValidateDimensionVotes(values map[string]string, dimensions []EstimationDimension) error {
+	if len(values) != len(dimensions) {
+		return ErrDimensionVotesMismatch
+	}
+	for _, dimension := range dimensions {
+		value, ok := values[dimension.Key]
+		if !ok {
+			return ErrDimensionVotesMismatch
+		}
+		if err := ValidateVote(value, dimension.VotingSystem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CombineDimensions applies the combination formula to one value per dimension
+// and rounds the result to the closest card of the room's deck. It returns "?"
+// when any dimension is missing or not numeric.
+func (s *EstimationService) CombineDimensions(values map[string]string, dimensions []EstimationDimension, combination DimensionCombination) string {
+	if len(dimensions) == 0 {
+		return "?"
+	}
+
+	var sum, weightedSum, totalWeight, highest float64
+	for _, dimension := range dimensions {
+		value, ok := values[dimension.Key]
+		if !ok || value == "?" {
+			return "?"
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "?"
+		}
+
+		weight := dimension.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		sum += number
+		weightedSum += number * weight
+		totalWeight += weight
+		highest = math.Max(highest, number)
+	}
+
+	var result float64
+	switch combination {
+	case CombineAverage:
+		result = sum / float64(len(dimensions))
+	case CombineMax:
+		result = highest
+	case CombineWeighted:
+		result = weightedSum / totalWeight
+	default:
+		result = sum
+	}
+
+	return strconv.FormatFloat(RoundToClosestDbsFiboVote(result), 'f', -1, 64)
+}
+
+// DimensionResult is the revealed outcome of a single dimension
+type DimensionResult struct {
+	Key       string
+	Votes     map[string]string // userID -> vote value
+	Average   *float64          // nil when no vote is numeric
+	Suggested string
+}
+
+// EstimateDimensions computes a result per dimension from each user's dimension votes
+func (s *EstimationService) EstimateDimensions(votes map[string]map[string]string, dimensions []EstimationDimension) []DimensionResult {
+	results := make([]DimensionResult, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		result := DimensionResult{Key: dimension.Key, Votes: make(map[string]string, len(votes))}
+		for userID, values := range votes {
+			if value, ok := values[dimension.Key]; ok {
+				result.Votes[userID] = value
+			}
+		}
+
+		result.Suggested = s.SuggestEstimate(result.Votes, dimension.VotingSystem)
+		if result.Suggested != "" {
+			if average, err := s.CalculateAverage(result.Votes, dimension.VotingSystem); err == nil {
+				result.Average = &average
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,173 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func testDimensions() []EstimationDimension {
+	return []EstimationDimension{
+		{Key: "complexity", Name: "Complexity", VotingSystem: DbsFibo},
+		{Key: "effort", Name: "Effort", VotingSystem: DbsFibo, Weight: 2},
+		{Key: "uncertainty", Name: "Uncertainty", VotingSystem: Fibonacci},
+	}
+}
+
+func TestValidateDimensions(t *testing.T) {
+	tests := []struct {
+		name        string
+		dimensions  []EstimationDimension
+		combination DimensionCombination
+		expectedErr error
+	}{
+		{"no dimensions", nil, "", nil},
+		{"valid", testDimensions(), CombineSum, nil},
+		{"unknown combination", testDimensions(), "median", ErrUnknownCombination},
+		{"duplicate key", []EstimationDimension{{Key: "a", VotingSystem: DbsFibo}, {Key: "a", VotingSystem: DbsFibo}}, CombineSum, ErrInvalidDimension},
+		{"empty key", []EstimationDimension{{Key: " ", VotingSystem: DbsFibo}}, CombineSum, ErrInvalidDimension},
+		{"negative weight", []EstimationDimension{{Key: "a", VotingSystem: DbsFibo, Weight: -1}}, CombineWeighted, ErrInvalidDimension},
+		{"unknown deck", []EstimationDimension{{Key: "a", VotingSystem: "tshirt"}}, CombineSum, ErrVotingSystemUnknown},
+		{"too many", make([]EstimationDimension, MaxEstimationDimensions+1), CombineSum, ErrTooManyDimensions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDimensions(tt.dimensions, tt.combination)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNewRoom_NormalizesDimensions(t *testing.T) {
+	rm, err := NewRoom("Room", RoomSettings{
+		VotingSystem: DbsFibo,
+		Dimensions:   []EstimationDimension{{Key: " effort "}},
+		Combination:  CombineSum,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	dimension := rm.Dimensions[0]
+	if dimension.Key != "effort" || dimension.Name != "effort" {
+		t.Errorf("expected trimmed key used as name, got %q/%q", dimension.Key, dimension.Name)
+	}
+	if dimension.VotingSystem != DbsFibo {
+		t.Errorf("expected room deck %q, got %q", DbsFibo, dimension.VotingSystem)
+	}
+	if !rm.IsMultiDimensional() {
+		t.Error("expected room to be multi-dimensional")
+	}
+}
+
+func TestValidateDimensionVotes(t *testing.T) {
+	dimensions := testDimensions()
+
+	valid := map[string]string{"complexity": "3", "effort": "?", "uncertainty": "1"}
+	if err := ValidateDimensionVotes(valid, dimensions); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	missing := map[string]string{"complexity": "3", "effort": "5"}
+	if err := ValidateDimensionVotes(missing, dimensions); !errors.Is(err, ErrDimensionVotesMismatch) {
+		t.Errorf("expected ErrDimensionVotesMismatch, got %v", err)
+	}
+
+	unknown := map[string]string{"complexity": "3", "effort": "5", "risk": "1"}
+	if err := ValidateDimensionVotes(unknown, dimensions); !errors.Is(err, ErrDimensionVotesMismatch) {
+		t.Errorf("expected ErrDimensionVotesMismatch, got %v", err)
+	}
+
+	invalid := map[string]string{"complexity": "4", "effort": "5", "uncertainty": "1"}
+	if err := ValidateDimensionVotes(invalid, dimensions); !errors.Is(err, ErrInvalidVote) {
+		t.Errorf("expected ErrInvalidVote, got %v", err)
+	}
+}
+
+func TestEstimationService_CombineDimensions(t *testing.T) {
+	service := NewEstimationService()
+	dimensions := testDimensions()
+	values := map[string]string{"complexity": "3", "effort": "5", "uncertainty": "1"}
+
+	tests := []struct {
+		combination DimensionCombination
+		expected    string
+	}{
+		{CombineSum, "8"},      // 9 rounds to 8
+		{CombineAverage, "3"},  // 3
+		{CombineMax, "5"},      // 5
+		{CombineWeighted, "3"}, // (3 + 10 + 1) / 4 = 3.5 rounds to 3
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.combination), func(t *testing.T) {
+			got := service.CombineDimensions(values, dimensions, tt.combination)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	values["effort"] = "?"
+	if got := service.CombineDimensions(values, dimensions, CombineSum); got != "?" {
+		t.Errorf("expected \"?\" when a dimension is unknown, got %q", got)
+	}
+}
+
+func TestEstimationService_EstimateDimensions(t *testing.T) {
+	service := NewEstimationService()
+	dimensions := testDimensions()[:2]
+	votes := map[string]map[string]string{
+		"user1": {"complexity": "3", "effort": "?"},
+		"user2": {"complexity": "5", "effort": "?"},
+	}
+
+	results := service.EstimateDimensions(votes, dimensions)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	complexity := results[0]
+	if complexity.Key != "complexity" || complexity.Suggested != "5" {
+		t.Errorf("expected complexity suggestion 5, got %q/%q", complexity.Key, complexity.Suggested)
+	}
+	if complexity.Average == nil || *complexity.Average != 5 {
+		t.Errorf("expected complexity average 5, got %v", complexity.Average)
+	}
+	if complexity.Votes["user1"] != "3" {
+		t.Errorf("expected user1 complexity vote 3, got %q", complexity.Votes["user1"])
+	}
+
+	effort := results[1]
+	if effort.Suggested != "" || effort.Average != nil {
+		t.Errorf("expected no effort result for only \"?\" votes, got %q/%v", effort.Suggested, effort.Average)
+	}
+}
+
+func TestTaskSetEstimationBreakdown(t *testing.T) {
+	task, _ := NewTask("room1", "Task", 1)
+	dimensions := testDimensions()
+
+	if err := task.SetEstimationBreakdown(map[string]string{"complexity": "3", "effort": "5"}, dimensions); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.EstimationBreakdown["effort"] != "5" {
+		t.Errorf("expected effort 5, got %q", task.EstimationBreakdown["effort"])
+	}
+
+	if err := task.SetEstimationBreakdown(map[string]string{"risk": "3"}, dimensions); !errors.Is(err, ErrInvalidDimension) {
+		t.Errorf("expected ErrInvalidDimension, got %v", err)
+	}
+	if err := task.SetEstimationBreakdown(map[string]string{"effort": "?"}, dimensions); !errors.Is(err, ErrInvalidEstimation) {
+		t.Errorf("expected ErrInvalidEstimation, got %v", err)
+	}
+
+	if err := task.SetEstimationBreakdown(nil, dimensions); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.EstimationBreakdown != nil {
+		t.Errorf("expected breakdown to be cleared, got %v", task.EstimationBreakdown)
+	}
+}
@@ -13,19 +13,84 @@ var (
 	ErrEmptyUserName     = errors.New("user name cannot be empty")
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists in room")
+	ErrUnknownHandSignal = errors.New("unknown hand signal")
+
+	ErrLowerHandNotAllowed = errors.New("only the facilitator can lower another user's hand")
 
 	ErrInvalidVote         = errors.New("invalid vote value")
 	ErrVotingSystemUnknown = errors.New("unknown voting system")
 	ErrNoVotes             = errors.New("no votes to calculate")
 	ErrVotesNotRevealed    = errors.New("votes have not been revealed yet")
+	ErrVoteCommentTooLong  = errors.New("vote comment exceeds maximum length of 280 characters")
+	ErrVotesLocked         = errors.New("votes are locked once revealed")
+
+	ErrUnknownVoteChangePolicy = errors.New("unknown vote change policy")
+
+	ErrInvalidDimension       = errors.New("estimation dimensions need a unique key of at most 50 characters and a non-negative weight")
+	ErrTooManyDimensions      = errors.New("room cannot have more than 5 estimation dimensions")
+	ErrUnknownCombination     = errors.New("unknown dimension combination")
+	ErrDimensionVotesMismatch = errors.New("a vote is required for every estimation dimension")
+
+	ErrUnknownEstimationMode     = errors.New("unknown estimation mode")
+	ErrIncompatibleModes         = errors.New("three-point estimation cannot be combined with estimation dimensions")
+	ErrInvalidThreePointVote     = errors.New("three-point vote needs numeric optimistic <= most likely <= pessimistic values")
+	ErrThreePointVoteRequired    = errors.New("this room expects optimistic, most likely and pessimistic values")
+	ErrThreePointVoteNotExpected = errors.New("this room does not use three-point estimation")
 
 	ErrRoomAlreadyExists = errors.New("room already exists")
 	ErrRoomEmpty         = errors.New("room has no users")
+	ErrRoomFull          = errors.New("room has reached its participant limit")
+
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrInvalidTaskID         = errors.New("invalid task ID")
+	ErrEmptyTaskHeadline     = errors.New("task headline cannot be empty")
+	ErrTaskHeadlineTooLong   = errors.New("task headline exceeds maximum length of 255 characters")
+	ErrInvalidTaskPosition   = errors.New("invalid task position")
+	ErrActiveTaskNotFound    = errors.New("no active task found in the room")
+	ErrTaskLimitReached      = errors.New("room has reached its task limit")
+	ErrInvalidTaskStatus     = errors.New("invalid task status")
+	ErrInvalidTaskTransition = errors.New("task status change is not allowed")
+	ErrInvalidEstimation     = errors.New("estimation must be a card from the room's deck")
+	ErrOverrideReasonTooLong = errors.New("override reason exceeds maximum length of 500 characters")
+	ErrOverrideNotAllowed    = errors.New("only the facilitator can accept a value other than the suggested one")
+	ErrNoOpenTasks           = errors.New("no open tasks left in the backlog")
+	ErrNoPreviousTask        = errors.New("already at the first task")
+	ErrTaskNotEstimated      = errors.New("task has not been estimated yet")
+	ErrNotesNotAllowed       = errors.New("only the facilitator can edit the decision log")
+
+	ErrTaskCommentNotFound = errors.New("comment not found")
+	ErrEmptyTaskComment    = errors.New("comment cannot be empty")
+	ErrTaskCommentTooLong  = errors.New("comment exceeds maximum length of 2000 characters")
+	ErrNotCommentAuthor    = errors.New("only the author can change a comment")
+
+	ErrTooManyTaskNotes = errors.New("decision log exceeds maximum of 20 notes")
+	ErrTaskNoteTooLong  = errors.New("note exceeds maximum length of 200 characters")
+
+	ErrCriterionNotFound         = errors.New("acceptance criterion not found")
+	ErrEmptyCriterion            = errors.New("acceptance criterion cannot be empty")
+	ErrCriterionTooLong          = errors.New("acceptance criterion exceeds maximum length of 500 characters")
+	ErrTooManyCriteria           = errors.New("task has reached its limit of 30 acceptance criteria")
+	ErrInvalidCriteriaOrder      = errors.New("criteria order must list every criterion of the task exactly once")
+	ErrMissingAcceptanceCriteria = errors.New("task needs acceptance criteria before it can be voted on")
+
+	ErrUnknownTaskType   = errors.New("unknown task type")
+	ErrTooManyTaskLabels = errors.New("task exceeds maximum of 10 labels")
+	ErrTaskLabelTooLong  = errors.New("label exceeds maximum length of 30 characters")
+
+	ErrParentTaskNotFound = errors.New("parent task not found in this room")
+	ErrTaskHierarchyCycle = errors.New("a task cannot be placed under itself or one of its subtasks")
+
+	ErrInvalidTaskRank = errors.New("invalid task rank")
+	ErrTaskRankTaken   = errors.New("another task was moved to the same place, try again")
+	ErrInvalidTaskMove = errors.New("a task must be moved before or after another task")
+
+	ErrTaskVersionRequired = errors.New("the task version being edited is required")
+	ErrTaskVersionConflict = errors.New("task was changed by someone else")
+
+	ErrDependencyTaskNotFound = errors.New("dependency must be a task of the same room")
+	ErrTaskDependencyCycle    = errors.New("task dependencies cannot form a cycle")
+	ErrTaskDependencyNotFound = errors.New("task does not depend on that task")
 
-	ErrTaskNotFound        = errors.New("task not found")
-	ErrInvalidTaskID       = errors.New("invalid task ID")
-	ErrEmptyTaskHeadline   = errors.New("task headline cannot be empty")
-	ErrTaskHeadlineTooLong = errors.New("task headline exceeds maximum length of 255 characters")
-	ErrInvalidTaskPosition = errors.New("invalid task position")
-	ErrActiveTaskNotFound  = errors.New("no active task found in the room")
+	ErrNothingToUndo  = errors.New("nothing to undo")
+	ErrUndoNotAllowed = errors.New("only the person who made the change or the facilitator can undo it")
 )
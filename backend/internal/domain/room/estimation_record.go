@@ -0,0 +1,55 @@
+package room
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EstimationSource tells how a task's estimation was decided
+type EstimationSource string
+
+const (
+	EstimationSourceVote     EstimationSource = "vote"
+	EstimationSourceOverride EstimationSource = "override"
+)
+
+// EstimationRecord is one entry of a task's estimation history
+type EstimationRecord struct {
+	ID           string
+	TaskID       string
+	Value        string
+	Source       EstimationSource
+	Reason       string
+	Participants []string          // names of the users who voted in the round
+	Notes        []string          // the task's decision log when the estimate was accepted
+	Comments     []VoteComment     // rationale the voters left with their votes
+	Changes      []VoteChange      // votes changed after the reveal
+	VoterNames   map[string]string // user ID -> name of each voter in Changes, as they were called in the round
+	CreatedAt    time.Time
+}
+
+// NewEstimationRecord snapshots the estimation the task has just accepted
+func NewEstimationRecord(task *Task, participants []string) *EstimationRecord {
+	source := EstimationSourceVote
+	if task.EstimationOverridden {
+		source = EstimationSourceOverride
+	}
+
+	var notes []string
+	if task.Notes != nil {
+		notes = slices.Clone(task.Notes.Items)
+	}
+
+	return &EstimationRecord{
+		ID:           uuid.New().String(),
+		TaskID:       task.ID,
+		Value:        task.Estimation,
+		Source:       source,
+		Reason:       task.OverrideReason,
+		Participants: participants,
+		Notes:        notes,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
@@ -1,5 +1,7 @@
 package room
 
+import "strconv"
+
 type EstimationService struct{}
 
 func NewEstimationService() *EstimationService {
@@ -56,3 +58,35 @@ func (s *EstimationService) ValidateAllVotes(votes map[string]string, votingSyst
 	}
 	return nil
 }
+
+// SuggestEstimate proposes a card from the deck for the revealed votes: the
+// numeric average rounded to the closest card. It returns "" when there is
+// nothing to suggest, including when every vote is "?", as "?" cannot be accepted.
+func (s *EstimationService) SuggestEstimate(votes map[string]string, votingSystem VotingSystem) string {
+	if len(votes) == 0 {
+		return ""
+	}
+
+	var numeric bool
+	for _, voteValue := range votes {
+		vote, err := CreateVote(voteValue, votingSystem)
+		if err != nil {
+			return ""
+		}
+		if vote.IsNumeric() {
+			numeric = true
+		}
+	}
+
+	if !numeric {
+		// Only "?" votes: nobody could estimate
+		return ""
+	}
+
+	average, err := s.CalculateAverage(votes, votingSystem)
+	if err != nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(RoundToClosestDbsFiboVote(average), 'f', -1, 64)
+}
@@ -248,3 +248,29 @@ func TestEstimationService_ValidateAllVotes(t *testing.T) {
 		})
 	}
 }
+
+func TestSuggestEstimate(t *testing.T) {
+	service := NewEstimationService()
+
+	tests := []struct {
+		name     string
+		votes    map[string]string
+		expected string
+	}{
+		{"Rounded average", map[string]string{"u1": "5", "u2": "8"}, "8"},
+		{"Half point", map[string]string{"u1": "0.5", "u2": "0.5"}, "0.5"},
+		{"Ignores unknown", map[string]string{"u1": "3", "u2": "?"}, "3"},
+		{"Only unknown", map[string]string{"u1": "?", "u2": "?"}, ""},
+		{"Single unknown", map[string]string{"u1": "?"}, ""},
+		{"No votes", map[string]string{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := service.SuggestEstimate(tt.votes, Fibonacci)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package room
+
+import "time"
+
+// HandSignal is what a participant raises their hand for
+type HandSignal string
+
+const (
+	SignalQuestion HandSignal = "question" // a question before the team votes
+	SignalSplit    HandSignal = "split"    // the story is too big and should be split
+)
+
+func ParseHandSignal(value string) (HandSignal, error) {
+	switch signal := HandSignal(value); signal {
+	case "":
+		return SignalQuestion, nil
+	case SignalQuestion, SignalSplit:
+		return signal, nil
+	default:
+		return "", ErrUnknownHandSignal
+	}
+}
+
+// RaisedHand is one entry of a room's hand queue; the queue is ordered by RaisedAt
+type RaisedHand struct {
+	UserID   string
+	Signal   HandSignal
+	RaisedAt time.Time
+}
@@ -0,0 +1,21 @@
+package room
+
+// RoomLimits caps how large a single room may grow; zero means unlimited
+type RoomLimits struct {
+	MaxTasks        int
+	MaxParticipants int
+}
+
+func (l RoomLimits) CheckTaskCount(current int) error {
+	if l.MaxTasks > 0 && current >= l.MaxTasks {
+		return ErrTaskLimitReached
+	}
+	return nil
+}
+
+func (l RoomLimits) CheckParticipantCount(current int) error {
+	if l.MaxParticipants > 0 && current >= l.MaxParticipants {
+		return ErrRoomFull
+	}
+	return nil
+}
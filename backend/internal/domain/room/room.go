@@ -10,6 +10,36 @@ import (
 type RoomSettings struct {
 	VotingSystem VotingSystem
 	AutoReveal   bool
+
+	// Optional axes voted on separately; the final estimate is derived with Combination
+	Dimensions  []EstimationDimension
+	Combination DimensionCombination
+
+	Mode EstimationMode
+
+	// Revealed votes are shown only as a distribution, never next to the voter's name
+	AnonymousVotes bool
+
+	VoteChangePolicy VoteChangePolicy
+
+	// Votes are refused on a task that has no acceptance criteria yet
+	RequireAcceptanceCriteria bool
+
+	// Saved filter restricting which tasks are picked next for estimation
+	TaskFilter TaskFilter
+}
+
+// VotesLockedAfterReveal tells whether votes are refused once revealed
+func (s RoomSettings) VotesLockedAfterReveal() bool {
+	return s.VoteChangePolicy == VoteChangesLocked
+}
+
+func (s RoomSettings) IsMultiDimensional() bool {
+	return len(s.Dimensions) > 0
+}
+
+func (s RoomSettings) IsThreePoint() bool {
+	return s.Mode == ModeThreePoint
 }
 
 type Room struct {
@@ -24,6 +54,21 @@ func NewRoom(name string, settings RoomSettings) (*Room, error) {
 	if err := ValidateRoomName(name); err != nil {
 		return nil, err
 	}
+	settings.Dimensions = normalizeDimensions(settings.Dimensions, settings.VotingSystem)
+	if err := ValidateDimensions(settings.Dimensions, settings.Combination); err != nil {
+		return nil, err
+	}
+	mode, err := ParseEstimationMode(string(settings.Mode))
+	if err != nil {
+		return nil, err
+	}
+	if mode == ModeThreePoint && len(settings.Dimensions) > 0 {
+		return nil, ErrIncompatibleModes
+	}
+	settings.Mode = mode
+	if settings.VoteChangePolicy, err = ParseVoteChangePolicy(string(settings.VoteChangePolicy)); err != nil {
+		return nil, err
+	}
 
 	roomID := strings.ReplaceAll(uuid.New().String()[:13], "-", "")[:8]
 	now := time.Now()
@@ -50,6 +95,27 @@ func ValidateRoomName(name string) error {
 	return nil
 }
 
+// normalizeDimensions trims keys and names and lets dimensions without their own deck use the room's
+func normalizeDimensions(dimensions []EstimationDimension, votingSystem VotingSystem) []EstimationDimension {
+	if len(dimensions) == 0 {
+		return nil
+	}
+
+	normalized := make([]EstimationDimension, len(dimensions))
+	for i, dimension := range dimensions {
+		dimension.Key = strings.TrimSpace(dimension.Key)
+		dimension.Name = strings.TrimSpace(dimension.Name)
+		if dimension.Name == "" {
+			dimension.Name = dimension.Key
+		}
+		if dimension.VotingSystem == "" {
+			dimension.VotingSystem = votingSystem
+		}
+		normalized[i] = dimension
+	}
+	return normalized
+}
+
 func (r *Room) UpdateName(name string) error {
 	if err := ValidateRoomName(name); err != nil {
 		return err
@@ -59,6 +125,12 @@ func (r *Room) UpdateName(name string) error {
 	return nil
 }
 
+// UpdateTaskFilter saves the filter used to pick the next task to estimate
+func (r *Room) UpdateTaskFilter(filter TaskFilter) {
+	r.TaskFilter = filter
+	r.UpdatedAt = time.Now()
+}
+
 func (r *Room) UpdateSettings(settings RoomSettings) {
 	r.RoomSettings = settings
 	r.UpdatedAt = time.Now()
@@ -1,11 +1,14 @@
 package room
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
+const MaxOverrideReasonLength = 500
+
 type Task struct {
 	ID          string
 	RoomID      string
@@ -13,7 +16,41 @@ type Task struct {
 	Description string
 	TrackerLink string
 	Estimation  string
-	Position    int
+	Status      TaskStatus
+	Position    int    // 1-based place in the backlog, derived from the ranks when the backlog is loaded
+	Rank        string // orders the task among its siblings, see RankBetween
+
+	// Incremented on every saved change, so edits made on an outdated copy can be detected
+	Version int
+
+	// Epic this task is a subtask of, empty for top-level tasks
+	ParentID string
+
+	// IDs of the tasks this one is blocked by, in the order they were added
+	DependsOn []string
+
+	Type   TaskType
+	Labels []string // lower-cased, without duplicates
+
+	// Set when the accepted estimation differs from the one suggested by the votes
+	EstimationOverridden bool
+	OverrideReason       string
+
+	// Estimation accepted before the current one, empty until the task is re-estimated
+	PreviousEstimation string
+
+	// Accepted value per estimation dimension (dimension key -> card), empty in single-value rooms
+	EstimationBreakdown map[string]string
+
+	// Team's three-point estimate behind the accepted card, nil outside three-point rooms
+	Pert *PertEstimate
+
+	// Decision log: current assumptions, nil until first written, and every earlier revision, oldest first
+	Notes        *TaskNotes
+	NotesHistory []TaskNotes
+
+	// Ordered checklist of what "done" means for the task
+	AcceptanceCriteria []AcceptanceCriterion
 }
 
 func NewTask(roomID, headline string, position int) (*Task, error) {
@@ -34,7 +71,11 @@ func NewTask(roomID, headline string, position int) (*Task, error) {
 		Description: "",
 		TrackerLink: "",
 		Estimation:  "",
+		Status:      TaskStatusPending,
 		Position:    position,
+		Rank:        InitialRank(position),
+		Version:     1,
+		Type:        TaskTypeStory,
 	}, nil
 }
 
@@ -65,13 +106,173 @@ func (t *Task) UpdateTrackerLink(link string) {
 	t.TrackerLink = strings.TrimSpace(link)
 }
 
+// SetEstimation records the estimate and marks the task estimated, unless the
+// value is "?" (no consensus), in which case the task stays in the queue
 func (t *Task) SetEstimation(value string, votingSystem VotingSystem) error {
-	if value != "" {
-		t.Estimation = strings.TrimSpace(value)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if value != "?" {
+		if err := t.transitionTo(TaskStatusEstimated); err != nil {
+			return err
+		}
+	}
+	t.Estimation = value
+	return nil
+}
+
+// AcceptEstimation records the final estimation chosen by the facilitator.
+// The value must be a card of the room's deck; it counts as an override when
+// it differs from the suggested value, and only then is the reason kept.
+func (t *Task) AcceptEstimation(value, suggested, reason string, votingSystem VotingSystem) error {
+	value = strings.TrimSpace(value)
+	if value == "?" || ValidateVote(value, votingSystem) != nil {
+		return ErrInvalidEstimation
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len(reason) > MaxOverrideReasonLength {
+		return ErrOverrideReasonTooLong
+	}
+
+	if err := t.transitionTo(TaskStatusEstimated); err != nil {
+		return err
+	}
+
+	if t.IsEstimatedValue() {
+		t.PreviousEstimation = t.Estimation
+	}
+	t.Estimation = value
+	t.EstimationOverridden = suggested != "" && !sameEstimate(value, suggested)
+	t.OverrideReason = ""
+	if t.EstimationOverridden {
+		t.OverrideReason = reason
+	}
+
+	return nil
+}
+
+// SetEstimationBreakdown stores the per-dimension values behind the final estimation.
+// Dimensions without a value (e.g. only "?" votes) are left out; an empty breakdown clears it.
+func (t *Task) SetEstimationBreakdown(breakdown map[string]string, dimensions []EstimationDimension) error {
+	if len(breakdown) == 0 {
+		t.EstimationBreakdown = nil
+		return nil
+	}
+
+	systems := make(map[string]VotingSystem, len(dimensions))
+	for _, dimension := range dimensions {
+		systems[dimension.Key] = dimension.VotingSystem
+	}
+
+	result := make(map[string]string, len(breakdown))
+	for key, value := range breakdown {
+		system, ok := systems[key]
+		if !ok {
+			return ErrInvalidDimension
+		}
+		value = strings.TrimSpace(value)
+		if value == "?" || ValidateVote(value, system) != nil {
+			return ErrInvalidEstimation
+		}
+		result[key] = value
 	}
+
+	t.EstimationBreakdown = result
 	return nil
 }
 
+// SetPertEstimate stores the expected value and range behind the accepted estimation
+func (t *Task) SetPertEstimate(pert *PertEstimate) {
+	if pert == nil {
+		t.Pert = nil
+		return
+	}
+	stored := *pert
+	t.Pert = &stored
+}
+
+// sameEstimate compares numerically so that "5" and "5.0" are equal
+func sameEstimate(a, b string) bool {
+	af, errA := strconv.ParseFloat(a, 64)
+	bf, errB := strconv.ParseFloat(b, 64)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return af == bf
+}
+
 func (t *Task) IsEstimated() bool {
+	return t.Status == TaskStatusEstimated
+}
+
+// IsEstimatedValue reports whether the task carries a real estimate, even if it was reopened since
+func (t *Task) IsEstimatedValue() bool {
 	return t.Estimation != "" && t.Estimation != "?"
 }
+
+// IsOpen reports whether the task still waits to be estimated
+func (t *Task) IsOpen() bool {
+	return t.Status.IsOpen()
+}
+
+func (t *Task) StartDiscussion() error {
+	return t.transitionTo(TaskStatusInDiscussion)
+}
+
+// StopDiscussion returns a task that was being discussed to the queue
+func (t *Task) StopDiscussion() error {
+	if t.Status != TaskStatusInDiscussion {
+		return nil
+	}
+	return t.transitionTo(TaskStatusPending)
+}
+
+func (t *Task) Skip() error {
+	return t.transitionTo(TaskStatusSkipped)
+}
+
+// Defer parks a task that needs more information before it can be estimated
+func (t *Task) Defer() error {
+	return t.transitionTo(TaskStatusDeferred)
+}
+
+// Reestimate reopens an estimated task for another round of voting.
+// The current estimation is kept and becomes the previous one once a new value is accepted.
+func (t *Task) Reestimate() error {
+	if t.Status != TaskStatusEstimated {
+		return ErrTaskNotEstimated
+	}
+	return t.transitionTo(TaskStatusPending)
+}
+
+// RevertEstimation undoes an accepted estimation, bringing back the status and
+// estimation the task had in before. The task must still be estimated.
+func (t *Task) RevertEstimation(before *Task) error {
+	if t.Status != TaskStatusEstimated {
+		return ErrTaskNotEstimated
+	}
+	t.Status = before.Status
+	t.Estimation = before.Estimation
+	t.EstimationOverridden = before.EstimationOverridden
+	t.OverrideReason = before.OverrideReason
+	t.PreviousEstimation = before.PreviousEstimation
+	t.EstimationBreakdown = before.EstimationBreakdown
+	t.Pert = before.Pert
+	return nil
+}
+
+// Reopen puts a skipped, deferred or estimated task back into the queue.
+// A previous estimation is kept until the task is estimated again.
+func (t *Task) Reopen() error {
+	return t.transitionTo(TaskStatusPending)
+}
+
+func (t *Task) transitionTo(next TaskStatus) error {
+	if !t.Status.CanTransitionTo(next) {
+		return ErrInvalidTaskTransition
+	}
+	t.Status = next
+	return nil
+}
@@ -0,0 +1,84 @@
+package room
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const MaxTaskCommentLength = 2000
+
+// TaskComment is one message of a task's discussion thread. The author's name is
+// copied at posting time so the thread still reads right after they leave the room.
+type TaskComment struct {
+	ID         string
+	TaskID     string
+	AuthorID   string
+	AuthorName string
+	Body       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewTaskComment(taskID, authorID, authorName, body string) (*TaskComment, error) {
+	if taskID == "" {
+		return nil, ErrInvalidTaskID
+	}
+	if authorID == "" {
+		return nil, ErrInvalidUserID
+	}
+	body, err := validateTaskCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &TaskComment{
+		ID:         uuid.New().String(),
+		TaskID:     taskID,
+		AuthorID:   authorID,
+		AuthorName: strings.TrimSpace(authorName),
+		Body:       body,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// Edit replaces the body; only the author may change their comment
+func (c *TaskComment) Edit(authorID, body string) error {
+	if err := c.CheckAuthor(authorID); err != nil {
+		return err
+	}
+	body, err := validateTaskCommentBody(body)
+	if err != nil {
+		return err
+	}
+
+	c.Body = body
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (c *TaskComment) CheckAuthor(userID string) error {
+	if c.AuthorID != userID {
+		return ErrNotCommentAuthor
+	}
+	return nil
+}
+
+func (c *TaskComment) IsEdited() bool {
+	return c.UpdatedAt.After(c.CreatedAt)
+}
+
+func validateTaskCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", ErrEmptyTaskComment
+	}
+	if utf8.RuneCountInString(body) > MaxTaskCommentLength {
+		return "", ErrTaskCommentTooLong
+	}
+	return body, nil
+}
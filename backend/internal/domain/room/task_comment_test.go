@@ -0,0 +1,70 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTaskComment(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        string
+		authorID      string
+		body          string
+		expectedError error
+	}{
+		{"Valid comment", "task1", "user1", "Do we need a migration?", nil},
+		{"Empty task", "", "user1", "Hi", ErrInvalidTaskID},
+		{"Empty author", "task1", "", "Hi", ErrInvalidUserID},
+		{"Blank body", "task1", "user1", "   ", ErrEmptyTaskComment},
+		{"Too long", "task1", "user1", strings.Repeat("a", MaxTaskCommentLength+1), ErrTaskCommentTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment, err := NewTaskComment(tt.taskID, tt.authorID, " Alice ", tt.body)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if comment.AuthorName != "Alice" {
+				t.Errorf("expected author name Alice, got %q", comment.AuthorName)
+			}
+			if comment.IsEdited() {
+				t.Error("expected new comment not to be edited")
+			}
+		})
+	}
+}
+
+func TestTaskComment_Edit(t *testing.T) {
+	comment, err := NewTaskComment("task1", "user1", "Alice", "first")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	comment.CreatedAt = comment.CreatedAt.Add(-time.Minute)
+
+	if err := comment.Edit("user2", "hijack"); !errors.Is(err, ErrNotCommentAuthor) {
+		t.Errorf("expected ErrNotCommentAuthor, got %v", err)
+	}
+	if err := comment.Edit("user1", " "); !errors.Is(err, ErrEmptyTaskComment) {
+		t.Errorf("expected ErrEmptyTaskComment, got %v", err)
+	}
+	if comment.Body != "first" {
+		t.Errorf("expected rejected edits to keep the body, got %q", comment.Body)
+	}
+
+	if err := comment.Edit("user1", " second "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.Body != "second" {
+		t.Errorf("expected body second, got %q", comment.Body)
+	}
+	if !comment.IsEdited() {
+		t.Error("expected comment to be marked as edited")
+	}
+}
@@ -0,0 +1,132 @@
+package room
+
+import "slices"
+
+// DependsOnTask reports whether the task is blocked by dependsOnID
+func (t *Task) DependsOnTask(dependsOnID string) bool {
+	return slices.Contains(t.DependsOn, dependsOnID)
+}
+
+// ValidateDependency checks that taskID may depend on dependsOnID: both must be tasks of
+// the room and the new edge must not close a cycle, directly or through other tasks.
+func ValidateDependency(tasks []*Task, taskID, dependsOnID string) error {
+	byID := make(map[string]*Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	if _, ok := byID[taskID]; !ok {
+		return ErrTaskNotFound
+	}
+	if _, ok := byID[dependsOnID]; !ok {
+		return ErrDependencyTaskNotFound
+	}
+
+	// A cycle exists when taskID is already reachable from dependsOnID
+	seen := make(map[string]bool)
+	var reaches func(id string) bool
+	reaches = func(id string) bool {
+		if id == taskID {
+			return true
+		}
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+		task, ok := byID[id]
+		return ok && slices.ContainsFunc(task.DependsOn, reaches)
+	}
+	if reaches(dependsOnID) {
+		return ErrTaskDependencyCycle
+	}
+	return nil
+}
+
+// TopologicalOrder proposes a backlog order in which tasks come after the tasks they depend on.
+// Epics stay directly followed by their subtasks, so a dependency between subtasks of two epics
+// orders the epics themselves. Tasks free to go keep their current backlog order, and dependencies
+// the hierarchy cannot honour (e.g. crossing in both directions between two epics) are left as is.
+func TopologicalOrder(tasks []*Task) []*Task {
+	tree := NewTaskTree(tasks)
+
+	// Lift every dependency to the pair of siblings holding its two ends
+	before := make(map[string][]string)
+	for _, task := range tasks {
+		for _, dependsOnID := range task.DependsOn {
+			if a, b, ok := tree.siblingAncestors(task.ID, dependsOnID); ok {
+				before[a] = append(before[a], b)
+			}
+		}
+	}
+
+	ordered := make([]*Task, 0, len(tasks))
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, task := range orderSiblings(tree.children[parentID], before) {
+			ordered = append(ordered, task)
+			walk(task.ID)
+		}
+	}
+	walk("")
+	return ordered
+}
+
+// orderSiblings sorts siblings so each comes after the siblings listed in before, preferring
+// position order; when only cycles remain the first remaining sibling goes next
+func orderSiblings(siblings []*Task, before map[string][]string) []*Task {
+	remaining := slices.Clone(siblings)
+	placed := make(map[string]bool, len(siblings))
+	isSibling := make(map[string]bool, len(siblings))
+	for _, task := range siblings {
+		isSibling[task.ID] = true
+	}
+
+	ready := func(task *Task) bool {
+		for _, id := range before[task.ID] {
+			if isSibling[id] && !placed[id] {
+				return false
+			}
+		}
+		return true
+	}
+
+	ordered := make([]*Task, 0, len(siblings))
+	for len(remaining) > 0 {
+		next := slices.IndexFunc(remaining, ready)
+		if next < 0 {
+			next = 0
+		}
+		task := remaining[next]
+		remaining = slices.Delete(remaining, next, next+1)
+		placed[task.ID] = true
+		ordered = append(ordered, task)
+	}
+	return ordered
+}
+
+// siblingAncestors returns the ancestors of a and b (or a and b themselves) that share a parent.
+// It fails when either task is missing or one is an ancestor of the other.
+func (tr *TaskTree) siblingAncestors(a, b string) (string, string, bool) {
+	pathA, pathB := tr.path(a), tr.path(b)
+	if pathA == nil || pathB == nil {
+		return "", "", false
+	}
+	for i := 0; i < len(pathA) && i < len(pathB); i++ {
+		if pathA[i] != pathB[i] {
+			return pathA[i], pathB[i], true
+		}
+	}
+	return "", "", false
+}
+
+// path lists the task's ancestors from the top level down to the task itself
+func (tr *TaskTree) path(taskID string) []string {
+	if _, ok := tr.byID[taskID]; !ok {
+		return nil
+	}
+	var path []string
+	for id := taskID; id != "" && !slices.Contains(path, id); id = tr.Parent(id) {
+		path = append(path, id)
+	}
+	slices.Reverse(path)
+	return path
+}
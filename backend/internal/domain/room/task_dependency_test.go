@@ -0,0 +1,54 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateDependency(t *testing.T) {
+	_, tasks := newTestTree(3)
+	tasks[1].DependsOn = []string{tasks[0].ID}
+	tasks[2].DependsOn = []string{tasks[1].ID}
+
+	if err := ValidateDependency(tasks, tasks[2].ID, tasks[0].ID); err != nil {
+		t.Errorf("expected a redundant dependency to be allowed, got %v", err)
+	}
+	if err := ValidateDependency(tasks, tasks[0].ID, tasks[2].ID); !errors.Is(err, ErrTaskDependencyCycle) {
+		t.Errorf("expected ErrTaskDependencyCycle through another task, got %v", err)
+	}
+	if err := ValidateDependency(tasks, tasks[0].ID, tasks[0].ID); !errors.Is(err, ErrTaskDependencyCycle) {
+		t.Errorf("expected ErrTaskDependencyCycle for itself, got %v", err)
+	}
+	if err := ValidateDependency(tasks, tasks[0].ID, "other-room"); !errors.Is(err, ErrDependencyTaskNotFound) {
+		t.Errorf("expected ErrDependencyTaskNotFound, got %v", err)
+	}
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	_, tasks := newTestTree(4)
+	// 1 needs 3, everything else keeps its place
+	tasks[0].DependsOn = []string{tasks[2].ID}
+
+	ordered := TopologicalOrder(tasks)
+	want := []*Task{tasks[1], tasks[2], tasks[0], tasks[3]}
+	for i, task := range want {
+		if ordered[i] != task {
+			t.Fatalf("expected task %s at index %d, got %s", task.ID, i, ordered[i].ID)
+		}
+	}
+}
+
+func TestTopologicalOrder_KeepsSubtasksWithTheirEpic(t *testing.T) {
+	tree, tasks := newTestTree(4)
+	_ = tree.SetParent(tasks[1].ID, tasks[0].ID)
+	// The first epic's subtask depends on task 3, so the whole epic moves after it
+	tasks[1].DependsOn = []string{tasks[2].ID}
+
+	ordered := TopologicalOrder(tasks)
+	want := []*Task{tasks[2], tasks[0], tasks[1], tasks[3]}
+	for i, task := range want {
+		if ordered[i] != task {
+			t.Fatalf("expected task %s at index %d, got %s", task.ID, i, ordered[i].ID)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package room
+
+import "slices"
+
+// TaskFilter narrows a backlog down to some labels, types and statuses.
+// Each empty field matches everything; a task matches Labels when it has any of them.
+type TaskFilter struct {
+	Labels   []string
+	Types    []TaskType
+	Statuses []TaskStatus
+}
+
+func NewTaskFilter(labels, types, statuses []string) (TaskFilter, error) {
+	var filter TaskFilter
+	var err error
+
+	if filter.Labels, err = NormalizeLabels(labels); err != nil {
+		return TaskFilter{}, err
+	}
+	for _, t := range types {
+		if t == "" {
+			continue
+		}
+		parsed, err := ParseTaskType(t)
+		if err != nil {
+			return TaskFilter{}, err
+		}
+		filter.Types = append(filter.Types, parsed)
+	}
+	for _, s := range statuses {
+		if s == "" {
+			continue
+		}
+		parsed, err := ParseTaskStatus(s)
+		if err != nil {
+			return TaskFilter{}, err
+		}
+		filter.Statuses = append(filter.Statuses, parsed)
+	}
+	return filter, nil
+}
+
+func (f TaskFilter) IsEmpty() bool {
+	return len(f.Labels) == 0 && len(f.Types) == 0 && len(f.Statuses) == 0
+}
+
+func (f TaskFilter) Matches(task *Task) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, task.Type) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, task.Status) {
+		return false
+	}
+	if len(f.Labels) > 0 && !slices.ContainsFunc(f.Labels, task.HasLabel) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the tasks matching the filter, keeping their order
+func (f TaskFilter) Apply(tasks []*Task) []*Task {
+	if f.IsEmpty() {
+		return tasks
+	}
+	var matching []*Task
+	for _, task := range tasks {
+		if f.Matches(task) {
+			matching = append(matching, task)
+		}
+	}
+	return matching
+}
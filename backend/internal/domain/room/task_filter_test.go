@@ -0,0 +1,73 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTaskType(t *testing.T) {
+	if got, err := ParseTaskType(""); err != nil || got != TaskTypeStory {
+		t.Errorf("expected empty type to default to story, got %q (%v)", got, err)
+	}
+	if got, err := ParseTaskType(" Bug "); err != nil || got != TaskTypeBug {
+		t.Errorf("expected bug, got %q (%v)", got, err)
+	}
+	if _, err := ParseTaskType("epic"); !errors.Is(err, ErrUnknownTaskType) {
+		t.Errorf("expected ErrUnknownTaskType, got %v", err)
+	}
+}
+
+func TestTaskSetLabels(t *testing.T) {
+	task, _ := NewTask("room123", "Task", 1)
+
+	if err := task.SetLabels([]string{" Backend", "backend", "", "UI"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(task.Labels) != 2 || !task.HasLabel("backend") || !task.HasLabel("ui") {
+		t.Errorf("expected labels [backend ui], got %v", task.Labels)
+	}
+
+	if err := task.SetLabels([]string{strings.Repeat("a", MaxTaskLabelLength+1)}); !errors.Is(err, ErrTaskLabelTooLong) {
+		t.Errorf("expected ErrTaskLabelTooLong, got %v", err)
+	}
+
+	tooMany := make([]string, MaxTaskLabels+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("l", i+1)
+	}
+	if err := task.SetLabels(tooMany); !errors.Is(err, ErrTooManyTaskLabels) {
+		t.Errorf("expected ErrTooManyTaskLabels, got %v", err)
+	}
+	if len(task.Labels) != 2 {
+		t.Errorf("expected rejected labels to leave the task unchanged, got %v", task.Labels)
+	}
+}
+
+func TestTaskFilterMatches(t *testing.T) {
+	story, _ := NewTask("room123", "Story", 1)
+	_ = story.SetLabels([]string{"backend"})
+	bug, _ := NewTask("room123", "Bug", 2)
+	_ = bug.SetType("bug")
+	_ = bug.SetLabels([]string{"backend", "urgent"})
+	estimated, _ := NewTask("room123", "Done", 3)
+	estimated.Status = TaskStatusEstimated
+
+	filter, err := NewTaskFilter([]string{"Backend"}, []string{"story"}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !filter.Matches(story) || filter.Matches(bug) || filter.Matches(estimated) {
+		t.Errorf("expected only the backend story to match")
+	}
+
+	filter, _ = NewTaskFilter(nil, nil, []string{"pending"})
+	matching := filter.Apply([]*Task{story, bug, estimated})
+	if len(matching) != 2 || matching[0] != story || matching[1] != bug {
+		t.Errorf("expected pending tasks in order, got %v", matching)
+	}
+
+	if _, err := NewTaskFilter(nil, nil, []string{"finished"}); !errors.Is(err, ErrInvalidTaskStatus) {
+		t.Errorf("expected ErrInvalidTaskStatus, got %v", err)
+	}
+}
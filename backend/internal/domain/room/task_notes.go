@@ -0,0 +1,63 @@
+package room
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxTaskNotes          = 20
+	MaxTaskNoteLength     = 200
+	MaxTaskNotesRevisions = 10 // earlier revisions kept in NotesHistory, the oldest are dropped
+)
+
+// TaskNotes is one revision of a task's decision log: the assumptions the
+// estimate relies on ("excludes migration", "API already exists"), who wrote them and when
+type TaskNotes struct {
+	Items     []string
+	Author    string
+	UpdatedAt time.Time
+}
+
+// UpdateNotes replaces the decision log and keeps the previous revision in NotesHistory,
+// which holds the last MaxTaskNotesRevisions revisions.
+// Blank items are dropped; it reports false and records nothing when the items are unchanged.
+func (t *Task) UpdateNotes(items []string, author string) (bool, error) {
+	cleaned := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if utf8.RuneCountInString(item) > MaxTaskNoteLength {
+			return false, ErrTaskNoteTooLong
+		}
+		cleaned = append(cleaned, item)
+	}
+	if len(cleaned) > MaxTaskNotes {
+		return false, ErrTooManyTaskNotes
+	}
+
+	var current []string
+	if t.Notes != nil {
+		current = t.Notes.Items
+	}
+	if slices.Equal(current, cleaned) {
+		return false, nil
+	}
+
+	if t.Notes != nil {
+		t.NotesHistory = append(t.NotesHistory, *t.Notes)
+		if excess := len(t.NotesHistory) - MaxTaskNotesRevisions; excess > 0 {
+			t.NotesHistory = slices.Delete(t.NotesHistory, 0, excess)
+		}
+	}
+	t.Notes = &TaskNotes{
+		Items:     cleaned,
+		Author:    strings.TrimSpace(author),
+		UpdatedAt: time.Now().UTC(),
+	}
+	return true, nil
+}
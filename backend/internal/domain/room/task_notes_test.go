@@ -0,0 +1,79 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTask_UpdateNotes(t *testing.T) {
+	task, _ := NewTask("room1", "Checkout", 1)
+
+	changed, err := task.UpdateNotes([]string{" excludes migration ", "", "API already exists"}, "Alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Fatal("expected first notes to be recorded")
+	}
+	if len(task.Notes.Items) != 2 || task.Notes.Items[0] != "excludes migration" {
+		t.Errorf("expected trimmed notes without blanks, got %q", task.Notes.Items)
+	}
+	if len(task.NotesHistory) != 0 {
+		t.Errorf("expected no earlier revision, got %d", len(task.NotesHistory))
+	}
+
+	changed, _ = task.UpdateNotes([]string{"excludes migration", "API already exists"}, "Bob")
+	if changed {
+		t.Error("expected unchanged notes not to create a revision")
+	}
+
+	if _, err := task.UpdateNotes([]string{"excludes migration"}, "Bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Notes.Author != "Bob" {
+		t.Errorf("expected Bob as author, got %q", task.Notes.Author)
+	}
+	if len(task.NotesHistory) != 1 || task.NotesHistory[0].Author != "Alice" {
+		t.Errorf("expected Alice's revision in the history, got %+v", task.NotesHistory)
+	}
+}
+
+func TestTask_UpdateNotes_KeepsLatestRevisions(t *testing.T) {
+	task, _ := NewTask("room1", "Checkout", 1)
+
+	for i := 0; i <= MaxTaskNotesRevisions+1; i++ {
+		if _, err := task.UpdateNotes([]string{strings.Repeat("a", i+1)}, "Alice"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(task.NotesHistory) != MaxTaskNotesRevisions {
+		t.Fatalf("expected %d revisions, got %d", MaxTaskNotesRevisions, len(task.NotesHistory))
+	}
+	if oldest := task.NotesHistory[0].Items[0]; oldest != "aa" {
+		t.Errorf("expected the oldest revision to be dropped, got %q first", oldest)
+	}
+	if latest := task.NotesHistory[MaxTaskNotesRevisions-1].Items[0]; latest != strings.Repeat("a", MaxTaskNotesRevisions+1) {
+		t.Errorf("expected the previous revision last, got %q", latest)
+	}
+}
+
+func TestTask_UpdateNotes_Limits(t *testing.T) {
+	task, _ := NewTask("room1", "Checkout", 1)
+
+	if _, err := task.UpdateNotes([]string{strings.Repeat("a", MaxTaskNoteLength+1)}, "Alice"); !errors.Is(err, ErrTaskNoteTooLong) {
+		t.Errorf("expected ErrTaskNoteTooLong, got %v", err)
+	}
+
+	tooMany := make([]string, MaxTaskNotes+1)
+	for i := range tooMany {
+		tooMany[i] = "note"
+	}
+	if _, err := task.UpdateNotes(tooMany, "Alice"); !errors.Is(err, ErrTooManyTaskNotes) {
+		t.Errorf("expected ErrTooManyTaskNotes, got %v", err)
+	}
+	if task.Notes != nil {
+		t.Errorf("expected rejected notes not to be stored, got %+v", task.Notes)
+	}
+}
@@ -0,0 +1,106 @@
+package room
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ranks order a task among its siblings. They are compared as plain byte strings, so a new
+// rank can always be found between two neighbours and a move only rewrites the moved task.
+// Ranks never end in the lowest digit, otherwise nothing would fit right below them.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// InitialRank is the rank of a task created at position in a backlog nobody has moved yet
+func InitialRank(position int) string {
+	return fmt.Sprintf("%010di", position)
+}
+
+// RankBetween returns a rank sorting strictly after before and strictly before after.
+// An empty before means the start of the list, an empty after its end.
+func RankBetween(before, after string) (string, error) {
+	if !validRank(before) || !validRank(after) || (after != "" && before >= after) {
+		return "", ErrInvalidTaskRank
+	}
+	return midpoint(before, after), nil
+}
+
+// SpreadRanks returns count evenly spaced, increasing ranks for renumbering a whole sibling list
+func SpreadRanks(count int) []string {
+	width := 1
+	for span := len(rankDigits); span <= count; span *= len(rankDigits) {
+		width++
+	}
+	space := 1
+	for range width {
+		space *= len(rankDigits)
+	}
+
+	ranks := make([]string, count)
+	for i := range ranks {
+		ranks[i] = strings.TrimRight(formatRank((i+1)*space/(count+1), width), rankDigits[:1])
+	}
+	return ranks
+}
+
+func formatRank(value, width int) string {
+	rank := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		rank[i] = rankDigits[value%len(rankDigits)]
+		value /= len(rankDigits)
+	}
+	return string(rank)
+}
+
+func validRank(rank string) bool {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(rank, rankDigits[:1])
+}
+
+// midpoint finds the shortest rank between a and b, b empty meaning no upper bound
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, reading missing digits of a as the lowest digit
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	low := 0
+	if a != "" {
+		low = strings.IndexByte(rankDigits, a[0])
+	}
+	high := len(rankDigits)
+	if b != "" {
+		high = strings.IndexByte(rankDigits, b[0])
+	}
+	if high-low > 1 {
+		return rankDigits[(low+high+1)/2 : (low+high+1)/2+1]
+	}
+	// Neighbouring digits: b's first digit alone fits when b goes on, otherwise extend a
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return rankDigits[low:low+1] + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+func suffix(rank string, n int) string {
+	if n >= len(rank) {
+		return ""
+	}
+	return rank[n:]
+}
@@ -0,0 +1,77 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+	}{
+		{"Empty list", "", ""},
+		{"Start of list", "", "i"},
+		{"End of list", "i", ""},
+		{"Wide gap", "1", "z"},
+		{"Neighbouring digits", "h", "i"},
+		{"Below a long rank", "", "01"},
+		{"Shared prefix", "a1", "a2"},
+		{"Initial ranks", InitialRank(3), InitialRank(4)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, err := RankBetween(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if rank <= tt.before || (tt.after != "" && rank >= tt.after) {
+				t.Errorf("expected a rank between %q and %q, got %q", tt.before, tt.after, rank)
+			}
+			if !validRank(rank) {
+				t.Errorf("expected a valid rank, got %q", rank)
+			}
+		})
+	}
+}
+
+func TestRankBetween_Rejects(t *testing.T) {
+	for _, bounds := range [][2]string{{"b", "a"}, {"a", "a"}, {"A", ""}, {"", "a0"}} {
+		if _, err := RankBetween(bounds[0], bounds[1]); !errors.Is(err, ErrInvalidTaskRank) {
+			t.Errorf("expected ErrInvalidTaskRank for %q..%q, got %v", bounds[0], bounds[1], err)
+		}
+	}
+}
+
+func TestRankBetween_RepeatedInsertsStayOrdered(t *testing.T) {
+	// Keep inserting right after the first rank, the worst case for rank length
+	low, high := "i", "j"
+	for i := 0; i < 200; i++ {
+		rank, err := RankBetween(low, high)
+		if err != nil {
+			t.Fatalf("insert %d: expected no error, got %v", i, err)
+		}
+		if rank <= low || rank >= high {
+			t.Fatalf("insert %d: expected a rank between %q and %q, got %q", i, low, high, rank)
+		}
+		high = rank
+	}
+}
+
+func TestSpreadRanks(t *testing.T) {
+	for _, count := range []int{1, 2, 35, 36, 100, 2000} {
+		ranks := SpreadRanks(count)
+		if len(ranks) != count {
+			t.Fatalf("expected %d ranks, got %d", count, len(ranks))
+		}
+		for i, rank := range ranks {
+			if !validRank(rank) || rank == "" {
+				t.Fatalf("expected valid ranks for %d tasks, got %q", count, rank)
+			}
+			if i > 0 && ranks[i-1] >= rank {
+				t.Fatalf("expected increasing ranks for %d tasks, got %q then %q", count, ranks[i-1], rank)
+			}
+		}
+	}
+}
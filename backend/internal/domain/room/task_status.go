@@ -0,0 +1,46 @@
+package room
+
+// TaskStatus tracks where a task is in the estimation lifecycle
+type TaskStatus string
+
+const (
+	TaskStatusPending      TaskStatus = "pending"
+	TaskStatusInDiscussion TaskStatus = "in_discussion"
+	TaskStatusEstimated    TaskStatus = "estimated"
+	TaskStatusSkipped      TaskStatus = "skipped"
+	TaskStatusDeferred     TaskStatus = "deferred"
+)
+
+// Allowed status changes; staying in the same status is always allowed
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending:      {TaskStatusInDiscussion, TaskStatusEstimated, TaskStatusSkipped, TaskStatusDeferred},
+	TaskStatusInDiscussion: {TaskStatusPending, TaskStatusEstimated, TaskStatusSkipped, TaskStatusDeferred},
+	TaskStatusEstimated:    {TaskStatusPending},
+	TaskStatusSkipped:      {TaskStatusPending, TaskStatusEstimated},
+	TaskStatusDeferred:     {TaskStatusPending, TaskStatusEstimated},
+}
+
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	status := TaskStatus(s)
+	if _, ok := taskTransitions[status]; !ok {
+		return "", ErrInvalidTaskStatus
+	}
+	return status, nil
+}
+
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range taskTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether a task in this status still waits in the estimation queue
+func (s TaskStatus) IsOpen() bool {
+	return s == TaskStatusPending || s == TaskStatusInDiscussion
+}
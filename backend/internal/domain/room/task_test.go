@@ -65,3 +65,157 @@ func TestTaskSetEstimation(t *testing.T) {
 		t.Errorf("Expected no error for any estimation value, got: %v", err)
 	}
 }
+
+func TestNewTask_StartsPending(t *testing.T) {
+	task, _ := NewTask("room123", "Test task", 1)
+
+	if task.Status != TaskStatusPending {
+		t.Errorf("expected status %s, got %s", TaskStatusPending, task.Status)
+	}
+	if !task.IsOpen() {
+		t.Error("expected new task to be open")
+	}
+}
+
+func TestTaskSetEstimation_UpdatesStatus(t *testing.T) {
+	task, _ := NewTask("room123", "Test task", 1)
+
+	if err := task.SetEstimation("?", DbsFibo); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.IsEstimated() {
+		t.Error("expected '?' not to mark the task estimated")
+	}
+
+	if err := task.SetEstimation("5", DbsFibo); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.Status != TaskStatusEstimated {
+		t.Errorf("expected status %s, got %s", TaskStatusEstimated, task.Status)
+	}
+}
+
+func TestTaskStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     TaskStatus
+		action   func(*Task) error
+		expected TaskStatus
+		wantErr  bool
+	}{
+		{"Skip pending", TaskStatusPending, (*Task).Skip, TaskStatusSkipped, false},
+		{"Defer in discussion", TaskStatusInDiscussion, (*Task).Defer, TaskStatusDeferred, false},
+		{"Reopen skipped", TaskStatusSkipped, (*Task).Reopen, TaskStatusPending, false},
+		{"Reopen estimated", TaskStatusEstimated, (*Task).Reopen, TaskStatusPending, false},
+		{"Discuss pending", TaskStatusPending, (*Task).StartDiscussion, TaskStatusInDiscussion, false},
+		{"Discuss deferred", TaskStatusDeferred, (*Task).StartDiscussion, TaskStatusDeferred, true},
+		{"Skip estimated", TaskStatusEstimated, (*Task).Skip, TaskStatusEstimated, true},
+		{"Defer estimated", TaskStatusEstimated, (*Task).Defer, TaskStatusEstimated, true},
+		{"Stop discussion", TaskStatusInDiscussion, (*Task).StopDiscussion, TaskStatusPending, false},
+		{"Stop discussion keeps skipped", TaskStatusSkipped, (*Task).StopDiscussion, TaskStatusSkipped, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, _ := NewTask("room123", "Test task", 1)
+			task.Status = tt.from
+
+			err := tt.action(task)
+			if tt.wantErr && err != ErrInvalidTaskTransition {
+				t.Errorf("expected ErrInvalidTaskTransition, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if task.Status != tt.expected {
+				t.Errorf("expected status %s, got %s", tt.expected, task.Status)
+			}
+		})
+	}
+}
+
+func TestParseTaskStatus(t *testing.T) {
+	if status, err := ParseTaskStatus("deferred"); err != nil || status != TaskStatusDeferred {
+		t.Errorf("expected deferred, got %s (%v)", status, err)
+	}
+	if _, err := ParseTaskStatus("done"); err != ErrInvalidTaskStatus {
+		t.Errorf("expected ErrInvalidTaskStatus, got %v", err)
+	}
+}
+
+func TestTaskAcceptEstimation(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		suggested      string
+		reason         string
+		wantErr        error
+		wantOverridden bool
+		wantReason     string
+	}{
+		{"Accept suggestion", "5", "5", "ignored", nil, false, ""},
+		{"Override with reason", "8", "5", "integration risk", nil, true, "integration risk"},
+		{"No suggestion", "3", "", "", nil, false, ""},
+		{"Not a card", "4", "5", "", ErrInvalidEstimation, false, ""},
+		{"Question mark", "?", "5", "", ErrInvalidEstimation, false, ""},
+		{"Reason too long", "8", "5", strings.Repeat("a", MaxOverrideReasonLength+1), ErrOverrideReasonTooLong, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, _ := NewTask("room123", "Test task", 1)
+
+			err := task.AcceptEstimation(tt.value, tt.suggested, tt.reason, DbsFibo)
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				if task.Status != TaskStatusPending {
+					t.Errorf("expected status to stay pending, got %s", task.Status)
+				}
+				return
+			}
+			if task.Estimation != tt.value || task.Status != TaskStatusEstimated {
+				t.Errorf("expected estimated with %s, got %s (%s)", tt.value, task.Estimation, task.Status)
+			}
+			if task.EstimationOverridden != tt.wantOverridden {
+				t.Errorf("expected overridden %v, got %v", tt.wantOverridden, task.EstimationOverridden)
+			}
+			if task.OverrideReason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, task.OverrideReason)
+			}
+		})
+	}
+}
+
+func TestTaskReestimate_KeepsPreviousEstimation(t *testing.T) {
+	task, _ := NewTask("room123", "Test task", 1)
+	_ = task.AcceptEstimation("5", "5", "", DbsFibo)
+
+	if err := task.Reestimate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.Status != TaskStatusPending || task.Estimation != "5" {
+		t.Errorf("expected pending task keeping estimation 5, got %s (%s)", task.Status, task.Estimation)
+	}
+
+	if err := task.AcceptEstimation("13", "8", "scope grew", DbsFibo); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.PreviousEstimation != "5" || task.Estimation != "13" {
+		t.Errorf("expected 5 -> 13, got %s -> %s", task.PreviousEstimation, task.Estimation)
+	}
+
+	record := NewEstimationRecord(task, []string{"Alice", "Bob"})
+	if record.Source != EstimationSourceOverride || record.Value != "13" || record.Reason != "scope grew" {
+		t.Errorf("unexpected history record %+v", record)
+	}
+}
+
+func TestTaskReestimate_RequiresEstimatedTask(t *testing.T) {
+	task, _ := NewTask("room123", "Test task", 1)
+
+	if err := task.Reestimate(); err != ErrTaskNotEstimated {
+		t.Errorf("expected ErrTaskNotEstimated, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package room
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TaskTree links a room's tasks to their parents so epics can be walked, validated and rolled up.
+// Tasks whose parent is missing from the room are treated as top-level.
+type TaskTree struct {
+	byID     map[string]*Task
+	children map[string][]*Task // parent ID -> subtasks in position order, "" holds the top level
+}
+
+// EstimateRollup is an epic's estimate summed from the leaf subtasks below it
+type EstimateRollup struct {
+	Points    float64
+	Estimated int // leaf subtasks carrying a numeric estimate
+	Subtasks  int // all leaf subtasks, estimated or not
+}
+
+func (r EstimateRollup) String() string {
+	return strconv.FormatFloat(r.Points, 'f', -1, 64)
+}
+
+func NewTaskTree(tasks []*Task) *TaskTree {
+	tree := &TaskTree{
+		byID:     make(map[string]*Task, len(tasks)),
+		children: make(map[string][]*Task),
+	}
+	for _, task := range tasks {
+		tree.byID[task.ID] = task
+	}
+	for _, task := range tasks {
+		tree.children[tree.parentOf(task)] = append(tree.children[tree.parentOf(task)], task)
+	}
+	for _, siblings := range tree.children {
+		sortByPosition(siblings)
+	}
+	return tree
+}
+
+func (tr *TaskTree) Find(taskID string) (*Task, error) {
+	task, ok := tr.byID[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// Subtasks returns the direct children of a task in position order
+func (tr *TaskTree) Subtasks(taskID string) []*Task {
+	if taskID == "" {
+		return nil
+	}
+	return tr.children[taskID]
+}
+
+// Parent returns the ID of the task's epic, empty for top-level tasks
+func (tr *TaskTree) Parent(taskID string) string {
+	task, ok := tr.byID[taskID]
+	if !ok {
+		return ""
+	}
+	return tr.parentOf(task)
+}
+
+// TopLevel returns the tasks without a parent in position order
+func (tr *TaskTree) TopLevel() []*Task {
+	return tr.children[""]
+}
+
+// IsEpic reports whether the task has subtasks; its estimate then comes from them
+func (tr *TaskTree) IsEpic(taskID string) bool {
+	return len(tr.Subtasks(taskID)) > 0
+}
+
+// SetParent moves a task under parentID, or to the top level when parentID is empty.
+// The parent must belong to the same room and must not be the task itself or one of its subtasks.
+// The task lands after its new siblings.
+func (tr *TaskTree) SetParent(taskID, parentID string) error {
+	task, err := tr.Find(taskID)
+	if err != nil {
+		return err
+	}
+	if parentID != "" {
+		if _, ok := tr.byID[parentID]; !ok {
+			return ErrParentTaskNotFound
+		}
+		for id := parentID; id != ""; id = tr.parentOf(tr.byID[id]) {
+			if id == taskID {
+				return ErrTaskHierarchyCycle
+			}
+		}
+	}
+
+	// The task joins the end of its new siblings
+	siblings := removeTask(slices.Clone(tr.children[parentID]), taskID)
+	last := ""
+	if len(siblings) > 0 {
+		last = siblings[len(siblings)-1].Rank
+	}
+	rank, err := RankBetween(last, "")
+	if err != nil {
+		return err
+	}
+
+	old := tr.parentOf(task)
+	tr.children[old] = removeTask(tr.children[old], taskID)
+	tr.children[parentID] = append(siblings, task)
+	task.ParentID = parentID
+	task.Rank = rank
+	return nil
+}
+
+// MoveBefore places a task right before anchorID, under the same parent as the anchor.
+// Only the moved task gets a new rank, its subtasks move along with it.
+func (tr *TaskTree) MoveBefore(taskID, anchorID string) error {
+	return tr.move(taskID, anchorID, 0)
+}
+
+// MoveAfter places a task right after anchorID, under the same parent as the anchor
+func (tr *TaskTree) MoveAfter(taskID, anchorID string) error {
+	return tr.move(taskID, anchorID, 1)
+}
+
+func (tr *TaskTree) move(taskID, anchorID string, offset int) error {
+	task, err := tr.Find(taskID)
+	if err != nil {
+		return err
+	}
+	if anchorID == "" || anchorID == taskID {
+		return ErrInvalidTaskMove
+	}
+	anchor, err := tr.Find(anchorID)
+	if err != nil {
+		return err
+	}
+	parentID := tr.parentOf(anchor)
+	for id := parentID; id != ""; id = tr.parentOf(tr.byID[id]) {
+		if id == taskID {
+			return ErrTaskHierarchyCycle
+		}
+	}
+
+	siblings := removeTask(slices.Clone(tr.children[parentID]), taskID)
+	at := slices.Index(siblings, anchor) + offset
+	before, after := "", ""
+	if at > 0 {
+		before = siblings[at-1].Rank
+	}
+	if at < len(siblings) {
+		after = siblings[at].Rank
+	}
+	rank, err := RankBetween(before, after)
+	if err != nil {
+		return err
+	}
+
+	old := tr.parentOf(task)
+	tr.children[old] = removeTask(tr.children[old], taskID)
+	tr.children[parentID] = slices.Insert(siblings, at, task)
+	task.ParentID = parentID
+	task.Rank = rank
+	return nil
+}
+
+// Insert adds a task that is not in the tree yet, such as a restored one, under its parent
+// at its own rank. A sibling holding that rank keeps it and the task goes right after it;
+// a parent missing from the tree puts the task at the top level.
+func (tr *TaskTree) Insert(task *Task) error {
+	if _, ok := tr.byID[task.ID]; ok {
+		return ErrInvalidTaskMove
+	}
+	if !validRank(task.Rank) || task.Rank == "" {
+		return ErrInvalidTaskRank
+	}
+
+	parentID := tr.parentOf(task)
+	siblings := tr.children[parentID]
+	at, taken := slices.BinarySearchFunc(siblings, task.Rank, func(sibling *Task, rank string) int {
+		return strings.Compare(sibling.Rank, rank)
+	})
+	if taken {
+		after := ""
+		if at+1 < len(siblings) {
+			after = siblings[at+1].Rank
+		}
+		rank, err := RankBetween(siblings[at].Rank, after)
+		if err != nil {
+			return err
+		}
+		task.Rank = rank
+		at++
+	}
+
+	tr.byID[task.ID] = task
+	tr.children[parentID] = slices.Insert(siblings, at, task)
+	task.ParentID = parentID
+	return nil
+}
+
+// Rerank gives every sibling list fresh, evenly spaced ranks in its current order.
+// It rewrites every task, so it is kept for whole-backlog reorders.
+func (tr *TaskTree) Rerank() {
+	for _, siblings := range tr.children {
+		for i, rank := range SpreadRanks(len(siblings)) {
+			siblings[i].Rank = rank
+		}
+	}
+}
+
+// Number sets every task's position to its place in Ordered and returns that order
+func (tr *TaskTree) Number() []*Task {
+	ordered := tr.Ordered()
+	for i, task := range ordered {
+		task.Position = i + 1
+	}
+	return ordered
+}
+
+// Ordered lists every task depth-first, each parent directly followed by its subtasks.
+// Siblings keep their relative position, so moving an epic moves its whole subtree.
+func (tr *TaskTree) Ordered() []*Task {
+	ordered := make([]*Task, 0, len(tr.byID))
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, task := range tr.children[parentID] {
+			ordered = append(ordered, task)
+			walk(task.ID)
+		}
+	}
+	walk("")
+	return ordered
+}
+
+// Rollup sums the numeric estimates of the leaf subtasks below an epic.
+// Estimates that are not numbers (e.g. "?") count as unestimated.
+func (tr *TaskTree) Rollup(taskID string) (EstimateRollup, bool) {
+	if !tr.IsEpic(taskID) {
+		return EstimateRollup{}, false
+	}
+
+	var rollup EstimateRollup
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, task := range tr.children[parentID] {
+			if tr.IsEpic(task.ID) {
+				walk(task.ID)
+				continue
+			}
+			rollup.Subtasks++
+			if points, err := strconv.ParseFloat(task.Estimation, 64); err == nil {
+				rollup.Points += points
+				rollup.Estimated++
+			}
+		}
+	}
+	walk(taskID)
+	return rollup, true
+}
+
+func (tr *TaskTree) parentOf(task *Task) string {
+	if _, ok := tr.byID[task.ParentID]; !ok {
+		return ""
+	}
+	return task.ParentID
+}
+
+func sortByPosition(tasks []*Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Position < tasks[j].Position
+	})
+}
+
+func removeTask(tasks []*Task, taskID string) []*Task {
+	for i, task := range tasks {
+		if task.ID == taskID {
+			return append(tasks[:i], tasks[i+1:]...)
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,158 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestTree(count int) (*TaskTree, []*Task) {
+	tasks := make([]*Task, count)
+	for i := range tasks {
+		tasks[i], _ = NewTask("room123", "Task", i+1)
+	}
+	return NewTaskTree(tasks), tasks
+}
+
+func TestTaskTreeSetParent_PreventsCycles(t *testing.T) {
+	tree, tasks := newTestTree(3)
+
+	if err := tree.SetParent(tasks[1].ID, tasks[0].ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := tree.SetParent(tasks[2].ID, tasks[1].ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := tree.SetParent(tasks[0].ID, tasks[2].ID); !errors.Is(err, ErrTaskHierarchyCycle) {
+		t.Errorf("expected ErrTaskHierarchyCycle for a grandchild parent, got %v", err)
+	}
+	if err := tree.SetParent(tasks[0].ID, tasks[0].ID); !errors.Is(err, ErrTaskHierarchyCycle) {
+		t.Errorf("expected ErrTaskHierarchyCycle for itself, got %v", err)
+	}
+	if err := tree.SetParent(tasks[0].ID, "missing"); !errors.Is(err, ErrParentTaskNotFound) {
+		t.Errorf("expected ErrParentTaskNotFound, got %v", err)
+	}
+	if tasks[0].ParentID != "" {
+		t.Errorf("expected rejected moves to leave the task alone, got parent %s", tasks[0].ParentID)
+	}
+}
+
+func TestTaskTreeOrdered_KeepsSubtasksAfterEpic(t *testing.T) {
+	tree, tasks := newTestTree(4)
+	// Task 4 becomes a subtask of task 1 and must follow it
+	_ = tree.SetParent(tasks[3].ID, tasks[0].ID)
+
+	ordered := tree.Ordered()
+	want := []string{tasks[0].ID, tasks[3].ID, tasks[1].ID, tasks[2].ID}
+	for i, id := range want {
+		if ordered[i].ID != id {
+			t.Fatalf("expected task %d at index %d, got %+v", i, i, ordered)
+		}
+	}
+}
+
+func TestTaskTreeRollup(t *testing.T) {
+	tree, tasks := newTestTree(5)
+	epic, feature := tasks[0], tasks[1]
+	_ = tree.SetParent(feature.ID, epic.ID)
+	_ = tree.SetParent(tasks[2].ID, feature.ID)
+	_ = tree.SetParent(tasks[3].ID, feature.ID)
+	_ = tree.SetParent(tasks[4].ID, epic.ID)
+	// An estimate left on an epic itself is ignored in favour of its subtasks
+	feature.Estimation = "40"
+	tasks[2].Estimation = "3"
+	tasks[3].Estimation = "0.5"
+	tasks[4].Estimation = "?"
+
+	rollup, ok := tree.Rollup(epic.ID)
+	if !ok {
+		t.Fatal("expected a roll-up for the epic")
+	}
+	if rollup.String() != "3.5" || rollup.Estimated != 2 || rollup.Subtasks != 3 {
+		t.Errorf("expected 3.5 from 2 of 3 subtasks, got %s from %d of %d", rollup, rollup.Estimated, rollup.Subtasks)
+	}
+
+	if _, ok := tree.Rollup(tasks[2].ID); ok {
+		t.Error("expected no roll-up for a task without subtasks")
+	}
+}
+
+func TestTaskTreeMove(t *testing.T) {
+	tree, tasks := newTestTree(4)
+	_ = tree.SetParent(tasks[3].ID, tasks[0].ID)
+	untouched := tasks[1].Rank
+
+	// Moving task 3 in front of task 2 only re-ranks task 3
+	if err := tree.MoveBefore(tasks[2].ID, tasks[1].ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tasks[1].Rank != untouched {
+		t.Errorf("expected the anchor to keep its rank, got %q", tasks[1].Rank)
+	}
+
+	// Moving task 3 after the subtask files it under the epic
+	if err := tree.MoveAfter(tasks[2].ID, tasks[3].ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tasks[2].ParentID != tasks[0].ID {
+		t.Errorf("expected the task to join the anchor's epic, got parent %q", tasks[2].ParentID)
+	}
+
+	ordered := tree.Number()
+	want := []string{tasks[0].ID, tasks[3].ID, tasks[2].ID, tasks[1].ID}
+	for i, id := range want {
+		if ordered[i].ID != id || ordered[i].Position != i+1 {
+			t.Fatalf("expected task %s at position %d, got %+v", id, i+1, ordered[i])
+		}
+	}
+
+	// Ranks alone must reproduce the order, as the database sorts by them
+	for _, siblings := range [][]*Task{tree.TopLevel(), tree.Subtasks(tasks[0].ID)} {
+		for i := 1; i < len(siblings); i++ {
+			if siblings[i-1].Rank >= siblings[i].Rank {
+				t.Errorf("expected increasing sibling ranks, got %q then %q", siblings[i-1].Rank, siblings[i].Rank)
+			}
+		}
+	}
+
+	if err := tree.MoveAfter(tasks[0].ID, tasks[3].ID); !errors.Is(err, ErrTaskHierarchyCycle) {
+		t.Errorf("expected ErrTaskHierarchyCycle moving an epic among its subtasks, got %v", err)
+	}
+	if err := tree.MoveBefore(tasks[1].ID, tasks[1].ID); !errors.Is(err, ErrInvalidTaskMove) {
+		t.Errorf("expected ErrInvalidTaskMove moving a task next to itself, got %v", err)
+	}
+	if err := tree.MoveBefore(tasks[1].ID, "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for a missing anchor, got %v", err)
+	}
+}
+
+func TestTaskTreeInsert(t *testing.T) {
+	_, tasks := newTestTree(3)
+	restored, taken := tasks[1], tasks[2]
+	tree := NewTaskTree([]*Task{tasks[0], taken})
+
+	// Back at its own rank, between its old neighbours
+	if err := tree.Insert(restored); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ordered := tree.Number(); ordered[1].ID != restored.ID {
+		t.Errorf("expected the task back at position 2, got %s", ordered[1].ID)
+	}
+	if err := tree.Insert(restored); !errors.Is(err, ErrInvalidTaskMove) {
+		t.Errorf("expected ErrInvalidTaskMove inserting a task twice, got %v", err)
+	}
+
+	// A sibling took the rank in the meantime: the task goes right after it
+	late, _ := NewTask("room123", "Late", 1)
+	late.Rank = taken.Rank
+	late.ParentID = "deleted-epic"
+	if err := tree.Insert(late); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if late.ParentID != "" || late.Rank <= taken.Rank {
+		t.Errorf("expected the task at the top level after %q, got parent %q rank %q", taken.Rank, late.ParentID, late.Rank)
+	}
+	if ordered := tree.Number(); ordered[len(ordered)-1].ID != late.ID {
+		t.Errorf("expected the task last, got %s", ordered[len(ordered)-1].ID)
+	}
+}
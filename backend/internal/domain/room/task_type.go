@@ -0,0 +1,78 @@
+package room
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// TaskType tells stories, bugs, spikes and chores apart in a mixed backlog
+type TaskType string
+
+const (
+	TaskTypeStory TaskType = "story"
+	TaskTypeBug   TaskType = "bug"
+	TaskTypeSpike TaskType = "spike"
+	TaskTypeChore TaskType = "chore"
+)
+
+const (
+	MaxTaskLabels      = 10
+	MaxTaskLabelLength = 30
+)
+
+// ParseTaskType defaults an empty type to a story
+func ParseTaskType(s string) (TaskType, error) {
+	switch t := TaskType(strings.ToLower(strings.TrimSpace(s))); t {
+	case "":
+		return TaskTypeStory, nil
+	case TaskTypeStory, TaskTypeBug, TaskTypeSpike, TaskTypeChore:
+		return t, nil
+	default:
+		return "", ErrUnknownTaskType
+	}
+}
+
+// NormalizeLabels trims and lower-cases labels, dropping blanks and duplicates
+func NormalizeLabels(labels []string) ([]string, error) {
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || slices.Contains(normalized, label) {
+			continue
+		}
+		if utf8.RuneCountInString(label) > MaxTaskLabelLength {
+			return nil, ErrTaskLabelTooLong
+		}
+		normalized = append(normalized, label)
+	}
+	if len(normalized) > MaxTaskLabels {
+		return nil, ErrTooManyTaskLabels
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+func (t *Task) SetType(taskType string) error {
+	parsed, err := ParseTaskType(taskType)
+	if err != nil {
+		return err
+	}
+	t.Type = parsed
+	return nil
+}
+
+func (t *Task) SetLabels(labels []string) error {
+	normalized, err := NormalizeLabels(labels)
+	if err != nil {
+		return err
+	}
+	t.Labels = normalized
+	return nil
+}
+
+func (t *Task) HasLabel(label string) bool {
+	return slices.Contains(t.Labels, label)
+}
@@ -0,0 +1,106 @@
+package room
+
+import (
+	"math"
+	"strconv"
+)
+
+// EstimationMode decides what a single vote looks like in a room
+type EstimationMode string
+
+const (
+	ModeStandard   EstimationMode = "standard"
+	ModeThreePoint EstimationMode = "three_point" // optimistic / most likely / pessimistic, combined with PERT
+)
+
+func ParseEstimationMode(value string) (EstimationMode, error) {
+	switch mode := EstimationMode(value); mode {
+	case "":
+		return ModeStandard, nil
+	case ModeStandard, ModeThreePoint:
+		return mode, nil
+	default:
+		return "", ErrUnknownEstimationMode
+	}
+}
+
+// ThreePointEstimate is one participant's optimistic / most likely / pessimistic vote
+type ThreePointEstimate struct {
+	Optimistic  float64
+	MostLikely  float64
+	Pessimistic float64
+}
+
+// NewThreePointEstimate parses three numeric cards of the deck; they must not decrease
+func NewThreePointEstimate(optimistic, mostLikely, pessimistic string, votingSystem VotingSystem) (ThreePointEstimate, error) {
+	values := make([]float64, 3)
+	for i, card := range []string{optimistic, mostLikely, pessimistic} {
+		vote, err := CreateVote(card, votingSystem)
+		if err != nil {
+			return ThreePointEstimate{}, err
+		}
+		if !vote.IsNumeric() {
+			return ThreePointEstimate{}, ErrInvalidThreePointVote
+		}
+		if values[i], err = vote.ToFloat(); err != nil {
+			return ThreePointEstimate{}, err
+		}
+	}
+
+	if values[0] > values[1] || values[1] > values[2] {
+		return ThreePointEstimate{}, ErrInvalidThreePointVote
+	}
+
+	return ThreePointEstimate{Optimistic: values[0], MostLikely: values[1], Pessimistic: values[2]}, nil
+}
+
+// Expected is the PERT weighted mean (O + 4M + P) / 6
+func (e ThreePointEstimate) Expected() float64 {
+	return (e.Optimistic + 4*e.MostLikely + e.Pessimistic) / 6
+}
+
+// StdDev is the PERT standard deviation (P - O) / 6
+func (e ThreePointEstimate) StdDev() float64 {
+	return (e.Pessimistic - e.Optimistic) / 6
+}
+
+// Card is the deck value closest to the expected value
+func (e ThreePointEstimate) Card() string {
+	return strconv.FormatFloat(RoundToClosestDbsFiboVote(e.Expected()), 'f', -1, 64)
+}
+
+// PertEstimate is the team's combined three-point estimate
+type PertEstimate struct {
+	ThreePointEstimate // participants' mean optimistic / most likely / pessimistic values
+	Expected           float64
+	StdDev             float64
+}
+
+// CalculatePert averages the participants' triples and applies PERT to the result
+func (s *EstimationService) CalculatePert(votes map[string]ThreePointEstimate) (*PertEstimate, error) {
+	if len(votes) == 0 {
+		return nil, ErrNoVotes
+	}
+
+	var mean ThreePointEstimate
+	for _, vote := range votes {
+		mean.Optimistic += vote.Optimistic
+		mean.MostLikely += vote.MostLikely
+		mean.Pessimistic += vote.Pessimistic
+	}
+	count := float64(len(votes))
+	mean.Optimistic /= count
+	mean.MostLikely /= count
+	mean.Pessimistic /= count
+
+	return &PertEstimate{
+		ThreePointEstimate: mean,
+		Expected:           roundTo(mean.Expected(), 2),
+		StdDev:             roundTo(mean.StdDev(), 2),
+	}, nil
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,100 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewThreePointEstimate(t *testing.T) {
+	estimate, err := NewThreePointEstimate("2", "3", "8", DbsFibo)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if estimate.Optimistic != 2 || estimate.MostLikely != 3 || estimate.Pessimistic != 8 {
+		t.Errorf("expected 2/3/8, got %+v", estimate)
+	}
+
+	tests := []struct {
+		name                                string
+		optimistic, mostLikely, pessimistic string
+		expectedErr                         error
+	}{
+		{"decreasing values", "5", "3", "8", ErrInvalidThreePointVote},
+		{"question mark", "?", "3", "8", ErrInvalidThreePointVote},
+		{"not a card", "2", "4", "8", ErrInvalidVote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewThreePointEstimate(tt.optimistic, tt.mostLikely, tt.pessimistic, DbsFibo)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestThreePointEstimate_Pert(t *testing.T) {
+	estimate := ThreePointEstimate{Optimistic: 2, MostLikely: 3, Pessimistic: 8}
+
+	// (2 + 12 + 8) / 6
+	if got := estimate.Expected(); got < 3.66 || got > 3.67 {
+		t.Errorf("expected 3.67, got %v", got)
+	}
+	if got := estimate.StdDev(); got != 1 {
+		t.Errorf("expected standard deviation 1, got %v", got)
+	}
+	if got := estimate.Card(); got != "3" {
+		t.Errorf("expected card 3, got %q", got)
+	}
+}
+
+func TestEstimationService_CalculatePert(t *testing.T) {
+	service := NewEstimationService()
+
+	if _, err := service.CalculatePert(nil); !errors.Is(err, ErrNoVotes) {
+		t.Errorf("expected ErrNoVotes, got %v", err)
+	}
+
+	result, err := service.CalculatePert(map[string]ThreePointEstimate{
+		"user1": {Optimistic: 1, MostLikely: 2, Pessimistic: 5},
+		"user2": {Optimistic: 3, MostLikely: 5, Pessimistic: 13},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// mean triple 2 / 3.5 / 9 -> (2 + 14 + 9) / 6 = 4.17, (9 - 2) / 6 = 1.17
+	if result.Optimistic != 2 || result.MostLikely != 3.5 || result.Pessimistic != 9 {
+		t.Errorf("expected mean triple 2/3.5/9, got %+v", result.ThreePointEstimate)
+	}
+	if result.Expected != 4.17 {
+		t.Errorf("expected value 4.17, got %v", result.Expected)
+	}
+	if result.StdDev != 1.17 {
+		t.Errorf("expected standard deviation 1.17, got %v", result.StdDev)
+	}
+}
+
+func TestNewRoom_EstimationMode(t *testing.T) {
+	rm, err := NewRoom("Room", RoomSettings{VotingSystem: DbsFibo})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rm.Mode != ModeStandard {
+		t.Errorf("expected default mode %q, got %q", ModeStandard, rm.Mode)
+	}
+
+	_, err = NewRoom("Room", RoomSettings{
+		VotingSystem: DbsFibo,
+		Mode:         ModeThreePoint,
+		Dimensions:   []EstimationDimension{{Key: "effort"}},
+		Combination:  CombineSum,
+	})
+	if !errors.Is(err, ErrIncompatibleModes) {
+		t.Errorf("expected ErrIncompatibleModes, got %v", err)
+	}
+
+	if _, err := NewRoom("Room", RoomSettings{VotingSystem: DbsFibo, Mode: "range"}); !errors.Is(err, ErrUnknownEstimationMode) {
+		t.Errorf("expected ErrUnknownEstimationMode, got %v", err)
+	}
+}
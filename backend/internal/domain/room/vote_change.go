@@ -0,0 +1,29 @@
+package room
+
+// VoteChangePolicy decides what happens to votes cast after the reveal
+type VoteChangePolicy string
+
+const (
+	VoteChangesTracked VoteChangePolicy = "tracked" // changes are allowed and shown as "changed from X to Y"
+	VoteChangesLocked  VoteChangePolicy = "locked"  // no vote is accepted once the votes are revealed
+)
+
+func ParseVoteChangePolicy(value string) (VoteChangePolicy, error) {
+	switch policy := VoteChangePolicy(value); policy {
+	case "":
+		return VoteChangesTracked, nil
+	case VoteChangesTracked, VoteChangesLocked:
+		return policy, nil
+	default:
+		return "", ErrUnknownVoteChangePolicy
+	}
+}
+
+// VoteChange is a vote changed after the reveal. Voter is the user ID in the live
+// state and the user's name in the estimation history, empty for anonymous rounds.
+// From is empty when the user had not voted before the reveal.
+type VoteChange struct {
+	Voter string // user ID; empty in anonymous rounds
+	From  string
+	To    string
+}
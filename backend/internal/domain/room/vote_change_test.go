@@ -0,0 +1,28 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewRoom_VoteChangePolicy(t *testing.T) {
+	rm, err := NewRoom("Room", RoomSettings{VotingSystem: DbsFibo})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rm.VoteChangePolicy != VoteChangesTracked || rm.VotesLockedAfterReveal() {
+		t.Errorf("expected default policy %q, got %q", VoteChangesTracked, rm.VoteChangePolicy)
+	}
+
+	rm, err = NewRoom("Room", RoomSettings{VotingSystem: DbsFibo, VoteChangePolicy: VoteChangesLocked})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !rm.VotesLockedAfterReveal() {
+		t.Error("expected votes to be locked after reveal")
+	}
+
+	if _, err := NewRoom("Room", RoomSettings{VotingSystem: DbsFibo, VoteChangePolicy: "silent"}); !errors.Is(err, ErrUnknownVoteChangePolicy) {
+		t.Errorf("expected ErrUnknownVoteChangePolicy, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package room
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const MaxVoteCommentLength = 280
+
+// VoteComment is a participant's rationale kept in the estimation history.
+// Author is empty when the round was anonymous.
+type VoteComment struct {
+	Author  string
+	Value   string
+	Comment string
+}
+
+// SanitizeVoteComment collapses whitespace, drops control and invisible format
+// characters and enforces the length limit; an empty result means no comment
+func SanitizeVoteComment(comment string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r), r == utf8.RuneError:
+			return -1
+		default:
+			return r
+		}
+	}, comment)
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if utf8.RuneCountInString(cleaned) > MaxVoteCommentLength {
+		return "", ErrVoteCommentTooLong
+	}
+	return cleaned, nil
+}
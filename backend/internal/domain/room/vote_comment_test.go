@@ -0,0 +1,35 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeVoteComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		comment     string
+		expected    string
+		expectedErr error
+	}{
+		{"empty", "   ", "", nil},
+		{"trimmed", "  legacy API  ", "legacy API", nil},
+		{"whitespace collapsed", "needs\n\tmigration", "needs migration", nil},
+		{"control characters dropped", "risky\x00\x07 part\u200b", "risky part", nil},
+		{"at limit", strings.Repeat("é", MaxVoteCommentLength), strings.Repeat("é", MaxVoteCommentLength), nil},
+		{"too long", strings.Repeat("a", MaxVoteCommentLength+1), "", ErrVoteCommentTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeVoteComment(tt.comment)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+// CheckFunc reports whether a single dependency is healthy
+type CheckFunc func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+type CheckResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type HealthResp struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type HealthHandler struct {
+	liveness  []namedCheck
+	readiness []namedCheck
+	timeout   time.Duration
+}
+
+func NewHealthHandler(timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		timeout: timeout,
+	}
+}
+
+// AddLivenessCheck registers a check that should only fail when the process must be restarted
+func (h *HealthHandler) AddLivenessCheck(name string, check CheckFunc) {
+	h.liveness = append(h.liveness, namedCheck{name: name, check: check})
+}
+
+// AddReadinessCheck registers a check that should fail while the pod cannot serve traffic
+func (h *HealthHandler) AddReadinessCheck(name string, check CheckFunc) {
+	h.readiness = append(h.readiness, namedCheck{name: name, check: check})
+}
+
+func (h *HealthHandler) Livez(c *fiber.Ctx) error {
+	return h.respond(c, h.liveness)
+}
+
+func (h *HealthHandler) Readyz(c *fiber.Ctx) error {
+	return h.respond(c, h.readiness)
+}
+
+func (h *HealthHandler) respond(c *fiber.Ctx, checks []namedCheck) error {
+	resp := h.run(c.UserContext(), checks)
+
+	status := fiber.StatusOK
+	if resp.Status != healthStatusOK {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(resp)
+}
+
+// run executes all checks concurrently under a shared timeout
+func (h *HealthHandler) run(ctx context.Context, checks []namedCheck) *HealthResp {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	resp := &HealthResp{
+		Status: healthStatusOK,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := nc.check(ctx)
+			result := CheckResult{
+				Status:  healthStatusOK,
+				Latency: time.Since(start).String(),
+			}
+			if err != nil {
+				result.Status = healthStatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[nc.name] = result
+			if err != nil {
+				resp.Status = healthStatusFail
+			}
+		}(nc)
+	}
+	wg.Wait()
+
+	return resp
+}
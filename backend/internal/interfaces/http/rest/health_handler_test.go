@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newHealthApp(h *HealthHandler) *fiber.App {
+	app := fiber.New()
+	app.Get("/livez", h.Livez)
+	app.Get("/readyz", h.Readyz)
+	return app
+}
+
+func decodeHealth(t *testing.T, app *fiber.App, path string) (int, HealthResp) {
+	t.Helper()
+
+	res, err := app.Test(httptest.NewRequest("GET", path, nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	var body HealthResp
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	return res.StatusCode, body
+}
+
+func TestHealthHandler_AllChecksPass(t *testing.T) {
+	h := NewHealthHandler(time.Second)
+	h.AddReadinessCheck("postgres", func(ctx context.Context) error { return nil })
+	h.AddReadinessCheck("hub", func(ctx context.Context) error { return nil })
+
+	status, body := decodeHealth(t, newHealthApp(h), "/readyz")
+
+	if status != fiber.StatusOK {
+		t.Errorf("expected status 200, got %d", status)
+	}
+	if body.Status != healthStatusOK {
+		t.Errorf("expected overall status 'ok', got '%s'", body.Status)
+	}
+	if len(body.Checks) != 2 {
+		t.Errorf("expected 2 checks, got %d", len(body.Checks))
+	}
+}
+
+func TestHealthHandler_FailingCheck(t *testing.T) {
+	h := NewHealthHandler(time.Second)
+	h.AddReadinessCheck("postgres", func(ctx context.Context) error { return errors.New("connection refused") })
+	h.AddReadinessCheck("hub", func(ctx context.Context) error { return nil })
+
+	status, body := decodeHealth(t, newHealthApp(h), "/readyz")
+
+	if status != fiber.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", status)
+	}
+	if body.Status != healthStatusFail {
+		t.Errorf("expected overall status 'fail', got '%s'", body.Status)
+	}
+	if body.Checks["postgres"].Error != "connection refused" {
+		t.Errorf("expected postgres error 'connection refused', got '%s'", body.Checks["postgres"].Error)
+	}
+	if body.Checks["hub"].Status != healthStatusOK {
+		t.Errorf("expected hub status 'ok', got '%s'", body.Checks["hub"].Status)
+	}
+}
+
+func TestHealthHandler_CheckTimesOut(t *testing.T) {
+	h := NewHealthHandler(50 * time.Millisecond)
+	h.AddLivenessCheck("hub", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	status, body := decodeHealth(t, newHealthApp(h), "/livez")
+
+	if status != fiber.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", status)
+	}
+	if body.Checks["hub"].Status != healthStatusFail {
+		t.Errorf("expected hub status 'fail', got '%s'", body.Checks["hub"].Status)
+	}
+}
@@ -1,26 +1,36 @@
 package rest
 
 import (
+	"context"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/vitaly-stepin/agile_party/internal/application"
 	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
 )
 
+// RoomPublisher runs room commands in step with the room's live events and sends their
+// outcome to the room's clients, the same way the websocket events do
+type RoomPublisher interface {
+	PublishJoin(ctx context.Context, roomID, userID, userName string) error
+	PublishLeave(ctx context.Context, roomID, userID string) error
+	PublishNameChange(ctx context.Context, roomID, userID, userName string) error
+	PublishVote(ctx context.Context, roomID string, req *dto.SubmitVoteReq) error
+	PublishReveal(ctx context.Context, roomID string) (*dto.RevealVotesResp, error)
+	PublishClear(ctx context.Context, roomID, userID string) error
+	PublishTaskFilter(ctx context.Context, roomID string, req dto.TaskFilterReq) (*dto.RoomResp, error)
+}
+
 type RoomHandler struct {
-	roomService   *application.RoomService
-	userService   *application.UserService
-	votingService *application.VotingService
+	roomService *application.RoomService
+	publisher   RoomPublisher
 }
 
-func NewRoomHandler(
-	roomService *application.RoomService,
-	userService *application.UserService,
-	votingService *application.VotingService,
-) *RoomHandler {
+func NewRoomHandler(roomService *application.RoomService, publisher RoomPublisher) *RoomHandler {
 	return &RoomHandler{
-		roomService:   roomService,
-		userService:   userService,
-		votingService: votingService,
+		roomService: roomService,
+		publisher:   publisher,
 	}
 }
 
@@ -35,8 +45,11 @@ func (h *RoomHandler) NewRoom(c *fiber.Ctx) error {
 	if req.VotingSystem == "" {
 		req.VotingSystem = "dbs_fibo"
 	}
+	if len(req.Dimensions) > 0 && req.Combination == "" {
+		req.Combination = "sum"
+	}
 
-	response, err := h.roomService.NewRoom(c.Context(), &req)
+	response, err := h.roomService.NewRoom(c.UserContext(), &req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -54,7 +67,7 @@ func (h *RoomHandler) GetRoom(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.roomService.GetRoom(c.Context(), roomID)
+	response, err := h.roomService.GetRoom(c.UserContext(), roomID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
@@ -72,7 +85,7 @@ func (h *RoomHandler) GetRoomState(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.roomService.GetRoomState(c.Context(), roomID)
+	response, err := h.roomService.GetRoomState(c.UserContext(), roomID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
@@ -82,6 +95,42 @@ func (h *RoomHandler) GetRoomState(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// SetTaskFilter saves the filter that restricts next-task selection; an empty body clears it
+func (h *RoomHandler) SetTaskFilter(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if roomID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID is required",
+		})
+	}
+
+	var req dto.TaskFilterReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	response, err := h.publisher.PublishTaskFilter(c.UserContext(), roomID, req)
+	if err != nil {
+		if errors.Is(err, room.ErrRoomNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if isInvalidTaskFilter(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
 func (h *RoomHandler) JoinRoom(c *fiber.Ctx) error {
 	roomID := c.Params("id")
 	if roomID == "" {
@@ -106,7 +155,7 @@ func (h *RoomHandler) JoinRoom(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.userService.JoinRoom(c.Context(), roomID, req.UserID, req.UserName); err != nil {
+	if err := h.publisher.PublishJoin(c.UserContext(), roomID, req.UserID, req.UserName); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -127,7 +176,7 @@ func (h *RoomHandler) LeaveRoom(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.userService.LeaveRoom(c.Context(), roomID, userID); err != nil {
+	if err := h.publisher.PublishLeave(c.UserContext(), roomID, userID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -163,7 +212,7 @@ func (h *RoomHandler) UpdateUserName(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.userService.UpdateUserName(c.Context(), roomID, userID, req.UserName); err != nil {
+	if err := h.publisher.PublishNameChange(c.UserContext(), roomID, userID, req.UserName); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -189,13 +238,13 @@ func (h *RoomHandler) SubmitVote(c *fiber.Ctx) error {
 		})
 	}
 
-	if req.UserID == "" || req.Value == "" {
+	if req.UserID == "" || (req.Value == "" && len(req.Values) == 0 && req.ThreePoint == nil) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "user_id and value are required",
 		})
 	}
 
-	if err := h.votingService.SubmitVote(c.Context(), roomID, req.UserID, req.Value); err != nil {
+	if err := h.publisher.PublishVote(c.UserContext(), roomID, &req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -214,7 +263,7 @@ func (h *RoomHandler) RevealVotes(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.votingService.RevealVotes(c.Context(), roomID)
+	response, err := h.publisher.PublishReveal(c.UserContext(), roomID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -224,6 +273,8 @@ func (h *RoomHandler) RevealVotes(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// ClearVotes resets the round. The clear can be undone by the facilitator and by the user
+// named in the optional user_id of the body.
 func (h *RoomHandler) ClearVotes(c *fiber.Ctx) error {
 	roomID := c.Params("id")
 	if roomID == "" {
@@ -232,7 +283,18 @@ func (h *RoomHandler) ClearVotes(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.votingService.ClearVotes(c.Context(), roomID); err != nil {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	if err := h.publisher.PublishClear(c.UserContext(), roomID, req.UserID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
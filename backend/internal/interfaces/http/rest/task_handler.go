@@ -0,0 +1,349 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vitaly-stepin/agile_party/internal/application"
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+// TaskPublisher runs a task edit in step with the room's live events and sends the
+// edited task to the room's clients
+type TaskPublisher interface {
+	PublishTaskEdit(ctx context.Context, roomID string, edit func(ctx context.Context) (*dto.TaskResp, error)) (*dto.TaskResp, error)
+}
+
+type TaskHandler struct {
+	taskService    *application.TaskService
+	commentService *application.CommentService
+	publisher      TaskPublisher
+}
+
+func NewTaskHandler(taskService *application.TaskService, commentService *application.CommentService, publisher TaskPublisher) *TaskHandler {
+	return &TaskHandler{
+		taskService:    taskService,
+		commentService: commentService,
+		publisher:      publisher,
+	}
+}
+
+// ListTasks returns the room's backlog, optionally narrowed by the comma-separated
+// label, type and status query parameters.
+func (h *TaskHandler) ListTasks(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if roomID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID is required",
+		})
+	}
+
+	filter := dto.TaskFilterReq{
+		Labels:   queryList(c, "label"),
+		Types:    queryList(c, "type"),
+		Statuses: queryList(c, "status"),
+	}
+
+	tasks, err := h.taskService.GetRoomTasks(c.UserContext(), roomID, filter)
+	if err != nil {
+		if isInvalidTaskFilter(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if tasks == nil {
+		tasks = []*dto.TaskResp{}
+	}
+	return c.JSON(fiber.Map{
+		"tasks": tasks,
+	})
+}
+
+// UpdateTask edits a task and sends it to the room's clients. The body must carry the version
+// the edit was made on; an outdated version is answered with 409 and the current task, so the
+// client can merge and retry.
+func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	taskID := c.Params("taskId")
+	if roomID == "" || taskID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID and task ID are required",
+		})
+	}
+
+	var req dto.UpdateTaskReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	task, err := h.publisher.PublishTaskEdit(c.UserContext(), roomID, func(ctx context.Context) (*dto.TaskResp, error) {
+		return h.taskService.UpdateTask(ctx, roomID, taskID, &req)
+	})
+	if err != nil {
+		return taskUpdateError(c, err)
+	}
+	return c.JSON(task)
+}
+
+// GetTaskTree returns the room's backlog nested under epics, with their rolled-up estimates
+func (h *TaskHandler) GetTaskTree(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if roomID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID is required",
+		})
+	}
+
+	tree, err := h.taskService.GetRoomTaskTree(c.UserContext(), roomID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if tree == nil {
+		tree = []*dto.TaskNodeResp{}
+	}
+	return c.JSON(fiber.Map{
+		"tasks": tree,
+	})
+}
+
+// ProposeTaskOrder returns a backlog order respecting task dependencies without applying it
+func (h *TaskHandler) ProposeTaskOrder(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	if roomID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID is required",
+		})
+	}
+
+	taskIDs, err := h.taskService.ProposeTaskOrder(c.UserContext(), roomID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"taskIds": taskIDs,
+	})
+}
+
+func (h *TaskHandler) GetEstimationHistory(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	taskID := c.Params("taskId")
+	if roomID == "" || taskID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID and task ID are required",
+		})
+	}
+
+	records, err := h.taskService.GetEstimationHistory(c.UserContext(), roomID, taskID)
+	if err != nil {
+		if errors.Is(err, room.ErrTaskNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// The discussion thread goes along so the decisions behind the estimates travel with them
+	comments, err := h.commentService.GetThread(c.UserContext(), roomID, taskID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"estimations": records,
+		"comments":    comments,
+	})
+}
+
+// ListComments returns a page of the task's discussion thread, oldest first.
+// Pagination uses the limit and offset query parameters.
+func (h *TaskHandler) ListComments(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	taskID := c.Params("taskId")
+	if roomID == "" || taskID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID and task ID are required",
+		})
+	}
+
+	limit := c.QueryInt("limit", application.DefaultCommentPageSize)
+	offset := c.QueryInt("offset", 0)
+
+	page, err := h.commentService.ListComments(c.UserContext(), roomID, taskID, limit, offset)
+	if err != nil {
+		if errors.Is(err, room.ErrTaskNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(page)
+}
+
+func (h *TaskHandler) GetCriteria(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	taskID := c.Params("taskId")
+	if roomID == "" || taskID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Room ID and task ID are required",
+		})
+	}
+
+	task, err := h.taskService.GetRoomTask(c.UserContext(), roomID, taskID)
+	if err != nil {
+		return criteriaError(c, err)
+	}
+
+	criteria := task.AcceptanceCriteria
+	if criteria == nil {
+		criteria = []dto.AcceptanceCriterionResp{}
+	}
+	return c.JSON(fiber.Map{
+		"criteria": criteria,
+	})
+}
+
+func (h *TaskHandler) AddCriterion(c *fiber.Ctx) error {
+	var req dto.AddCriterionReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	roomID := c.Params("id")
+	task, err := h.publisher.PublishTaskEdit(c.UserContext(), roomID, func(ctx context.Context) (*dto.TaskResp, error) {
+		return h.taskService.AddCriterion(ctx, roomID, c.Params("taskId"), req.Text)
+	})
+	if err != nil {
+		return criteriaError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(task)
+}
+
+func (h *TaskHandler) UpdateCriterion(c *fiber.Ctx) error {
+	var req dto.UpdateCriterionReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	roomID := c.Params("id")
+	task, err := h.publisher.PublishTaskEdit(c.UserContext(), roomID, func(ctx context.Context) (*dto.TaskResp, error) {
+		return h.taskService.UpdateCriterion(ctx, roomID, c.Params("taskId"), c.Params("criterionId"), &req)
+	})
+	if err != nil {
+		return criteriaError(c, err)
+	}
+	return c.JSON(task)
+}
+
+func (h *TaskHandler) DeleteCriterion(c *fiber.Ctx) error {
+	roomID := c.Params("id")
+	task, err := h.publisher.PublishTaskEdit(c.UserContext(), roomID, func(ctx context.Context) (*dto.TaskResp, error) {
+		return h.taskService.DeleteCriterion(ctx, roomID, c.Params("taskId"), c.Params("criterionId"))
+	})
+	if err != nil {
+		return criteriaError(c, err)
+	}
+	return c.JSON(task)
+}
+
+func (h *TaskHandler) ReorderCriteria(c *fiber.Ctx) error {
+	var req dto.ReorderCriteriaReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	roomID := c.Params("id")
+	task, err := h.publisher.PublishTaskEdit(c.UserContext(), roomID, func(ctx context.Context) (*dto.TaskResp, error) {
+		return h.taskService.ReorderCriteria(ctx, roomID, c.Params("taskId"), req.CriterionIDs)
+	})
+	if err != nil {
+		return criteriaError(c, err)
+	}
+	return c.JSON(task)
+}
+
+// criteriaError answers 404 for unknown tasks or criteria and 400 for rejected changes
+func criteriaError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusBadRequest
+	if errors.Is(err, room.ErrTaskNotFound) || errors.Is(err, room.ErrCriterionNotFound) {
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// taskUpdateError answers 409 with the current task for outdated edits, 404 for unknown tasks
+// and 400 for rejected values
+func taskUpdateError(c *fiber.Ctx, err error) error {
+	var conflict *application.TaskConflictError
+	if errors.As(err, &conflict) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  "CONFLICT",
+			"task":  conflict.Current,
+		})
+	}
+
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, room.ErrTaskNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, room.ErrTaskVersionRequired),
+		errors.Is(err, room.ErrEmptyTaskHeadline),
+		errors.Is(err, room.ErrTaskHeadlineTooLong),
+		isInvalidTaskFilter(err):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// queryList splits a comma-separated query parameter, dropping empty entries
+func queryList(c *fiber.Ctx, key string) []string {
+	var values []string
+	for _, v := range strings.Split(c.Query(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func isInvalidTaskFilter(err error) bool {
+	return errors.Is(err, room.ErrUnknownTaskType) ||
+		errors.Is(err, room.ErrInvalidTaskStatus) ||
+		errors.Is(err, room.ErrTooManyTaskLabels) ||
+		errors.Is(err, room.ErrTaskLabelTooLong)
+}
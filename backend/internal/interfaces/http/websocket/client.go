@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"log"
 	"time"
 
@@ -28,6 +29,7 @@ type Client struct {
 	UserID  string
 	send    chan []byte
 	handler MessageHandler
+	limiter *clientLimiter // nil disables rate limiting
 }
 
 type MessageHandler interface {
@@ -85,6 +87,24 @@ func (c *Client) readPump() {
 			break
 		}
 
+		if c.limiter != nil && !c.limiter.Allow() {
+			if c.limiter.RecordViolation(time.Now()) {
+				log.Printf("Disconnecting user %s in room %s: too many rate-limited events", c.UserID, c.RoomID)
+				c.conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limit exceeded"),
+					time.Now().Add(writeWait))
+				break
+			}
+			c.Send(WsMessage{
+				Type: EventTypeError,
+				Payload: ErrorPayload{
+					Message: "Too many events, slow down",
+					Code:    ErrCodeRateLimited,
+				},
+			})
+			continue
+		}
+
 		if err := c.handler.HandleMessage(c, msg); err != nil {
 			log.Printf("Error handling message from user %s in room %s: %v", c.UserID, c.RoomID, err)
 
@@ -159,6 +179,21 @@ func (c *Client) Start() {
 	c.readPump()
 }
 
+// Send queues a message for this client only, dropping it if the client is not keeping up
+func (c *Client) Send(msg WsMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to marshal message for user %s: %v", c.UserID, err)
+		return
+	}
+
+	select {
+	case c.send <- data:
+	default:
+		log.Printf("Client %s send channel full, dropping message", c.UserID)
+	}
+}
+
 func (c *Client) SendError(message, code string) {
 	c.hub.BroadcastToRoom(c.RoomID, WsMessage{
 		Type: EventTypeError,
@@ -1,38 +1,75 @@
 package websocket
 
+import "time"
+
 // WsEventType represents WebSocket event types
 type WsEventType string
 
 // Client Events
 const (
-	EventTypeVote           WsEventType = "vote"
-	EventTypeReveal         WsEventType = "reveal"
-	EventTypeClear          WsEventType = "clear"
-	EventTypeUpdateNickname WsEventType = "update_nickname"
-	EventTypeSetTask        WsEventType = "set_task"
-	EventTypeCreateTask     WsEventType = "create_task"
-	EventTypeUpdateTask     WsEventType = "update_task"
-	EventTypeDeleteTask     WsEventType = "delete_task"
-	EventTypeReorderTasks   WsEventType = "reorder_tasks"
-	EventTypeSetActiveTask  WsEventType = "set_active_task"
+	EventTypeVote             WsEventType = "vote"
+	EventTypeReveal           WsEventType = "reveal"
+	EventTypeClear            WsEventType = "clear"
+	EventTypeUpdateNickname   WsEventType = "update_nickname"
+	EventTypeSetTask          WsEventType = "set_task"
+	EventTypeCreateTask       WsEventType = "create_task"
+	EventTypeUpdateTask       WsEventType = "update_task"
+	EventTypeDeleteTask       WsEventType = "delete_task"
+	EventTypeReorderTasks     WsEventType = "reorder_tasks"
+	EventTypeMoveTask         WsEventType = "move_task"
+	EventTypeSetActiveTask    WsEventType = "set_active_task"
+	EventTypeResync           WsEventType = "resync"
+	EventTypeSkipTask         WsEventType = "skip_task"
+	EventTypeDeferTask        WsEventType = "defer_task"
+	EventTypeReopenTask       WsEventType = "reopen_task"
+	EventTypeAcceptEstimate   WsEventType = "accept_estimate"
+	EventTypeNextTask         WsEventType = "next_task"
+	EventTypePreviousTask     WsEventType = "previous_task"
+	EventTypeJumpToTask       WsEventType = "jump_to_task"
+	EventTypeReestimateTask   WsEventType = "reestimate_task"
+	EventTypeRaiseHand        WsEventType = "raise_hand"
+	EventTypeLowerHand        WsEventType = "lower_hand"
+	EventTypeAddComment       WsEventType = "add_comment"
+	EventTypeEditComment      WsEventType = "edit_comment"
+	EventTypeDeleteComment    WsEventType = "delete_comment"
+	EventTypeUpdateNotes      WsEventType = "update_notes"
+	EventTypeAddCriterion     WsEventType = "add_criterion"
+	EventTypeUpdateCriterion  WsEventType = "update_criterion"
+	EventTypeDeleteCriterion  WsEventType = "delete_criterion"
+	EventTypeReorderCriteria  WsEventType = "reorder_criteria"
+	EventTypeSetTaskFilter    WsEventType = "set_task_filter"
+	EventTypeSetTaskParent    WsEventType = "set_task_parent"
+	EventTypeAddDependency    WsEventType = "add_dependency"
+	EventTypeRemoveDependency WsEventType = "remove_dependency"
+	EventTypeProposeTaskOrder WsEventType = "propose_task_order"
+	EventTypeUndo             WsEventType = "undo"
 )
 
 // Server Events
 const (
-	EventTypeRoomState      WsEventType = "room_state"
-	EventTypeUserJoined     WsEventType = "user_joined"
-	EventTypeUserLeft       WsEventType = "user_left"
-	EventTypeVoteSubmitted  WsEventType = "vote_submitted"
-	EventTypeVotesRevealed  WsEventType = "votes_revealed"
-	EventTypeVotesCleared   WsEventType = "votes_cleared"
-	EventTypeUserUpdated    WsEventType = "user_updated"
-	EventTypeError          WsEventType = "error"
-	EventTypeTaskCreated    WsEventType = "task_created"
-	EventTypeTaskUpdated    WsEventType = "task_updated"
-	EventTypeTaskDeleted    WsEventType = "task_deleted"
-	EventTypeTasksReordered WsEventType = "tasks_reordered"
-	EventTypeActiveTaskSet  WsEventType = "active_task_set"
-	EventTypeTaskListSync   WsEventType = "task_list_sync"
+	EventTypeRoomState          WsEventType = "room_state"
+	EventTypeUserJoined         WsEventType = "user_joined"
+	EventTypeUserLeft           WsEventType = "user_left"
+	EventTypeVoteSubmitted      WsEventType = "vote_submitted"
+	EventTypeVotesRevealed      WsEventType = "votes_revealed"
+	EventTypeVotesCleared       WsEventType = "votes_cleared"
+	EventTypeUserUpdated        WsEventType = "user_updated"
+	EventTypeError              WsEventType = "error"
+	EventTypeTaskCreated        WsEventType = "task_created"
+	EventTypeTaskUpdated        WsEventType = "task_updated"
+	EventTypeTaskDeleted        WsEventType = "task_deleted"
+	EventTypeTasksReordered     WsEventType = "tasks_reordered"
+	EventTypeActiveTaskSet      WsEventType = "active_task_set"
+	EventTypeTaskListSync       WsEventType = "task_list_sync"
+	EventTypeRoundReset         WsEventType = "round_reset"
+	EventTypeTaskDescriptionSet WsEventType = "task_description_set"
+	EventTypeHandsUpdated       WsEventType = "hands_updated"
+	EventTypeCommentAdded       WsEventType = "comment_added"
+	EventTypeCommentUpdated     WsEventType = "comment_updated"
+	EventTypeCommentDeleted     WsEventType = "comment_deleted"
+	EventTypeTaskFilterSet      WsEventType = "task_filter_set"
+	EventTypeTaskOrderProposed  WsEventType = "task_order_proposed"
+	EventTypeUndoUpdated        WsEventType = "undo_updated"
 )
 
 type WsMessage struct {
@@ -40,8 +77,88 @@ type WsMessage struct {
 	Payload interface{} `json:"payload"`
 }
 
+// VotePayload carries a single card, one card per dimension in multi-dimensional rooms,
+// or a triple in three-point rooms
 type VotePayload struct {
-	Value string `json:"value"`
+	Value      string             `json:"value,omitempty"`
+	Values     map[string]string  `json:"values,omitempty"` // dimension key -> card
+	ThreePoint *ThreePointPayload `json:"threePoint,omitempty"`
+	Comment    string             `json:"comment,omitempty"` // optional rationale, revealed with the votes
+}
+
+type ThreePointPayload struct {
+	Optimistic  string `json:"optimistic"`
+	MostLikely  string `json:"mostLikely"`
+	Pessimistic string `json:"pessimistic"`
+}
+
+// RaiseHandPayload signals "question" (the default) or "split"
+type RaiseHandPayload struct {
+	Signal string `json:"signal,omitempty"`
+}
+
+// LowerHandPayload lowers the sender's hand, or another user's when the facilitator calls on them
+type LowerHandPayload struct {
+	UserID string `json:"userId,omitempty"`
+}
+
+// HandsUpdatedPayload is the whole hand queue, first raised first
+type HandsUpdatedPayload struct {
+	Hands []RaisedHandPayload `json:"hands"`
+}
+
+type RaisedHandPayload struct {
+	UserID   string `json:"userId"`
+	Signal   string `json:"signal"`
+	Position int    `json:"position"` // 1-based, the facilitator calls on the lowest first
+}
+
+// AddCommentPayload posts to a task's thread; an empty TaskID targets the active task
+type AddCommentPayload struct {
+	TaskID string `json:"taskId"`
+	Body   string `json:"body"`
+}
+
+// UpdateNotesPayload replaces a task's decision log; an empty TaskID targets the active task
+type UpdateNotesPayload struct {
+	TaskID string   `json:"taskId"`
+	Notes  []string `json:"notes"`
+}
+
+// CriterionPayload is sent with add_criterion, update_criterion and delete_criterion.
+// An empty TaskID targets the active task; Text and Done are left as is when omitted on update.
+type CriterionPayload struct {
+	TaskID      string  `json:"taskId"`
+	CriterionID string  `json:"criterionId,omitempty"`
+	Text        *string `json:"text,omitempty"`
+	Done        *bool   `json:"done,omitempty"`
+}
+
+type ReorderCriteriaPayload struct {
+	TaskID       string   `json:"taskId"`
+	CriterionIDs []string `json:"criterionIds"`
+}
+
+// TaskFilterPayload carries the room's saved next-task filter; empty lists match everything
+type TaskFilterPayload struct {
+	Labels   []string `json:"labels"`
+	Types    []string `json:"types"`
+	Statuses []string `json:"statuses"`
+}
+
+type EditCommentPayload struct {
+	CommentID string `json:"commentId"`
+	Body      string `json:"body"`
+}
+
+type DeleteCommentPayload struct {
+	CommentID string `json:"commentId"`
+}
+
+// CommentDeletedPayload names the removed comment and the thread it belonged to
+type CommentDeletedPayload struct {
+	CommentID string `json:"commentId"`
+	TaskID    string `json:"taskId"`
 }
 
 type UpdateNicknamePayload struct {
@@ -53,13 +170,15 @@ type SetTaskPayload struct {
 }
 
 type RoomStatePayload struct {
-	RoomID          string        `json:"roomId"`
-	RoomName        string        `json:"roomName"`
-	Users           []UserPayload `json:"users"`
-	Votes           []VoteInfo    `json:"votes"`
-	IsRevealed      bool          `json:"isRevealed"`
-	TaskDescription string        `json:"taskDescription"`
-	Average         *float64      `json:"average,omitempty"`
+	RoomID          string         `json:"roomId"`
+	RoomName        string         `json:"roomName"`
+	Users           []UserPayload  `json:"users"`
+	Votes           []VoteInfo     `json:"votes"`
+	IsRevealed      bool           `json:"isRevealed"`
+	TaskDescription string         `json:"taskDescription"`
+	Average         *float64       `json:"average,omitempty"`
+	FacilitatorID   string         `json:"facilitatorId"`
+	LastChange      *ChangePayload `json:"lastChange,omitempty"` // latest change that can be undone
 }
 
 type UserPayload struct {
@@ -67,21 +186,31 @@ type UserPayload struct {
 	Name     string `json:"name"`
 	IsVoted  bool   `json:"isVoted"`
 	IsOnline bool   `json:"isOnline"`
+
+	RaisedHand   string `json:"raisedHand,omitempty"`   // "question" or "split"
+	HandPosition int    `json:"handPosition,omitempty"` // 1-based place in the hand queue
 }
 
+// VoteInfo is one vote; UserID and UserName are left empty in anonymous rooms
 type VoteInfo struct {
-	UserID   string `json:"userId"`
-	Value    string `json:"value"`
-	UserName string `json:"userName"`
+	UserID     string                   `json:"userId,omitempty"`
+	Value      string                   `json:"value"`
+	UserName   string                   `json:"userName,omitempty"`
+	Dimensions map[string]string        `json:"dimensions,omitempty"`
+	ThreePoint *ThreePointResultPayload `json:"threePoint,omitempty"`
+	Comment    string                   `json:"comment,omitempty"`
 }
 
 type UserJoinedPayload struct {
 	UserID   string `json:"userId"`
 	Nickname string `json:"nickname"`
+	IsVoted  bool   `json:"isVoted"` // true when a reconnecting user already voted this round
+	IsOnline bool   `json:"isOnline"`
 }
 
 type UserLeftPayload struct {
-	UserID string `json:"userId"`
+	UserID        string `json:"userId"`
+	FacilitatorID string `json:"facilitatorId,omitempty"` // the room's facilitator once the user has left
 }
 
 type VoteSubmittedPayload struct {
@@ -90,33 +219,95 @@ type VoteSubmittedPayload struct {
 }
 
 type VotesRevealedPayload struct {
-	Votes   []VoteInfo `json:"votes"`
-	Average *float64   `json:"average,omitempty"`
+	Votes      []VoteInfo               `json:"votes"`
+	Average    *float64                 `json:"average,omitempty"`
+	Suggested  string                   `json:"suggested,omitempty"` // card proposed for accept_estimate
+	Dimensions []DimensionResultPayload `json:"dimensions,omitempty"`
+	Pert       *PertPayload             `json:"pert,omitempty"` // three-point rooms: expected value and range
+	Anonymous  bool                     `json:"anonymous,omitempty"`
+	Changes    []VoteChangePayload      `json:"changes,omitempty"` // votes changed after the reveal, with voter names
+}
+
+// ThreePointResultPayload is a revealed triple
+type ThreePointResultPayload struct {
+	Optimistic  float64 `json:"optimistic"`
+	MostLikely  float64 `json:"mostLikely"`
+	Pessimistic float64 `json:"pessimistic"`
+}
+
+// PertPayload is the team's three-point estimate: the mean triple, PERT expected value and standard deviation
+type PertPayload struct {
+	ThreePointResultPayload
+	Expected float64 `json:"expected"`
+	StdDev   float64 `json:"stdDev"`
+}
+
+// VoteChangePayload is a vote changed after the reveal; UserID and Voter are empty in anonymous rooms
+type VoteChangePayload struct {
+	UserID string `json:"userId,omitempty"`
+	Voter  string `json:"voter,omitempty"`
+	From   string `json:"from,omitempty"` // empty when the user first voted after the reveal
+	To     string `json:"to"`
+}
+
+type DimensionResultPayload struct {
+	Key       string   `json:"key"`
+	Name      string   `json:"name"`
+	Average   *float64 `json:"average,omitempty"`
+	Suggested string   `json:"suggested,omitempty"`
+}
+
+// AcceptEstimatePayload carries the facilitator's final value; Reason is kept only when it overrides the suggestion.
+// Breakdown adjusts the per-dimension values; when omitted the revealed suggestions are stored.
+type AcceptEstimatePayload struct {
+	Value     string            `json:"value"`
+	Reason    string            `json:"reason,omitempty"`
+	Breakdown map[string]string `json:"breakdown,omitempty"`
 }
 
 type VotesClearedPayload struct{}
 
+// RoundResetPayload replaces the votes_cleared/room_state/task_list_sync/active_task_set
+// sequence with a single transition: all votes are cleared and users reset to "not voted"
+type RoundResetPayload struct {
+	ActiveTaskID  string       `json:"activeTaskId,omitempty"`
+	ActiveTask    *TaskPayload `json:"activeTask,omitempty"`
+	EstimatedTask *TaskPayload `json:"estimatedTask,omitempty"` // task whose estimation was saved by this round
+}
+
 type UserUpdatedPayload struct {
 	UserID   string `json:"userId"`
 	Nickname string `json:"nickname"`
 }
 
 type ErrorPayload struct {
-	Message string `json:"message"`
-	Code    string `json:"code,omitempty"`
+	Message string       `json:"message"`
+	Code    string       `json:"code,omitempty"`
+	Task    *TaskPayload `json:"task,omitempty"` // current task, set with ErrCodeConflict
 }
 
+// ErrCodeConflict rejects an edit made on an outdated version of a task
+const ErrCodeConflict = "CONFLICT"
+
 type CreateTaskPayload struct {
-	Headline    string `json:"headline"`
-	Description string `json:"description,omitempty"`
-	TrackerLink string `json:"trackerLink,omitempty"`
+	Headline    string   `json:"headline"`
+	Description string   `json:"description,omitempty"`
+	TrackerLink string   `json:"trackerLink,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	ParentID    string   `json:"parentId,omitempty"`
 }
 
+// UpdateTaskPayload must carry the version of the task the edit was made on;
+// an outdated version is answered with a CONFLICT error holding the current task
 type UpdateTaskPayload struct {
-	TaskID      string `json:"taskId"`
-	Headline    string `json:"headline,omitempty"`
-	Description string `json:"description,omitempty"`
-	TrackerLink string `json:"trackerLink,omitempty"`
+	TaskID      string    `json:"taskId"`
+	Version     *int      `json:"version"`
+	Headline    string    `json:"headline,omitempty"`
+	Description string    `json:"description,omitempty"`
+	TrackerLink string    `json:"trackerLink,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
 }
 
 type DeleteTaskPayload struct {
@@ -127,10 +318,56 @@ type ReorderTasksPayload struct {
 	TaskIDs []string `json:"taskIds"`
 }
 
+// MoveTaskPayload places a task right before or right after another one; set exactly one of the two
+type MoveTaskPayload struct {
+	TaskID   string `json:"taskId"`
+	BeforeID string `json:"beforeId,omitempty"`
+	AfterID  string `json:"afterId,omitempty"`
+}
+
+// DependencyPayload is sent with add_dependency and remove_dependency; TaskID defaults to the active task
+type DependencyPayload struct {
+	TaskID      string `json:"taskId"`
+	DependsOnID string `json:"dependsOnId"`
+}
+
+// SetTaskParentPayload files a task under an epic; an empty ParentID moves it to the top level
+type SetTaskParentPayload struct {
+	TaskID   string `json:"taskId"`
+	ParentID string `json:"parentId"`
+}
+
+// SetActiveTaskPayload is sent with set_active_task and jump_to_task
 type SetActiveTaskPayload struct {
 	TaskID string `json:"taskId"`
 }
 
+// UndoUpdatedPayload follows every undoable change and every undo. LastChange is the change
+// the next undo would revert, nil when there is none; Undone is set when a change was just undone.
+type UndoUpdatedPayload struct {
+	LastChange *ChangePayload `json:"lastChange"`
+	Undone     *ChangePayload `json:"undone,omitempty"`
+}
+
+// ChangePayload names a change that can be undone and who made it
+type ChangePayload struct {
+	Action string `json:"action"`
+	UserID string `json:"userId"`
+}
+
+// ActiveTaskSetPayload announces the new active task with its details.
+// Receiving it also means the previous round was cleared.
+type ActiveTaskSetPayload struct {
+	TaskID string       `json:"taskId"`
+	Task   *TaskPayload `json:"task,omitempty"`
+}
+
+// TaskStatusPayload is sent with skip_task, defer_task, reopen_task and reestimate_task.
+// An empty TaskID targets the active task.
+type TaskStatusPayload struct {
+	TaskID string `json:"taskId"`
+}
+
 type TaskPayload struct {
 	ID          string `json:"id"`
 	RoomID      string `json:"roomId"`
@@ -138,9 +375,58 @@ type TaskPayload struct {
 	Description string `json:"description,omitempty"`
 	TrackerLink string `json:"trackerLink,omitempty"`
 	Estimation  string `json:"estimation,omitempty"`
+	Status      string `json:"status"`
 	Position    int    `json:"position"`
+	Version     int    `json:"version"`
+
+	ParentID string                 `json:"parentId,omitempty"`
+	Rollup   *EstimateRollupPayload `json:"rollup,omitempty"`
+
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	Type   string   `json:"type"`
+	Labels []string `json:"labels,omitempty"`
+
+	EstimationOverridden bool   `json:"estimationOverridden,omitempty"`
+	OverrideReason       string `json:"overrideReason,omitempty"`
+	PreviousEstimation   string `json:"previousEstimation,omitempty"`
+
+	EstimationBreakdown map[string]string `json:"estimationBreakdown,omitempty"`
+	Pert                *PertPayload      `json:"pert,omitempty"`
+
+	Notes        *TaskNotesPayload  `json:"notes,omitempty"`        // decision log agreed for the estimate
+	NotesHistory []TaskNotesPayload `json:"notesHistory,omitempty"` // latest earlier revisions, oldest first
+
+	AcceptanceCriteria []AcceptanceCriterionPayload `json:"acceptanceCriteria,omitempty"`
+}
+
+// EstimateRollupPayload is an epic's estimate summed from its leaf subtasks
+type EstimateRollupPayload struct {
+	Points    string `json:"points"`
+	Estimated int    `json:"estimated"`
+	Subtasks  int    `json:"subtasks"`
+}
+
+// TaskNotesPayload is one revision of a task's decision log
+type TaskNotesPayload struct {
+	Items     []string  `json:"items"`
+	Author    string    `json:"author,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type AcceptanceCriterionPayload struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
 }
 
+// TaskListSyncPayload carries the backlog both flat, in position order, and nested under epics
 type TaskListSyncPayload struct {
-	Tasks []TaskPayload `json:"tasks"`
+	Tasks []TaskPayload     `json:"tasks"`
+	Tree  []TaskNodePayload `json:"tree"`
+}
+
+type TaskNodePayload struct {
+	TaskPayload
+	Subtasks []TaskNodePayload `json:"subtasks,omitempty"`
 }
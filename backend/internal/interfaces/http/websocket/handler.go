@@ -3,16 +3,25 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/fasthttp/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/valyala/fasthttp"
 	"github.com/vitaly-stepin/agile_party/internal/application"
 	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/vitaly-stepin/agile_party/internal/interfaces/http/websocket"
+
 var upgrader = websocket.FastHTTPUpgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -23,11 +32,15 @@ var upgrader = websocket.FastHTTPUpgrader{
 }
 
 type WsHandler struct {
-	hub           *WsHub
-	roomService   *application.RoomService
-	userService   *application.UserService
-	votingService *application.VotingService
-	taskService   *application.TaskService
+	hub            *WsHub
+	roomService    *application.RoomService
+	userService    *application.UserService
+	votingService  *application.VotingService
+	taskService    *application.TaskService
+	commentService *application.CommentService
+	undoService    *application.UndoService
+	limiter        *EventLimiter
+	actors         *RoomActors
 }
 
 func NewHandler(
@@ -36,14 +49,114 @@ func NewHandler(
 	userService *application.UserService,
 	votingService *application.VotingService,
 	taskService *application.TaskService,
+	commentService *application.CommentService,
+	undoService *application.UndoService,
+	limiter *EventLimiter,
 ) *WsHandler {
 	return &WsHandler{
-		hub:           hub,
-		roomService:   roomService,
-		userService:   userService,
-		votingService: votingService,
-		taskService:   taskService,
+		hub:            hub,
+		roomService:    roomService,
+		userService:    userService,
+		votingService:  votingService,
+		taskService:    taskService,
+		commentService: commentService,
+		undoService:    undoService,
+		limiter:        limiter,
+		actors:         NewRoomActors(),
+	}
+}
+
+// PublishTaskEdit runs a task edit made outside the websocket, such as a REST call, on the
+// room actor like any client event and sends the edited task to the room's clients
+func (h *WsHandler) PublishTaskEdit(ctx context.Context, roomID string, edit func(ctx context.Context) (*dto.TaskResp, error)) (*dto.TaskResp, error) {
+	var task *dto.TaskResp
+	err := h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		var err error
+		if task, err = edit(ctx); err != nil {
+			return err
+		}
+
+		h.hub.BroadcastToRoom(roomID, WsMessage{
+			Type:    EventTypeTaskUpdated,
+			Payload: convertTaskToPayload(task),
+		}, nil)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// PublishJoin adds a user joining without a websocket, such as over REST, on the room actor
+func (h *WsHandler) PublishJoin(ctx context.Context, roomID, userID, userName string) error {
+	return h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		if err := h.userService.JoinRoom(ctx, roomID, userID, userName); err != nil {
+			return err
+		}
+
+		h.hub.BroadcastToRoom(roomID, WsMessage{
+			Type:    EventTypeUserJoined,
+			Payload: UserJoinedPayload{UserID: userID, Nickname: userName},
+		}, nil)
+		return nil
+	})
+}
+
+// PublishLeave removes a user from the room on the room actor, like a closed websocket
+func (h *WsHandler) PublishLeave(ctx context.Context, roomID, userID string) error {
+	return h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		return h.removeUser(ctx, roomID, userID)
+	})
+}
+
+// PublishNameChange renames a user on the room actor, like the update_nickname event
+func (h *WsHandler) PublishNameChange(ctx context.Context, roomID, userID, userName string) error {
+	return h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		return h.renameUser(ctx, roomID, userID, userName)
+	})
+}
+
+// PublishVote casts a vote on the room actor, like the vote event
+func (h *WsHandler) PublishVote(ctx context.Context, roomID string, req *dto.SubmitVoteReq) error {
+	return h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		return h.castVote(ctx, roomID, req)
+	})
+}
+
+// PublishReveal reveals the votes on the room actor, like the reveal event
+func (h *WsHandler) PublishReveal(ctx context.Context, roomID string) (*dto.RevealVotesResp, error) {
+	var result *dto.RevealVotesResp
+	err := h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		var err error
+		result, err = h.revealVotes(ctx, roomID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
+}
+
+// PublishClear resets the round on the room actor, like the clear event; userID may undo it
+func (h *WsHandler) PublishClear(ctx context.Context, roomID, userID string) error {
+	return h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		return h.clearRound(ctx, roomID, userID)
+	})
+}
+
+// PublishTaskFilter saves the room's task filter on the room actor, like the set_task_filter event
+func (h *WsHandler) PublishTaskFilter(ctx context.Context, roomID string, req dto.TaskFilterReq) (*dto.RoomResp, error) {
+	var rm *dto.RoomResp
+	err := h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		var err error
+		rm, err = h.setTaskFilter(ctx, roomID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rm, nil
 }
 
 func (h *WsHandler) HandleConnection(c *fiber.Ctx) error {
@@ -57,6 +170,8 @@ func (h *WsHandler) HandleConnection(c *fiber.Ctx) error {
 	nickname := c.Query("nickname")
 	nicknameCopy := string([]byte(nickname))
 
+	ipCopy := string([]byte(c.IP()))
+
 	log.Printf("[DEBUG] WebSocket connection - roomID: '%s', userID: '%s', path: '%s'", roomIDCopy, userIDCopy, c.Path())
 
 	if roomIDCopy == "" || userIDCopy == "" || nicknameCopy == "" {
@@ -67,75 +182,152 @@ func (h *WsHandler) HandleConnection(c *fiber.Ctx) error {
 
 	// Upgrade the http conn to a WebSocket
 	err := upgrader.Upgrade(c.Context(), func(conn *websocket.Conn) {
-		h.handleWebSocket(conn, roomIDCopy, userIDCopy, nicknameCopy)
+		h.handleWebSocket(conn, roomIDCopy, userIDCopy, nicknameCopy, ipCopy)
 	})
 
 	return err
 }
 
 // manages the WebSocket lifecycle for a client
-func (h *WsHandler) handleWebSocket(conn *websocket.Conn, roomID, userID, nickname string) {
+func (h *WsHandler) handleWebSocket(conn *websocket.Conn, roomID, userID, nickname, ip string) {
 	ctx := context.Background()
 
-	// Remove user if they already exist (handles reconnection/stale connections)
-	_ = h.userService.LeaveRoom(ctx, roomID, userID)
+	log.Printf("[DEBUG] Creating client - roomID: '%s', userID: '%s'", roomID, userID)
+	client := NewClient(conn, h.hub, roomID, userID, h)
+	if h.limiter != nil {
+		client.limiter = h.limiter.forClient(ip)
+	}
+	log.Printf("[DEBUG] Client created - client.RoomID: '%s', client.UserID: '%s'", client.RoomID, client.UserID)
 
-	if err := h.userService.JoinRoom(ctx, roomID, userID, nickname); err != nil {
+	// Joining goes through the room actor so the snapshot sent to the new client
+	// cannot be overtaken by a concurrent command for the same room
+	err := h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+		return h.join(ctx, client, nickname)
+	})
+	if err != nil {
 		log.Printf("Failed to join room %s for user %s: %v", roomID, userID, err)
+		code := "JOIN_FAILED"
+		if errors.Is(err, room.ErrRoomFull) {
+			code = "ROOM_FULL"
+		}
 		conn.WriteJSON(WsMessage{
 			Type: EventTypeError,
 			Payload: ErrorPayload{
 				Message: "Failed to join room: " + err.Error(),
-				Code:    "JOIN_FAILED",
+				Code:    code,
 			},
 		})
 		conn.Close()
 		return
 	}
 
-	log.Printf("[DEBUG] Creating client - roomID: '%s', userID: '%s'", roomID, userID)
-	client := NewClient(conn, h.hub, roomID, userID, h)
-	log.Printf("[DEBUG] Client created - client.RoomID: '%s', client.UserID: '%s'", client.RoomID, client.UserID)
+	defer func() {
+		_ = h.actors.Do(ctx, roomID, func(ctx context.Context) error {
+			h.leave(ctx, client)
+			return nil
+		})
+	}()
+
+	client.Start()
+}
+
+func (h *WsHandler) join(ctx context.Context, client *Client, nickname string) error {
+	// Remove user if they already exist (handles reconnection/stale connections)
+	_ = h.userService.LeaveRoom(ctx, client.RoomID, client.UserID)
+
+	if err := h.userService.JoinRoom(ctx, client.RoomID, client.UserID, nickname); err != nil {
+		return err
+	}
+
 	h.hub.register <- client
 
-	if err := h.sendRoomState(client); err != nil {
-		log.Printf("Failed to send initial state to user %s in room %s: %v", userID, roomID, err)
+	// The joining client is the only one that receives a full snapshot
+	joined := UserJoinedPayload{
+		UserID:   client.UserID,
+		Nickname: nickname,
+		IsOnline: true,
+	}
+	roomState, err := h.sendRoomState(ctx, client)
+	if err != nil {
+		log.Printf("Failed to send initial state to user %s in room %s: %v", client.UserID, client.RoomID, err)
+	} else {
+		for _, user := range roomState.Users {
+			if user.UserID == client.UserID {
+				joined.IsVoted = user.IsVoted
+				break
+			}
+		}
 	}
 
 	// Send initial task list
 	if err := h.sendTaskListSync(client); err != nil {
-		log.Printf("Failed to send task list to user %s in room %s: %v", userID, roomID, err)
+		log.Printf("Failed to send task list to user %s in room %s: %v", client.UserID, client.RoomID, err)
 	}
 
-	h.hub.BroadcastToRoom(roomID, WsMessage{
-		Type: EventTypeUserJoined,
-		Payload: UserJoinedPayload{
-			UserID:   userID,
-			Nickname: nickname,
-		},
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeUserJoined,
+		Payload: joined,
 	}, client)
 
-	defer func() {
-		if err := h.userService.LeaveRoom(ctx, roomID, userID); err != nil {
-			log.Printf("Failed to remove user %s from room %s: %v", userID, roomID, err)
-		}
+	return nil
+}
 
-		h.hub.BroadcastToRoom(roomID, WsMessage{
-			Type: EventTypeUserLeft,
-			Payload: UserLeftPayload{
-				UserID: userID,
-			},
-		}, nil)
+func (h *WsHandler) leave(ctx context.Context, client *Client) {
+	if err := h.removeUser(ctx, client.RoomID, client.UserID); err != nil {
+		log.Printf("Failed to remove user %s from room %s: %v", client.UserID, client.RoomID, err)
+	}
 
-		log.Printf("User %s disconnected from room %s", userID, roomID)
-	}()
+	log.Printf("User %s disconnected from room %s", client.UserID, client.RoomID)
+}
 
-	client.Start()
+// removeUser takes the user out of the room and tells the others, along with who facilitates now
+func (h *WsHandler) removeUser(ctx context.Context, roomID, userID string) error {
+	if err := h.userService.LeaveRoom(ctx, roomID, userID); err != nil {
+		return err
+	}
+
+	left := UserLeftPayload{UserID: userID}
+	if roomState, err := h.roomService.GetRoomState(ctx, roomID); err == nil {
+		left.FacilitatorID = roomState.FacilitatorID
+	}
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type:    EventTypeUserLeft,
+		Payload: left,
+	}, nil)
+
+	// Leaving also takes the user out of the hand queue
+	if err := h.broadcastHands(ctx, roomID); err != nil {
+		log.Printf("Warning: failed to send hand queue after user %s left room %s: %v", userID, roomID, err)
+	}
+
+	return nil
 }
 
+// HandleMessage runs each client event inside its own server span so the
+// service calls and SQL queries it triggers are grouped under one trace.
+// The event itself is executed on the room actor, after any earlier command for the room.
 func (h *WsHandler) HandleMessage(client *Client, msg WsMessage) error {
-	ctx := context.Background()
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "ws."+string(msg.Type),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("ws.event", string(msg.Type)),
+			attribute.String("room.id", client.RoomID),
+			attribute.String("user.id", client.UserID),
+		),
+	)
+	defer span.End()
+
+	err := h.actors.Do(ctx, client.RoomID, func(ctx context.Context) error {
+		return h.dispatch(ctx, client, msg)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
 
+func (h *WsHandler) dispatch(ctx context.Context, client *Client, msg WsMessage) error {
 	switch msg.Type {
 	case EventTypeVote:
 		return h.handleVote(ctx, client, msg)
@@ -146,6 +338,9 @@ func (h *WsHandler) HandleMessage(client *Client, msg WsMessage) error {
 	case EventTypeClear:
 		return h.handleClear(ctx, client)
 
+	case EventTypeAcceptEstimate:
+		return h.handleAcceptEstimate(ctx, client, msg)
+
 	case EventTypeUpdateNickname:
 		return h.handleUpdateNickname(ctx, client, msg)
 
@@ -164,8 +359,71 @@ func (h *WsHandler) HandleMessage(client *Client, msg WsMessage) error {
 	case EventTypeReorderTasks:
 		return h.handleReorderTasks(ctx, client, msg)
 
-	case EventTypeSetActiveTask:
-		return h.handleSetActiveTask(ctx, client, msg)
+	case EventTypeMoveTask:
+		return h.handleMoveTask(ctx, client, msg)
+
+	case EventTypeSetActiveTask, EventTypeJumpToTask:
+		return h.handleJumpToTask(ctx, client, msg)
+
+	case EventTypeReestimateTask:
+		return h.handleReestimateTask(ctx, client, msg)
+
+	case EventTypeNextTask:
+		return h.handleNextTask(ctx, client)
+
+	case EventTypePreviousTask:
+		return h.handlePreviousTask(ctx, client)
+
+	case EventTypeResync:
+		return h.handleResync(ctx, client)
+
+	case EventTypeSkipTask:
+		return h.handleChangeTaskStatus(ctx, client, msg, h.taskService.SkipTask)
+
+	case EventTypeDeferTask:
+		return h.handleChangeTaskStatus(ctx, client, msg, h.taskService.DeferTask)
+
+	case EventTypeReopenTask:
+		return h.handleChangeTaskStatus(ctx, client, msg, h.taskService.ReopenTask)
+
+	case EventTypeRaiseHand:
+		return h.handleRaiseHand(ctx, client, msg)
+
+	case EventTypeLowerHand:
+		return h.handleLowerHand(ctx, client, msg)
+
+	case EventTypeUpdateNotes:
+		return h.handleUpdateNotes(ctx, client, msg)
+
+	case EventTypeAddCriterion, EventTypeUpdateCriterion, EventTypeDeleteCriterion:
+		return h.handleCriterion(ctx, client, msg)
+
+	case EventTypeReorderCriteria:
+		return h.handleReorderCriteria(ctx, client, msg)
+
+	case EventTypeSetTaskFilter:
+		return h.handleSetTaskFilter(ctx, client, msg)
+
+	case EventTypeSetTaskParent:
+		return h.handleSetTaskParent(ctx, client, msg)
+
+	case EventTypeAddDependency, EventTypeRemoveDependency:
+		return h.handleDependency(ctx, client, msg)
+
+	case EventTypeProposeTaskOrder:
+		return h.handleProposeTaskOrder(ctx, client)
+
+	case EventTypeAddComment:
+		return h.handleAddComment(ctx, client, msg)
+
+	case EventTypeEditComment:
+		return h.handleEditComment(ctx, client, msg)
+
+	case EventTypeDeleteComment:
+		return h.handleDeleteComment(ctx, client, msg)
+
+	case EventTypeUndo:
+		return h.handleUndo(ctx, client)
 
 	default:
 		return fmt.Errorf("unknown event type: %s", msg.Type)
@@ -178,218 +436,603 @@ func (h *WsHandler) handleVote(ctx context.Context, client *Client, msg WsMessag
 		return fmt.Errorf("invalid vote payload: %w", err)
 	}
 
-	if err := h.votingService.SubmitVote(ctx, client.RoomID, client.UserID, payload.Value); err != nil {
+	req := &dto.SubmitVoteReq{
+		UserID:  client.UserID,
+		Value:   payload.Value,
+		Values:  payload.Values,
+		Comment: payload.Comment,
+	}
+	if payload.ThreePoint != nil {
+		req.ThreePoint = &dto.ThreePointReq{
+			Optimistic:  payload.ThreePoint.Optimistic,
+			MostLikely:  payload.ThreePoint.MostLikely,
+			Pessimistic: payload.ThreePoint.Pessimistic,
+		}
+	}
+
+	return h.castVote(ctx, client.RoomID, req)
+}
+
+func (h *WsHandler) castVote(ctx context.Context, roomID string, req *dto.SubmitVoteReq) error {
+	// Sending no comment clears the one left with a previous vote
+	if err := h.votingService.CastVote(ctx, roomID, req); err != nil {
 		return fmt.Errorf("failed to submit vote: %w", err)
 	}
 
-	roomState, err := h.roomService.GetRoomState(ctx, client.RoomID)
+	// Only the fact that the user voted is public until reveal
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type: EventTypeVoteSubmitted,
+		Payload: VoteSubmittedPayload{
+			UserID:   req.UserID,
+			HasVoted: true,
+		},
+	}, nil)
+
+	// If votes are already revealed, recalculate and broadcast updated results
+	revealed, err := h.roomService.IsRevealed(roomID)
 	if err != nil {
 		return fmt.Errorf("failed to get room state: %w", err)
 	}
+	if revealed {
+		return h.broadcastVotesRevealed(ctx, roomID)
+	}
 
-	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type:    EventTypeRoomState,
-		Payload: h.convertRoomStateToPayload(roomState),
-	}, nil)
+	return nil
+}
 
-	// If votes are already revealed, recalculate and broadcast updated results
-	if roomState.IsRevealed {
-		result, err := h.votingService.RevealVotes(ctx, client.RoomID)
-		if err != nil {
-			return fmt.Errorf("failed to recalculate votes: %w", err)
+func (h *WsHandler) handleReveal(ctx context.Context, client *Client) error {
+	return h.broadcastVotesRevealed(ctx, client.RoomID)
+}
+
+func (h *WsHandler) broadcastVotesRevealed(ctx context.Context, roomID string) error {
+	_, err := h.revealVotes(ctx, roomID)
+	return err
+}
+
+// revealVotes reveals the round's votes to everyone and returns the result
+func (h *WsHandler) revealVotes(ctx context.Context, roomID string) (*dto.RevealVotesResp, error) {
+	result, err := h.votingService.RevealVotes(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reveal votes: %w", err)
+	}
+
+	userNames, err := h.userNames(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	votes := make([]VoteInfo, 0, len(result.Votes)+len(result.Distribution))
+	for i, voteValue := range result.Distribution {
+		vote := VoteInfo{Value: voteValue}
+		if result.DistributionComments != nil {
+			vote.Comment = result.DistributionComments[i]
+		}
+		votes = append(votes, vote)
+	}
+	for userID, voteValue := range result.Votes {
+		vote := VoteInfo{
+			UserID:     userID,
+			Value:      voteValue,
+			UserName:   userNames[userID],
+			Dimensions: dimensionVotesOf(result, userID),
+			Comment:    result.Comments[userID],
+		}
+		if triple, ok := result.ThreePointVotes[userID]; ok {
+			vote.ThreePoint = convertThreePointToPayload(&triple)
 		}
+		votes = append(votes, vote)
+	}
 
-		votes := make([]VoteInfo, 0, len(result.Votes))
-		for userID, voteValue := range result.Votes {
-			userName := ""
-			for _, user := range roomState.Users {
-				if user.UserID == userID {
-					userName = user.Name
-					break
-				}
-			}
-			votes = append(votes, VoteInfo{
-				UserID:   userID,
-				Value:    voteValue,
-				UserName: userName,
-			})
+	var dimensions []DimensionResultPayload
+	for _, dimension := range result.Dimensions {
+		dimensions = append(dimensions, DimensionResultPayload{
+			Key:       dimension.Key,
+			Name:      dimension.Name,
+			Average:   dimension.Average,
+			Suggested: dimension.Suggested,
+		})
+	}
+
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type: EventTypeVotesRevealed,
+		Payload: VotesRevealedPayload{
+			Votes:      votes,
+			Average:    result.Average,
+			Suggested:  result.Suggested,
+			Dimensions: dimensions,
+			Pert:       convertPertToPayload(result.Pert),
+			Anonymous:  result.Anonymous,
+			Changes:    convertVoteChangesToPayload(result.NamedChanges(userNames)),
+		},
+	}, nil)
+
+	return result, nil
+}
+
+// dimensionVotesOf collects a user's per-dimension votes from a reveal result
+func dimensionVotesOf(result *dto.RevealVotesResp, userID string) map[string]string {
+	if len(result.Dimensions) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(result.Dimensions))
+	for _, dimension := range result.Dimensions {
+		if value, ok := dimension.Votes[userID]; ok {
+			values[dimension.Key] = value
 		}
+	}
+	return values
+}
 
-		h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-			Type: EventTypeVotesRevealed,
-			Payload: VotesRevealedPayload{
-				Votes:   votes,
-				Average: result.Average,
-			},
-		}, nil)
+// userNames maps the IDs of the users currently in the room to their nicknames
+func (h *WsHandler) userNames(ctx context.Context, roomID string) (map[string]string, error) {
+	state, err := h.roomService.GetRoomState(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room state: %w", err)
+	}
+
+	userNames := make(map[string]string, len(state.Users))
+	for _, user := range state.Users {
+		userNames[user.UserID] = user.Name
+	}
+	return userNames, nil
+}
+
+// handleClear resets the round for a re-vote on the same task without saving anything
+func (h *WsHandler) handleClear(ctx context.Context, client *Client) error {
+	return h.clearRound(ctx, client.RoomID, client.UserID)
+}
+
+// clearRound resets the round as an undoable change made by userID
+func (h *WsHandler) clearRound(ctx context.Context, roomID, userID string) error {
+	change, err := h.undoService.PrepareClear(ctx, roomID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear votes: %w", err)
 	}
 
+	if err := h.votingService.ClearVotes(ctx, roomID); err != nil {
+		return fmt.Errorf("failed to clear votes: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type:    EventTypeRoundReset,
+		Payload: RoundResetPayload{ActiveTaskID: h.activeTaskID(roomID)},
+	}, nil)
+
+	h.recordChange(roomID, change)
 	return nil
 }
 
-func (h *WsHandler) handleReveal(ctx context.Context, client *Client) error {
+// handleAcceptEstimate saves the final value to the active task (or the next open task
+// when none is active), then starts the next round. Only the facilitator may override the suggestion.
+func (h *WsHandler) handleAcceptEstimate(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload AcceptEstimatePayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid accept estimate payload: %w", err)
+	}
+
+	revealed, err := h.roomService.IsRevealed(client.RoomID)
+	if err != nil {
+		return fmt.Errorf("failed to get room state: %w", err)
+	}
+	if !revealed {
+		return room.ErrVotesNotRevealed
+	}
+
 	result, err := h.votingService.RevealVotes(ctx, client.RoomID)
 	if err != nil {
-		return fmt.Errorf("failed to reveal votes: %w", err)
+		return fmt.Errorf("failed to get vote result: %w", err)
 	}
 
-	state, err := h.roomService.GetRoomState(ctx, client.RoomID)
+	userNames, err := h.userNames(ctx, client.RoomID)
+	if err != nil {
+		return err
+	}
+	participants := make([]string, 0, len(result.Votes))
+	for _, userID := range result.VoterIDs() {
+		participants = append(participants, userNames[userID])
+	}
+	sort.Strings(participants)
+
+	breakdown := payload.Breakdown
+	if breakdown == nil {
+		breakdown = result.Breakdown()
+	}
+
+	byFacilitator, err := h.roomService.IsFacilitator(client.RoomID, client.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to get room state: %w", err)
 	}
 
-	votes := make([]VoteInfo, 0, len(result.Votes))
-	for userID, voteValue := range result.Votes {
-		userName := ""
-		for _, user := range state.Users {
-			if user.UserID == userID {
-				userName = user.Name
-				break
-			}
+	activeTaskID := h.activeTaskID(client.RoomID)
+	change, err := h.undoService.PrepareAcceptEstimate(ctx, client.RoomID, client.UserID, activeTaskID)
+	if err != nil {
+		return fmt.Errorf("failed to accept estimate: %w", err)
+	}
+
+	estimatedTask, err := h.taskService.AcceptEstimate(ctx, client.RoomID, &dto.AcceptEstimateReq{
+		TaskID:        activeTaskID,
+		Value:         payload.Value,
+		Suggested:     result.Suggested,
+		Reason:        payload.Reason,
+		ByFacilitator: byFacilitator,
+		Participants:  participants,
+		Breakdown:     breakdown,
+		Pert:          result.Pert,
+		Comments:      result.VoteComments(userNames),
+		Changes:       result.Changes,
+		VoterNames:    result.VoterNames(userNames),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to accept estimate: %w", err)
+	}
+
+	if err := h.votingService.ClearVotes(ctx, client.RoomID); err != nil {
+		return fmt.Errorf("failed to clear votes: %w", err)
+	}
+
+	nextTask, err := h.taskService.NextTask(ctx, client.RoomID, estimatedTask.ID)
+	if err != nil && !errors.Is(err, room.ErrNoOpenTasks) {
+		log.Printf("Warning: failed to find next task: %v", err)
+	}
+
+	taskPayload := convertTaskToPayload(estimatedTask)
+	resetPayload := RoundResetPayload{EstimatedTask: &taskPayload}
+	if nextTask != nil {
+		resetPayload.ActiveTaskID = nextTask.ID
+	}
+	resetPayload.ActiveTask = h.activateTask(ctx, client.RoomID, resetPayload.ActiveTaskID)
+
+	// A single transition message: clients reset votes locally and apply the task changes
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeRoundReset,
+		Payload: resetPayload,
+	}, nil)
+
+	h.recordChange(client.RoomID, change)
+	return nil
+}
+
+// handleUndo reverts the room's latest change and resends whatever it brought back
+func (h *WsHandler) handleUndo(ctx context.Context, client *Client) error {
+	result, err := h.undoService.Undo(ctx, client.RoomID, client.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to undo: %w", err)
+	}
+
+	if result.RestoredTasks {
+		if err := h.broadcastTaskListSync(ctx, client.RoomID); err != nil {
+			return err
+		}
+	}
+	if result.RestoredRound {
+		if err := h.broadcastRound(ctx, client.RoomID); err != nil {
+			return err
 		}
-		votes = append(votes, VoteInfo{
-			UserID:   userID,
-			Value:    voteValue,
-			UserName: userName,
-		})
 	}
 
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type: EventTypeVotesRevealed,
-		Payload: VotesRevealedPayload{
-			Votes:   votes,
-			Average: result.Average,
+		Type: EventTypeUndoUpdated,
+		Payload: UndoUpdatedPayload{
+			LastChange: convertChangeToPayload(result.LastChange),
+			Undone:     convertChangeToPayload(&result.Undone),
 		},
 	}, nil)
 
 	return nil
 }
 
-func (h *WsHandler) handleClear(ctx context.Context, client *Client) error {
-	// Get current state to check for votes
-	state, err := h.roomService.GetRoomState(ctx, client.RoomID)
+// broadcastRound sends a restored round to everyone: its active task first, as that resets
+// the round on the clients, then the votes, revealed again if the round had been revealed
+func (h *WsHandler) broadcastRound(ctx context.Context, roomID string) error {
+	taskID := h.activeTaskID(roomID)
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type: EventTypeActiveTaskSet,
+		Payload: ActiveTaskSetPayload{
+			TaskID: taskID,
+			Task:   h.activateTask(ctx, roomID, taskID),
+		},
+	}, nil)
+
+	roomState, err := h.roomService.GetRoomState(ctx, roomID)
 	if err != nil {
 		return fmt.Errorf("failed to get room state: %w", err)
 	}
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type:    EventTypeRoomState,
+		Payload: h.convertRoomStateToPayload(roomState),
+	}, nil)
 
-	// Save estimation to active task if votes were cast and revealed
-	taskUpdated := false
-	if state.IsRevealed && len(state.Votes) > 0 {
-		// Calculate result to determine estimation
-		result, err := h.votingService.RevealVotes(ctx, client.RoomID)
-		if err != nil {
-			log.Printf("Warning: failed to get vote result: %v", err)
-		} else {
-			estimation := h.determineEstimation(result)
-
-			// Get the active task ID from room state
-			activeTaskID, err := h.roomService.GetActiveTask(client.RoomID)
-			if err != nil {
-				log.Printf("Warning: failed to get active task: %v", err)
-			}
+	if roomState.IsRevealed {
+		return h.broadcastVotesRevealed(ctx, roomID)
+	}
+	return nil
+}
 
-			// Save estimation to the active task if set, otherwise fallback to next unestimated
-			if activeTaskID != "" {
-				if err := h.taskService.SaveEstimationToTask(ctx, activeTaskID, estimation); err != nil {
-					log.Printf("Warning: failed to save estimation to active task: %v", err)
-				} else {
-					taskUpdated = true
-				}
-			} else {
-				if err := h.taskService.SaveEstimation(ctx, client.RoomID, estimation); err != nil {
-					log.Printf("Warning: failed to save estimation: %v", err)
-				} else {
-					taskUpdated = true
-				}
-			}
-		}
+// recordChange makes an applied change undoable and tells the room what undo would now revert.
+// The change has already been made, so failing to record it is only logged.
+func (h *WsHandler) recordChange(roomID string, change *application.Change) {
+	lastChange, err := h.undoService.Record(roomID, change)
+	if err != nil {
+		log.Printf("Warning: failed to record change: %v", err)
+		return
 	}
 
-	// Clear votes
-	if err := h.votingService.ClearVotes(ctx, client.RoomID); err != nil {
-		return fmt.Errorf("failed to clear votes: %w", err)
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type:    EventTypeUndoUpdated,
+		Payload: UndoUpdatedPayload{LastChange: convertChangeToPayload(lastChange)},
+	}, nil)
+}
+
+func (h *WsHandler) handleUpdateNickname(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload UpdateNicknamePayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid nickname payload: %w", err)
+	}
+
+	return h.renameUser(ctx, client.RoomID, client.UserID, payload.Nickname)
+}
+
+func (h *WsHandler) renameUser(ctx context.Context, roomID, userID, nickname string) error {
+	if err := h.userService.UpdateUserName(ctx, roomID, userID, nickname); err != nil {
+		return fmt.Errorf("failed to update nickname: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type: EventTypeUserUpdated,
+		Payload: UserUpdatedPayload{
+			UserID:   userID,
+			Nickname: nickname,
+		},
+	}, nil)
+
+	return nil
+}
+
+func (h *WsHandler) handleRaiseHand(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload RaiseHandPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid raise hand payload: %w", err)
+	}
+
+	if err := h.userService.RaiseHand(ctx, client.RoomID, client.UserID, payload.Signal); err != nil {
+		return fmt.Errorf("failed to raise hand: %w", err)
 	}
 
-	// Move to next unestimated task
-	nextTask, err := h.taskService.GetNextUnestimatedTask(ctx, client.RoomID)
+	return h.broadcastHands(ctx, client.RoomID)
+}
+
+func (h *WsHandler) handleLowerHand(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload LowerHandPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid lower hand payload: %w", err)
+	}
+
+	userID := payload.UserID
+	if userID == "" {
+		userID = client.UserID
+	}
+	if err := h.userService.LowerHand(ctx, client.RoomID, client.UserID, userID); err != nil {
+		return fmt.Errorf("failed to lower hand: %w", err)
+	}
+
+	return h.broadcastHands(ctx, client.RoomID)
+}
+
+func (h *WsHandler) broadcastHands(ctx context.Context, roomID string) error {
+	hands, err := h.userService.GetHandQueue(ctx, roomID)
 	if err != nil {
-		log.Printf("Warning: failed to get next unestimated task: %v", err)
+		return fmt.Errorf("failed to get hand queue: %w", err)
 	}
 
-	// Get updated room state
-	newRoomState, err := h.roomService.GetRoomState(ctx, client.RoomID)
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type:    EventTypeHandsUpdated,
+		Payload: HandsUpdatedPayload{Hands: convertHandsToPayload(hands)},
+	}, nil)
+
+	return nil
+}
+
+// handleUpdateNotes records a new revision of a task's decision log under the editor's nickname.
+// Only the facilitator may edit the log.
+func (h *WsHandler) handleUpdateNotes(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload UpdateNotesPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid update notes payload: %w", err)
+	}
+
+	byFacilitator, err := h.roomService.IsFacilitator(client.RoomID, client.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to get room state: %w", err)
 	}
 
-	// Get updated task list to reflect saved estimations
-	tasks, err := h.taskService.GetRoomTasks(ctx, client.RoomID)
+	if payload.TaskID == "" {
+		payload.TaskID = h.activeTaskID(client.RoomID)
+	}
+
+	userNames, err := h.userNames(ctx, client.RoomID)
+	if err != nil {
+		return err
+	}
+
+	task, err := h.taskService.UpdateNotes(ctx, client.RoomID, payload.TaskID, userNames[client.UserID], payload.Notes, byFacilitator)
+	if err != nil {
+		return fmt.Errorf("failed to update notes: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeTaskUpdated,
+		Payload: convertTaskToPayload(task),
+	}, nil)
+
+	return nil
+}
+
+// handleCriterion adds, edits or removes one acceptance criterion of a task
+func (h *WsHandler) handleCriterion(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload CriterionPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid criterion payload: %w", err)
+	}
+
+	if payload.TaskID == "" {
+		payload.TaskID = h.activeTaskID(client.RoomID)
+	}
+
+	var task *dto.TaskResp
+	var err error
+	switch msg.Type {
+	case EventTypeAddCriterion:
+		var text string
+		if payload.Text != nil {
+			text = *payload.Text
+		}
+		task, err = h.taskService.AddCriterion(ctx, client.RoomID, payload.TaskID, text)
+	case EventTypeUpdateCriterion:
+		task, err = h.taskService.UpdateCriterion(ctx, client.RoomID, payload.TaskID, payload.CriterionID, &dto.UpdateCriterionReq{
+			Text: payload.Text,
+			Done: payload.Done,
+		})
+	default:
+		task, err = h.taskService.DeleteCriterion(ctx, client.RoomID, payload.TaskID, payload.CriterionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to change acceptance criteria: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeTaskUpdated,
+		Payload: convertTaskToPayload(task),
+	}, nil)
+
+	return nil
+}
+
+func (h *WsHandler) handleReorderCriteria(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload ReorderCriteriaPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid reorder criteria payload: %w", err)
+	}
+
+	if payload.TaskID == "" {
+		payload.TaskID = h.activeTaskID(client.RoomID)
+	}
+
+	task, err := h.taskService.ReorderCriteria(ctx, client.RoomID, payload.TaskID, payload.CriterionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to reorder acceptance criteria: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeTaskUpdated,
+		Payload: convertTaskToPayload(task),
+	}, nil)
+
+	return nil
+}
+
+// handleSetTaskFilter saves the filter that restricts which tasks "next" picks
+func (h *WsHandler) handleSetTaskFilter(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload TaskFilterPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid task filter payload: %w", err)
+	}
+
+	_, err := h.setTaskFilter(ctx, client.RoomID, dto.TaskFilterReq{
+		Labels:   payload.Labels,
+		Types:    payload.Types,
+		Statuses: payload.Statuses,
+	})
+	return err
+}
+
+func (h *WsHandler) setTaskFilter(ctx context.Context, roomID string, req dto.TaskFilterReq) (*dto.RoomResp, error) {
+	rm, err := h.roomService.SetTaskFilter(ctx, roomID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set task filter: %w", err)
+	}
+
+	saved := TaskFilterPayload{}
+	if rm.TaskFilter != nil {
+		saved = TaskFilterPayload{
+			Labels:   rm.TaskFilter.Labels,
+			Types:    rm.TaskFilter.Types,
+			Statuses: rm.TaskFilter.Statuses,
+		}
+	}
+
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type:    EventTypeTaskFilterSet,
+		Payload: saved,
+	}, nil)
+
+	return rm, nil
+}
+
+// handleAddComment posts to a task's thread under the author's current nickname
+func (h *WsHandler) handleAddComment(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload AddCommentPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid add comment payload: %w", err)
+	}
+
+	if payload.TaskID == "" {
+		payload.TaskID = h.activeTaskID(client.RoomID)
+	}
+
+	userNames, err := h.userNames(ctx, client.RoomID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := h.commentService.AddComment(ctx, client.RoomID, payload.TaskID, client.UserID, userNames[client.UserID], payload.Body)
 	if err != nil {
-		log.Printf("Warning: failed to get tasks after clear: %v", err)
+		return fmt.Errorf("failed to add comment: %w", err)
 	}
 
-	// Broadcast votes cleared event
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type:    EventTypeVotesCleared,
-		Payload: VotesClearedPayload{},
+		Type:    EventTypeCommentAdded,
+		Payload: comment,
 	}, nil)
 
-	// Broadcast room state
-	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type:    EventTypeRoomState,
-		Payload: h.convertRoomStateToPayload(newRoomState),
-	}, nil)
+	return nil
+}
 
-	// Broadcast updated task list to show saved estimations
-	if taskUpdated || tasks != nil {
-		if tasks == nil {
-			tasks, err = h.taskService.GetRoomTasks(ctx, client.RoomID)
-			if err != nil {
-				log.Printf("Warning: failed to get tasks for broadcast: %v", err)
-			}
-		}
-		if tasks != nil {
-			taskPayloads := make([]TaskPayload, len(tasks))
-			for i, task := range tasks {
-				taskPayloads[i] = convertTaskToPayload(task)
-			}
-			h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-				Type: EventTypeTaskListSync,
-				Payload: TaskListSyncPayload{
-					Tasks: taskPayloads,
-				},
-			}, nil)
-		}
+func (h *WsHandler) handleEditComment(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload EditCommentPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid edit comment payload: %w", err)
 	}
 
-	// If there's a next task, broadcast that too
-	if nextTask != nil {
-		h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-			Type: EventTypeActiveTaskSet,
-			Payload: SetActiveTaskPayload{
-				TaskID: nextTask.ID,
-			},
-		}, nil)
+	comment, err := h.commentService.EditComment(ctx, client.RoomID, payload.CommentID, client.UserID, payload.Body)
+	if err != nil {
+		return fmt.Errorf("failed to edit comment: %w", err)
 	}
 
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeCommentUpdated,
+		Payload: comment,
+	}, nil)
+
 	return nil
 }
 
-func (h *WsHandler) handleUpdateNickname(ctx context.Context, client *Client, msg WsMessage) error {
-	var payload UpdateNicknamePayload
+func (h *WsHandler) handleDeleteComment(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload DeleteCommentPayload
 	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
-		return fmt.Errorf("invalid nickname payload: %w", err)
+		return fmt.Errorf("invalid delete comment payload: %w", err)
 	}
 
-	if err := h.userService.UpdateUserName(ctx, client.RoomID, client.UserID, payload.Nickname); err != nil {
-		return fmt.Errorf("failed to update nickname: %w", err)
+	comment, err := h.commentService.DeleteComment(ctx, client.RoomID, payload.CommentID, client.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
 	}
 
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type: EventTypeUserUpdated,
-		Payload: UserUpdatedPayload{
-			UserID:   client.UserID,
-			Nickname: payload.Nickname,
+		Type: EventTypeCommentDeleted,
+		Payload: CommentDeletedPayload{
+			CommentID: comment.ID,
+			TaskID:    comment.TaskID,
 		},
 	}, nil)
 
@@ -406,39 +1049,35 @@ func (h *WsHandler) handleSetTask(ctx context.Context, client *Client, msg WsMes
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
-	roomState, err := h.roomService.GetRoomState(ctx, client.RoomID)
-	if err != nil {
-		return fmt.Errorf("failed to get room state: %w", err)
-	}
-
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type:    EventTypeRoomState,
-		Payload: h.convertRoomStateToPayload(roomState),
+		Type:    EventTypeTaskDescriptionSet,
+		Payload: payload,
 	}, nil)
 
 	return nil
 }
 
-func (h *WsHandler) sendRoomState(client *Client) error {
-	ctx := context.Background()
+// handleResync sends a fresh snapshot to the requesting client only, for clients
+// that suspect they missed a delta (e.g. after a long background tab)
+func (h *WsHandler) handleResync(ctx context.Context, client *Client) error {
+	if _, err := h.sendRoomState(ctx, client); err != nil {
+		return err
+	}
+	return h.sendTaskListSync(client)
+}
 
+// sendRoomState sends a full snapshot to a single client and returns it
+func (h *WsHandler) sendRoomState(ctx context.Context, client *Client) (*dto.RoomStateResp, error) {
 	roomState, err := h.roomService.GetRoomState(ctx, client.RoomID)
 	if err != nil {
-		return fmt.Errorf("failed to get room state: %w", err)
+		return nil, fmt.Errorf("failed to get room state: %w", err)
 	}
 
-	payload := h.convertRoomStateToPayload(roomState)
-
-	data, err := json.Marshal(WsMessage{
+	client.Send(WsMessage{
 		Type:    EventTypeRoomState,
-		Payload: payload,
+		Payload: h.convertRoomStateToPayload(roomState),
 	})
-	if err != nil {
-		return fmt.Errorf("failed to marshal room state: %w", err)
-	}
-
-	client.send <- data
-	return nil
+	return roomState, nil
 }
 
 func (h *WsHandler) convertRoomStateToPayload(state *dto.RoomStateResp) RoomStatePayload {
@@ -449,15 +1088,21 @@ func (h *WsHandler) convertRoomStateToPayload(state *dto.RoomStateResp) RoomStat
 			Name:     user.Name,
 			IsVoted:  user.IsVoted,
 			IsOnline: user.IsOnline,
+
+			RaisedHand:   user.RaisedHand,
+			HandPosition: user.HandPosition,
 		}
 	}
 
 	votes := make([]VoteInfo, len(state.Votes))
 	for i, vote := range state.Votes {
 		votes[i] = VoteInfo{
-			UserID:   vote.UserID,
-			Value:    vote.Value,
-			UserName: vote.UserName,
+			UserID:     vote.UserID,
+			Value:      vote.Value,
+			UserName:   vote.UserName,
+			Dimensions: vote.Dimensions,
+			ThreePoint: convertThreePointToPayload(vote.ThreePoint),
+			Comment:    vote.Comment,
 		}
 	}
 
@@ -469,6 +1114,8 @@ func (h *WsHandler) convertRoomStateToPayload(state *dto.RoomStateResp) RoomStat
 		IsRevealed:      state.IsRevealed,
 		TaskDescription: state.TaskDescription,
 		Average:         state.Average,
+		FacilitatorID:   state.FacilitatorID,
+		LastChange:      convertChangeToPayload(state.LastChange),
 	}
 }
 
@@ -491,6 +1138,9 @@ func (h *WsHandler) handleCreateTask(ctx context.Context, client *Client, msg Ws
 		Headline:    payload.Headline,
 		Description: payload.Description,
 		TrackerLink: payload.TrackerLink,
+		Type:        payload.Type,
+		Labels:      payload.Labels,
+		ParentID:    payload.ParentID,
 	}
 
 	task, err := h.taskService.CreateTask(ctx, client.RoomID, req)
@@ -498,6 +1148,11 @@ func (h *WsHandler) handleCreateTask(ctx context.Context, client *Client, msg Ws
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
+	// A subtask shifts the tasks after its epic, resend the whole backlog
+	if task.ParentID != "" {
+		return h.broadcastTaskListSync(ctx, client.RoomID)
+	}
+
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
 		Type:    EventTypeTaskCreated,
 		Payload: convertTaskToPayload(task),
@@ -513,12 +1168,29 @@ func (h *WsHandler) handleUpdateTask(ctx context.Context, client *Client, msg Ws
 	}
 
 	req := &dto.UpdateTaskReq{
+		Version:     payload.Version,
 		Headline:    payload.Headline,
 		Description: payload.Description,
 		TrackerLink: payload.TrackerLink,
+		Type:        payload.Type,
+		Labels:      payload.Labels,
 	}
 
-	task, err := h.taskService.UpdateTask(ctx, payload.TaskID, req)
+	task, err := h.taskService.UpdateTask(ctx, client.RoomID, payload.TaskID, req)
+	var conflict *application.TaskConflictError
+	if errors.As(err, &conflict) {
+		// Only the editor needs to merge, the room already has the current task
+		current := convertTaskToPayload(conflict.Current)
+		client.Send(WsMessage{
+			Type: EventTypeError,
+			Payload: ErrorPayload{
+				Message: err.Error(),
+				Code:    ErrCodeConflict,
+				Task:    &current,
+			},
+		})
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -537,10 +1209,23 @@ func (h *WsHandler) handleDeleteTask(ctx context.Context, client *Client, msg Ws
 		return fmt.Errorf("invalid delete task payload: %w", err)
 	}
 
-	if err := h.taskService.DeleteTask(ctx, payload.TaskID); err != nil {
+	change, err := h.undoService.PrepareDelete(ctx, client.RoomID, client.UserID, payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	moved, err := h.taskService.DeleteTask(ctx, client.RoomID, payload.TaskID)
+	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 
+	for _, task := range moved {
+		h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+			Type:    EventTypeTaskUpdated,
+			Payload: convertTaskToPayload(task),
+		}, nil)
+	}
+
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
 		Type: EventTypeTaskDeleted,
 		Payload: fiber.Map{
@@ -548,6 +1233,7 @@ func (h *WsHandler) handleDeleteTask(ctx context.Context, client *Client, msg Ws
 		},
 	}, nil)
 
+	h.recordChange(client.RoomID, change)
 	return nil
 }
 
@@ -561,43 +1247,319 @@ func (h *WsHandler) handleReorderTasks(ctx context.Context, client *Client, msg
 		TaskIDs: payload.TaskIDs,
 	}
 
-	if err := h.taskService.ReorderTasks(ctx, client.RoomID, req); err != nil {
+	change, err := h.undoService.PrepareReorder(ctx, client.RoomID, client.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to reorder tasks: %w", err)
+	}
+
+	taskIDs, err := h.taskService.ReorderTasks(ctx, client.RoomID, req)
+	if err != nil {
 		return fmt.Errorf("failed to reorder tasks: %w", err)
 	}
 
+	// The saved order may differ from the requested one, subtasks stay with their epic
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
 		Type:    EventTypeTasksReordered,
-		Payload: payload,
+		Payload: ReorderTasksPayload{TaskIDs: taskIDs},
+	}, nil)
+
+	h.recordChange(client.RoomID, change)
+	return nil
+}
+
+// handleMoveTask saves a single move; the moved task is sent again as it may have changed epic
+func (h *WsHandler) handleMoveTask(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload MoveTaskPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid move payload: %w", err)
+	}
+
+	req := &dto.MoveTaskReq{
+		TaskID:   payload.TaskID,
+		BeforeID: payload.BeforeID,
+		AfterID:  payload.AfterID,
+	}
+
+	change, err := h.undoService.PrepareMove(ctx, client.RoomID, client.UserID, payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to move task: %w", err)
+	}
+
+	task, taskIDs, err := h.taskService.MoveTask(ctx, client.RoomID, req)
+	if err != nil {
+		return fmt.Errorf("failed to move task: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeTaskUpdated,
+		Payload: convertTaskToPayload(task),
+	}, nil)
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeTasksReordered,
+		Payload: ReorderTasksPayload{TaskIDs: taskIDs},
+	}, nil)
+
+	h.recordChange(client.RoomID, change)
+	return nil
+}
+
+func (h *WsHandler) handleDependency(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload DependencyPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid dependency payload: %w", err)
+	}
+
+	if payload.TaskID == "" {
+		payload.TaskID = h.activeTaskID(client.RoomID)
+	}
+
+	var task *dto.TaskResp
+	var err error
+	if msg.Type == EventTypeAddDependency {
+		task, err = h.taskService.AddDependency(ctx, client.RoomID, payload.TaskID, payload.DependsOnID)
+	} else {
+		task, err = h.taskService.RemoveDependency(ctx, client.RoomID, payload.TaskID, payload.DependsOnID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to change task dependencies: %w", err)
+	}
+
+	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
+		Type:    EventTypeTaskUpdated,
+		Payload: convertTaskToPayload(task),
 	}, nil)
 
 	return nil
 }
 
-func (h *WsHandler) handleSetActiveTask(ctx context.Context, client *Client, msg WsMessage) error {
+// handleProposeTaskOrder answers the requester only; the order is applied with reorder_tasks
+func (h *WsHandler) handleProposeTaskOrder(ctx context.Context, client *Client) error {
+	taskIDs, err := h.taskService.ProposeTaskOrder(ctx, client.RoomID)
+	if err != nil {
+		return fmt.Errorf("failed to propose task order: %w", err)
+	}
+
+	client.Send(WsMessage{
+		Type:    EventTypeTaskOrderProposed,
+		Payload: ReorderTasksPayload{TaskIDs: taskIDs},
+	})
+
+	return nil
+}
+
+func (h *WsHandler) handleSetTaskParent(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload SetTaskParentPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid set task parent payload: %w", err)
+	}
+
+	change, err := h.undoService.PrepareMove(ctx, client.RoomID, client.UserID, payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to set task parent: %w", err)
+	}
+
+	if _, err := h.taskService.SetTaskParent(ctx, client.RoomID, payload.TaskID, payload.ParentID); err != nil {
+		return fmt.Errorf("failed to set task parent: %w", err)
+	}
+
+	if err := h.broadcastTaskListSync(ctx, client.RoomID); err != nil {
+		return err
+	}
+	h.recordChange(client.RoomID, change)
+	return nil
+}
+
+// handleJumpToTask makes any task of the room's backlog the active one
+func (h *WsHandler) handleJumpToTask(ctx context.Context, client *Client, msg WsMessage) error {
 	var payload SetActiveTaskPayload
 	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
 		return fmt.Errorf("invalid set active task payload: %w", err)
 	}
 
-	// Set the active task in room state
-	if err := h.roomService.SetActiveTask(client.RoomID, payload.TaskID); err != nil {
+	task, err := h.taskService.GetRoomTask(ctx, client.RoomID, payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to find task: %w", err)
+	}
+
+	return h.navigateTo(ctx, client.RoomID, task.ID)
+}
+
+// handleReestimateTask reopens an estimated task and starts a fresh round on it
+func (h *WsHandler) handleReestimateTask(ctx context.Context, client *Client, msg WsMessage) error {
+	var payload TaskStatusPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid reestimate payload: %w", err)
+	}
+	if payload.TaskID == "" {
+		payload.TaskID = h.activeTaskID(client.RoomID)
+	}
+
+	if _, err := h.taskService.ReestimateTask(ctx, client.RoomID, payload.TaskID); err != nil {
+		return fmt.Errorf("failed to reopen task: %w", err)
+	}
+
+	return h.navigateTo(ctx, client.RoomID, payload.TaskID)
+}
+
+func (h *WsHandler) handleNextTask(ctx context.Context, client *Client) error {
+	task, err := h.taskService.NextTask(ctx, client.RoomID, h.activeTaskID(client.RoomID))
+	if err != nil {
+		return fmt.Errorf("failed to move to next task: %w", err)
+	}
+
+	return h.navigateTo(ctx, client.RoomID, task.ID)
+}
+
+func (h *WsHandler) handlePreviousTask(ctx context.Context, client *Client) error {
+	task, err := h.taskService.PreviousTask(ctx, client.RoomID, h.activeTaskID(client.RoomID))
+	if err != nil {
+		return fmt.Errorf("failed to move to previous task: %w", err)
+	}
+
+	return h.navigateTo(ctx, client.RoomID, task.ID)
+}
+
+// navigateTo clears the current round and makes taskID (or no task, when empty)
+// the active one, announced by a single active_task_set with the task details
+func (h *WsHandler) navigateTo(ctx context.Context, roomID, taskID string) error {
+	if err := h.votingService.ClearVotes(ctx, roomID); err != nil {
+		return fmt.Errorf("failed to clear votes: %w", err)
+	}
+
+	task := h.activateTask(ctx, roomID, taskID)
+
+	h.hub.BroadcastToRoom(roomID, WsMessage{
+		Type: EventTypeActiveTaskSet,
+		Payload: ActiveTaskSetPayload{
+			TaskID: taskID,
+			Task:   task,
+		},
+	}, nil)
+
+	return nil
+}
+
+// activateTask stores taskID as the room's active task and moves it into discussion.
+// It returns the active task's details (nil when there is none); status changes
+// of other tasks are broadcast as task_updated.
+func (h *WsHandler) activateTask(ctx context.Context, roomID, taskID string) *TaskPayload {
+	if err := h.roomService.SetActiveTask(roomID, taskID); err != nil {
 		log.Printf("Warning: failed to set active task: %v", err)
 	}
 
+	changed, err := h.taskService.StartDiscussion(ctx, roomID, taskID)
+	if err != nil {
+		log.Printf("Warning: failed to start discussion of task %s: %v", taskID, err)
+	}
+
+	var active *TaskPayload
+	for _, task := range changed {
+		payload := convertTaskToPayload(task)
+		if task.ID == taskID {
+			active = &payload
+			continue
+		}
+		h.hub.BroadcastToRoom(roomID, WsMessage{
+			Type:    EventTypeTaskUpdated,
+			Payload: payload,
+		}, nil)
+	}
+
+	if active == nil && taskID != "" {
+		task, err := h.taskService.GetRoomTask(ctx, roomID, taskID)
+		if err != nil {
+			log.Printf("Warning: failed to get active task %s: %v", taskID, err)
+			return nil
+		}
+		payload := convertTaskToPayload(task)
+		active = &payload
+	}
+
+	return active
+}
+
+func (h *WsHandler) activeTaskID(roomID string) string {
+	taskID, err := h.roomService.GetActiveTask(roomID)
+	if err != nil {
+		log.Printf("Warning: failed to get active task: %v", err)
+	}
+	return taskID
+}
+
+// handleChangeTaskStatus applies a skip, defer or reopen. When the active task
+// leaves the queue, the room moves on to the next open task.
+func (h *WsHandler) handleChangeTaskStatus(
+	ctx context.Context,
+	client *Client,
+	msg WsMessage,
+	change func(ctx context.Context, roomID, taskID string) (*dto.TaskResp, error),
+) error {
+	var payload TaskStatusPayload
+	if err := unmarshalPayload(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid task status payload: %w", err)
+	}
+
+	activeTaskID := h.activeTaskID(client.RoomID)
+	if payload.TaskID == "" {
+		payload.TaskID = activeTaskID
+	}
+
+	task, err := change(ctx, client.RoomID, payload.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to change task status: %w", err)
+	}
+
 	h.hub.BroadcastToRoom(client.RoomID, WsMessage{
-		Type:    EventTypeActiveTaskSet,
-		Payload: payload,
+		Type:    EventTypeTaskUpdated,
+		Payload: convertTaskToPayload(task),
 	}, nil)
 
-	return nil
+	if activeTaskID != task.ID || room.TaskStatus(task.Status).IsOpen() {
+		return nil
+	}
+
+	var nextTaskID string
+	next, err := h.taskService.NextTask(ctx, client.RoomID, task.ID)
+	if err != nil && !errors.Is(err, room.ErrNoOpenTasks) {
+		log.Printf("Warning: failed to find next task: %v", err)
+	}
+	if next != nil {
+		nextTaskID = next.ID
+	}
+
+	return h.navigateTo(ctx, client.RoomID, nextTaskID)
 }
 
 func (h *WsHandler) sendTaskListSync(client *Client) error {
-	ctx := context.Background()
+	msg, err := h.taskListSync(context.Background(), client.RoomID)
+	if err != nil {
+		return err
+	}
+
+	client.Send(msg)
+	return nil
+}
+
+// broadcastTaskListSync resends the backlog to everyone after changes that move many tasks at once
+func (h *WsHandler) broadcastTaskListSync(ctx context.Context, roomID string) error {
+	msg, err := h.taskListSync(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	h.hub.BroadcastToRoom(roomID, msg, nil)
+	return nil
+}
 
-	tasks, err := h.taskService.GetRoomTasks(ctx, client.RoomID)
+func (h *WsHandler) taskListSync(ctx context.Context, roomID string) (WsMessage, error) {
+	tasks, err := h.taskService.GetRoomTasks(ctx, roomID, dto.TaskFilterReq{})
 	if err != nil {
-		return fmt.Errorf("failed to get tasks: %w", err)
+		return WsMessage{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	tree, err := h.taskService.GetRoomTaskTree(ctx, roomID)
+	if err != nil {
+		return WsMessage{}, fmt.Errorf("failed to get task tree: %w", err)
 	}
 
 	taskPayloads := make([]TaskPayload, len(tasks))
@@ -605,20 +1567,24 @@ func (h *WsHandler) sendTaskListSync(client *Client) error {
 		taskPayloads[i] = convertTaskToPayload(task)
 	}
 
-	payload := TaskListSyncPayload{
-		Tasks: taskPayloads,
-	}
+	return WsMessage{
+		Type: EventTypeTaskListSync,
+		Payload: TaskListSyncPayload{
+			Tasks: taskPayloads,
+			Tree:  convertTaskNodesToPayload(tree),
+		},
+	}, nil
+}
 
-	data, err := json.Marshal(WsMessage{
-		Type:    EventTypeTaskListSync,
-		Payload: payload,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to marshal task list: %w", err)
+func convertTaskNodesToPayload(nodes []*dto.TaskNodeResp) []TaskNodePayload {
+	payloads := make([]TaskNodePayload, len(nodes))
+	for i, node := range nodes {
+		payloads[i] = TaskNodePayload{
+			TaskPayload: convertTaskToPayload(node.TaskResp),
+			Subtasks:    convertTaskNodesToPayload(node.Subtasks),
+		}
 	}
-
-	client.send <- data
-	return nil
+	return payloads
 }
 
 func convertTaskToPayload(task *dto.TaskResp) TaskPayload {
@@ -629,44 +1595,136 @@ func convertTaskToPayload(task *dto.TaskResp) TaskPayload {
 		Description: task.Description,
 		TrackerLink: task.TrackerLink,
 		Estimation:  task.Estimation,
+		Status:      task.Status,
 		Position:    task.Position,
+		Version:     task.Version,
+
+		ParentID: task.ParentID,
+		Rollup:   convertRollupToPayload(task.Rollup),
+
+		DependsOn: task.DependsOn,
+
+		Type:   task.Type,
+		Labels: task.Labels,
+
+		EstimationOverridden: task.EstimationOverridden,
+		OverrideReason:       task.OverrideReason,
+		PreviousEstimation:   task.PreviousEstimation,
+
+		EstimationBreakdown: task.EstimationBreakdown,
+		Pert:                convertPertToPayload(task.Pert),
+
+		Notes:        convertNotesToPayload(task.Notes),
+		NotesHistory: convertNotesHistoryToPayload(task.NotesHistory),
+
+		AcceptanceCriteria: convertCriteriaToPayload(task.AcceptanceCriteria),
+	}
+}
+
+func convertRollupToPayload(rollup *dto.EstimateRollupResp) *EstimateRollupPayload {
+	if rollup == nil {
+		return nil
+	}
+	return &EstimateRollupPayload{
+		Points:    rollup.Points,
+		Estimated: rollup.Estimated,
+		Subtasks:  rollup.Subtasks,
 	}
 }
 
-// determineEstimation calculates the estimation value from reveal results
-// Uses average for numeric votes, or consensus/most common vote for non-numeric
-func (h *WsHandler) determineEstimation(result *dto.RevealVotesResp) string {
-	// If we have a numeric average, use it
-	if result.Average != nil {
-		return fmt.Sprintf("%.1f", *result.Average)
+func convertThreePointToPayload(triple *dto.ThreePointResp) *ThreePointResultPayload {
+	if triple == nil {
+		return nil
+	}
+	return &ThreePointResultPayload{
+		Optimistic:  triple.Optimistic,
+		MostLikely:  triple.MostLikely,
+		Pessimistic: triple.Pessimistic,
 	}
+}
 
-	// No numeric average - find consensus or most common vote
-	if len(result.Votes) == 0 {
-		return "?"
+func convertPertToPayload(pert *dto.PertResp) *PertPayload {
+	if pert == nil {
+		return nil
+	}
+	return &PertPayload{
+		ThreePointResultPayload: *convertThreePointToPayload(&pert.ThreePointResp),
+		Expected:                pert.Expected,
+		StdDev:                  pert.StdDev,
 	}
+}
 
-	// Count vote occurrences
-	voteCounts := make(map[string]int)
-	for _, voteValue := range result.Votes {
-		voteCounts[voteValue]++
+func convertVoteChangesToPayload(changes []dto.VoteChangeResp) []VoteChangePayload {
+	if changes == nil {
+		return nil
+	}
+	payloads := make([]VoteChangePayload, len(changes))
+	for i, change := range changes {
+		payloads[i] = VoteChangePayload{
+			UserID: change.UserID,
+			Voter:  change.Voter,
+			From:   change.From,
+			To:     change.To,
+		}
 	}
+	return payloads
+}
 
-	// Find the most common vote
-	var maxCount int
-	var mostCommon string
-	for value, count := range voteCounts {
-		if count > maxCount {
-			maxCount = count
-			mostCommon = value
+func convertHandsToPayload(hands []dto.RaisedHandResp) []RaisedHandPayload {
+	payloads := make([]RaisedHandPayload, len(hands))
+	for i, hand := range hands {
+		payloads[i] = RaisedHandPayload{
+			UserID:   hand.UserID,
+			Signal:   hand.Signal,
+			Position: hand.Position,
 		}
 	}
+	return payloads
+}
+
+func convertChangeToPayload(change *dto.ChangeResp) *ChangePayload {
+	if change == nil {
+		return nil
+	}
+	return &ChangePayload{
+		Action: change.Action,
+		UserID: change.UserID,
+	}
+}
+
+func convertNotesToPayload(notes *dto.TaskNotesResp) *TaskNotesPayload {
+	if notes == nil {
+		return nil
+	}
+	return &TaskNotesPayload{
+		Items:     notes.Items,
+		Author:    notes.Author,
+		UpdatedAt: notes.UpdatedAt,
+	}
+}
 
-	// If everyone voted the same, use that value
-	if maxCount == len(result.Votes) {
-		return mostCommon
+func convertNotesHistoryToPayload(history []dto.TaskNotesResp) []TaskNotesPayload {
+	if history == nil {
+		return nil
+	}
+	payloads := make([]TaskNotesPayload, len(history))
+	for i := range history {
+		payloads[i] = *convertNotesToPayload(&history[i])
 	}
+	return payloads
+}
 
-	// No clear consensus - mark as uncertain
-	return "?"
+func convertCriteriaToPayload(criteria []dto.AcceptanceCriterionResp) []AcceptanceCriterionPayload {
+	if criteria == nil {
+		return nil
+	}
+	payloads := make([]AcceptanceCriterionPayload, len(criteria))
+	for i, criterion := range criteria {
+		payloads[i] = AcceptanceCriterionPayload{
+			ID:   criterion.ID,
+			Text: criterion.Text,
+			Done: criterion.Done,
+		}
+	}
+	return payloads
 }
@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vitaly-stepin/agile_party/internal/adapters/memory"
+	"github.com/vitaly-stepin/agile_party/internal/application"
+	"github.com/vitaly-stepin/agile_party/internal/application/dto"
+	"github.com/vitaly-stepin/agile_party/internal/domain/room"
+)
+
+// stubRoomRepo knows every room and stores nothing
+type stubRoomRepo struct{}
+
+func (stubRoomRepo) Create(ctx context.Context, r *room.Room) error {
+	return nil
+}
+
+func (stubRoomRepo) GetByID(ctx context.Context, id string) (*room.Room, error) {
+	return nil, room.ErrRoomNotFound
+}
+
+func (stubRoomRepo) Update(ctx context.Context, r *room.Room) error {
+	return nil
+}
+
+func (stubRoomRepo) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (stubRoomRepo) Exists(ctx context.Context, id string) (bool, error) {
+	return true, nil
+}
+
+func TestWsHandler_PublishTaskEdit(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	client := &Client{hub: hub, RoomID: "room1", UserID: "user1", send: make(chan []byte, 4)}
+	hub.register <- client
+
+	h := NewHandler(hub, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	task, err := h.PublishTaskEdit(ctx, "room1", func(ctx context.Context) (*dto.TaskResp, error) {
+		return &dto.TaskResp{ID: "task1", Headline: "Login page"}, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if task.ID != "task1" {
+		t.Errorf("expected the edited task back, got %+v", task)
+	}
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Type    WsEventType `json:"type"`
+			Payload TaskPayload `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("expected a JSON message, got %v", err)
+		}
+		if msg.Type != EventTypeTaskUpdated || msg.Payload.ID != "task1" {
+			t.Errorf("expected task_updated for task1, got %s %+v", msg.Type, msg.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the edit to be broadcast")
+	}
+
+	rejected := errors.New("rejected")
+	if _, err := h.PublishTaskEdit(ctx, "room1", func(ctx context.Context) (*dto.TaskResp, error) {
+		return nil, rejected
+	}); !errors.Is(err, rejected) {
+		t.Errorf("expected the edit's error, got %v", err)
+	}
+
+	select {
+	case data := <-client.send:
+		t.Errorf("expected a rejected edit not to be broadcast, got %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWsHandler_PublishJoinWaitsForTheRoomActor(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	client := &Client{hub: hub, RoomID: "room1", UserID: "user1", send: make(chan []byte, 4)}
+	hub.register <- client
+
+	stateMgr := memory.NewRoomStateManager(memory.CleanupConfig{CleanupInterval: time.Hour, RoomTTL: time.Hour})
+	repo := stubRoomRepo{}
+	h := NewHandler(hub, application.NewRoomService(repo, stateMgr), application.NewUserService(repo, stateMgr), nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	// A websocket command still running for the room holds the REST join back
+	release := make(chan struct{})
+	running := make(chan struct{})
+	go h.actors.Do(ctx, "room1", func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	})
+	<-running
+
+	joined := make(chan error, 1)
+	go func() {
+		joined <- h.PublishJoin(ctx, "room1", "user2", "Bob")
+	}()
+
+	select {
+	case err := <-joined:
+		t.Fatalf("expected the join to wait for the running command, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if stateMgr.RoomExists("room1") {
+		t.Error("expected the room to be untouched while the command runs")
+	}
+
+	close(release)
+	if err := <-joined; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Type    WsEventType       `json:"type"`
+			Payload UserJoinedPayload `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("expected a JSON message, got %v", err)
+		}
+		if msg.Type != EventTypeUserJoined || msg.Payload.UserID != "user2" {
+			t.Errorf("expected user_joined for user2, got %s %+v", msg.Type, msg.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the join to be broadcast")
+	}
+}
+
+func TestWsHandler_PublishClearCanBeUndone(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	stateMgr := memory.NewRoomStateManager(memory.CleanupConfig{CleanupInterval: time.Hour, RoomTTL: time.Hour})
+	repo := stubRoomRepo{}
+	userService := application.NewUserService(repo, stateMgr)
+	undoService := application.NewUndoService(repo, nil, nil, stateMgr)
+	h := NewHandler(hub, application.NewRoomService(repo, stateMgr), userService,
+		application.NewVotingService(repo, stateMgr, nil), nil, nil, undoService, nil)
+	ctx := context.Background()
+
+	if err := userService.JoinRoom(ctx, "room1", "user1", "Alice"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := stateMgr.SubmitVote("room1", "user1", "5", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := h.PublishClear(ctx, "room1", "user1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	state, _ := stateMgr.GetRoomState("room1")
+	if len(state.Votes) != 0 {
+		t.Fatalf("expected the votes to be cleared, got %v", state.Votes)
+	}
+
+	result, err := undoService.Undo(ctx, "room1", "user1")
+	if err != nil {
+		t.Fatalf("expected the clear to be undoable, got %v", err)
+	}
+	if !result.RestoredRound {
+		t.Error("expected the undo to restore the round")
+	}
+	state, _ = stateMgr.GetRoomState("room1")
+	if state.Votes["user1"] != "5" {
+		t.Errorf("expected the vote to be back, got %v", state.Votes)
+	}
+}
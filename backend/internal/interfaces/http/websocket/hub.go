@@ -1,7 +1,9 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 )
@@ -11,6 +13,7 @@ type WsHub struct {
 	register   chan *Client
 	unregister chan *Client
 	broadcast  chan BroadcastMessage
+	ping       chan chan struct{}
 	mu         sync.RWMutex
 }
 
@@ -26,6 +29,7 @@ func NewHub() *WsHub {
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan BroadcastMessage, 256),
+		ping:       make(chan chan struct{}),
 	}
 }
 
@@ -40,6 +44,9 @@ func (h *WsHub) Run() {
 
 		case message := <-h.broadcast:
 			h.broadcastToRoom(message)
+
+		case reply := <-h.ping:
+			close(reply)
 		}
 	}
 }
@@ -116,6 +123,24 @@ func (h *WsHub) BroadcastToRoom(roomID string, message WsMessage, exclude *Clien
 	}
 }
 
+// Ping round-trips through the Run loop; an error means the loop is stuck or was never started
+func (h *WsHub) Ping(ctx context.Context) error {
+	reply := make(chan struct{})
+
+	select {
+	case h.ping <- reply:
+	case <-ctx.Done():
+		return fmt.Errorf("hub loop not responding: %w", ctx.Err())
+	}
+
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("hub loop not responding: %w", ctx.Err())
+	}
+}
+
 func (h *WsHub) GetRoomClientCount(roomID string) int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -0,0 +1,30 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWsHub_Ping_Running(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := hub.Ping(ctx); err != nil {
+		t.Fatalf("expected running hub to answer ping, got %v", err)
+	}
+}
+
+func TestWsHub_Ping_NotRunning(t *testing.T) {
+	hub := NewHub()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := hub.Ping(ctx); err == nil {
+		t.Fatal("expected error when hub loop is not running")
+	}
+}
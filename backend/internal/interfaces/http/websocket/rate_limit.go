@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/vitaly-stepin/agile_party/internal/adapters/config"
+	"github.com/vitaly-stepin/agile_party/internal/interfaces/ratelimit"
+)
+
+const ErrCodeRateLimited = "RATE_LIMITED"
+
+// EventLimiter hands out per-connection buckets and shares a per-IP bucket across connections
+type EventLimiter struct {
+	cfg   config.RateLimitConfig
+	perIP *ratelimit.KeyedLimiter
+}
+
+func NewEventLimiter(cfg config.RateLimitConfig) *EventLimiter {
+	return &EventLimiter{
+		cfg:   cfg,
+		perIP: ratelimit.NewKeyedLimiter(cfg.WsIPPerSecond, cfg.WsIPBurst),
+	}
+}
+
+func (l *EventLimiter) forClient(ip string) *clientLimiter {
+	return &clientLimiter{
+		conn:            ratelimit.NewLimiter(l.cfg.WsConnPerSecond, l.cfg.WsConnBurst),
+		perIP:           l.perIP,
+		ip:              ip,
+		maxViolations:   l.cfg.MaxViolations,
+		violationWindow: l.cfg.ViolationWindow,
+	}
+}
+
+// clientLimiter is owned by a single readPump goroutine and is not safe for concurrent use
+type clientLimiter struct {
+	conn            *rate.Limiter
+	perIP           *ratelimit.KeyedLimiter
+	ip              string
+	maxViolations   int
+	violationWindow time.Duration
+	violations      int
+	windowStart     time.Time
+}
+
+// Allow spends a token from both the connection and the IP bucket, or from neither:
+// the connection token is handed back when the IP bucket rejects the event
+func (l *clientLimiter) Allow() bool {
+	now := time.Now()
+	conn := l.conn.ReserveN(now, 1)
+	if !conn.OK() || conn.DelayFrom(now) > 0 || !l.perIP.Allow(l.ip) {
+		conn.CancelAt(now)
+		return false
+	}
+	return true
+}
+
+// RecordViolation counts a rejected event and reports whether the client
+// has exceeded its allowance for the current window and should be disconnected
+func (l *clientLimiter) RecordViolation(now time.Time) bool {
+	if now.Sub(l.windowStart) > l.violationWindow {
+		l.windowStart = now
+		l.violations = 0
+	}
+	l.violations++
+
+	return l.maxViolations > 0 && l.violations > l.maxViolations
+}
@@ -0,0 +1,75 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vitaly-stepin/agile_party/internal/adapters/config"
+)
+
+func TestClientLimiter_SharesIPBucketAcrossConnections(t *testing.T) {
+	limiter := NewEventLimiter(config.RateLimitConfig{
+		WsConnPerSecond: 1,
+		WsConnBurst:     5,
+		WsIPPerSecond:   1,
+		WsIPBurst:       3,
+	})
+
+	first := limiter.forClient("10.0.0.1")
+	second := limiter.forClient("10.0.0.1")
+
+	for i := 0; i < 3; i++ {
+		if !first.Allow() {
+			t.Fatalf("expected event %d within IP burst to be allowed", i+1)
+		}
+	}
+	if second.Allow() {
+		t.Error("expected second connection from the same IP to be limited")
+	}
+}
+
+func TestClientLimiter_IPRejectionKeepsConnectionToken(t *testing.T) {
+	limiter := NewEventLimiter(config.RateLimitConfig{
+		WsConnPerSecond: 0.001,
+		WsConnBurst:     2,
+		WsIPPerSecond:   0.001,
+		WsIPBurst:       1,
+	})
+
+	client := limiter.forClient("10.0.0.1")
+	if !client.Allow() {
+		t.Fatal("expected first event to be allowed")
+	}
+	if client.Allow() {
+		t.Fatal("expected second event to be rejected by the IP bucket")
+	}
+
+	// The rejected event must not have used up the connection's last token
+	client.ip = "10.0.0.2"
+	if !client.Allow() {
+		t.Error("expected the connection to keep the token of the rejected event")
+	}
+	if client.Allow() {
+		t.Error("expected the connection bucket to be empty")
+	}
+}
+
+func TestClientLimiter_RecordViolation(t *testing.T) {
+	limiter := NewEventLimiter(config.RateLimitConfig{
+		MaxViolations:   2,
+		ViolationWindow: time.Minute,
+	}).forClient("10.0.0.1")
+
+	now := time.Now()
+	if limiter.RecordViolation(now) || limiter.RecordViolation(now) {
+		t.Fatal("expected violations within the allowance not to disconnect")
+	}
+	if !limiter.RecordViolation(now) {
+		t.Error("expected violation beyond the allowance to disconnect")
+	}
+
+	// A new window starts the count over
+	if limiter.RecordViolation(now.Add(2 * time.Minute)) {
+		t.Error("expected violation count to reset after the window")
+	}
+}
@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Commands queued per room before submitters start blocking
+const roomMailboxSize = 64
+
+type roomCommand struct {
+	ctx  context.Context
+	run  func(ctx context.Context) error
+	done chan error
+}
+
+// roomActor owns a single goroutine that executes commands for one room in arrival order
+type roomActor struct {
+	roomID  string
+	mailbox chan roomCommand
+	pending int // commands submitted but not yet completed, guarded by RoomActors.mu
+}
+
+func (a *roomActor) loop() {
+	for cmd := range a.mailbox {
+		cmd.done <- a.execute(cmd)
+	}
+}
+
+func (a *roomActor) execute(cmd roomCommand) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Recovered from panic in room %s actor: %v", a.roomID, r)
+			err = fmt.Errorf("internal error processing room command")
+		}
+	}()
+	return cmd.run(cmd.ctx)
+}
+
+// RoomActors serializes commands per room so a read-modify-write of room state
+// and the broadcasts it produces cannot interleave with another command for the
+// same room. Different rooms are processed fully in parallel. An actor goroutine
+// only lives while it has pending work.
+type RoomActors struct {
+	mu     sync.Mutex
+	actors map[string]*roomActor
+}
+
+func NewRoomActors() *RoomActors {
+	return &RoomActors{
+		actors: make(map[string]*roomActor),
+	}
+}
+
+// Do runs fn on the room's actor after every previously submitted command for
+// that room has completed, and returns fn's error
+func (r *RoomActors) Do(ctx context.Context, roomID string, fn func(ctx context.Context) error) error {
+	actor := r.acquire(roomID)
+	defer r.release(actor)
+
+	cmd := roomCommand{
+		ctx:  ctx,
+		run:  fn,
+		done: make(chan error, 1),
+	}
+
+	select {
+	case actor.mailbox <- cmd:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return <-cmd.done
+}
+
+func (r *RoomActors) acquire(roomID string) *roomActor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	actor, exists := r.actors[roomID]
+	if !exists {
+		actor = &roomActor{
+			roomID:  roomID,
+			mailbox: make(chan roomCommand, roomMailboxSize),
+		}
+		r.actors[roomID] = actor
+		go actor.loop()
+	}
+	actor.pending++
+
+	return actor
+}
+
+func (r *RoomActors) release(actor *roomActor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	actor.pending--
+	if actor.pending == 0 {
+		close(actor.mailbox)
+		delete(r.actors, actor.roomID)
+	}
+}
+
+// ActiveRooms returns the number of rooms that currently have an actor running
+func (r *RoomActors) ActiveRooms() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.actors)
+}
@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRoomActors_SerializesCommandsPerRoom(t *testing.T) {
+	actors := NewRoomActors()
+
+	var inFlight, maxInFlight int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = actors.Do(context.Background(), "room1", func(ctx context.Context) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					max := atomic.LoadInt32(&maxInFlight)
+					if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 1 {
+		t.Errorf("expected commands for one room to run one at a time, saw %d concurrently", maxInFlight)
+	}
+}
+
+func TestRoomActors_PreservesSubmissionOrder(t *testing.T) {
+	actors := NewRoomActors()
+
+	var order []int
+	for i := 0; i < 10; i++ {
+		i := i
+		_ = actors.Do(context.Background(), "room1", func(ctx context.Context) error {
+			order = append(order, i)
+			return nil
+		})
+	}
+
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("expected commands in submission order, got %v", order)
+		}
+	}
+}
+
+func TestRoomActors_RoomsRunInParallel(t *testing.T) {
+	actors := NewRoomActors()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	go func() {
+		_ = actors.Do(context.Background(), "room1", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		_ = actors.Do(context.Background(), "room2", func(ctx context.Context) error { return nil })
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected room2 not to wait for a busy room1")
+	}
+	close(release)
+}
+
+func TestRoomActors_StopsIdleActors(t *testing.T) {
+	actors := NewRoomActors()
+
+	_ = actors.Do(context.Background(), "room1", func(ctx context.Context) error { return nil })
+
+	if n := actors.ActiveRooms(); n != 0 {
+		t.Errorf("expected idle actor to be removed, got %d active rooms", n)
+	}
+}
+
+func TestRoomActors_RecoversFromPanic(t *testing.T) {
+	actors := NewRoomActors()
+
+	err := actors.Do(context.Background(), "room1", func(ctx context.Context) error {
+		panic("boom")
+	})
+	if err == nil {
+		t.Fatal("expected error from panicking command")
+	}
+
+	if err := actors.Do(context.Background(), "room1", func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("expected room to keep processing after a panic, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vitaly-stepin/agile_party/internal/interfaces/ratelimit"
+)
+
+// RateLimit returns a middleware that applies a token bucket per client IP
+func RateLimit(limiter *ratelimit.KeyedLimiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !limiter.Allow(c.IP()) {
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many requests",
+				"code":  "RATE_LIMITED",
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import "testing"
+
+func TestKeyedLimiter_BurstThenReject(t *testing.T) {
+	limiter := NewKeyedLimiter(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if !limiter.Allow("10.0.0.1") {
+			t.Fatalf("expected request %d within burst to be allowed", i+1)
+		}
+	}
+	if limiter.Allow("10.0.0.1") {
+		t.Error("expected request beyond burst to be rejected")
+	}
+}
+
+func TestKeyedLimiter_KeysAreIndependent(t *testing.T) {
+	limiter := NewKeyedLimiter(1, 1)
+
+	if !limiter.Allow("10.0.0.1") {
+		t.Fatal("expected first request from 10.0.0.1 to be allowed")
+	}
+	if !limiter.Allow("10.0.0.2") {
+		t.Error("expected first request from 10.0.0.2 to be allowed")
+	}
+}
+
+func TestKeyedLimiter_DisabledWhenRateNotPositive(t *testing.T) {
+	limiter := NewKeyedLimiter(0, 0)
+
+	for i := 0; i < 100; i++ {
+		if !limiter.Allow("10.0.0.1") {
+			t.Fatal("expected disabled limiter to allow every request")
+		}
+	}
+}
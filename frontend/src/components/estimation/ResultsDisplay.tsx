@@ -1,15 +1,43 @@
-import { Card, Button } from '../common';
+import { useEffect, useState } from 'react';
+import { Card, Button, Input } from '../common';
 import { useRoom } from '../../context/RoomContext';
+import { VALID_VOTES } from '../../types';
+import type { AcceptEstimatePayload } from '../../types';
 
 interface ResultsDisplayProps {
-  onClear: () => void;
+  onAccept: (payload: AcceptEstimatePayload) => void;
+  onRevote: () => void;
 }
 
-export default function ResultsDisplay({ onClear }: ResultsDisplayProps) {
-  const { roomState } = useRoom();
+const DECK = VALID_VOTES.filter(v => v !== '?');
+
+export default function ResultsDisplay({ onAccept, onRevote }: ResultsDisplayProps) {
+  const { roomState, currentUserId } = useRoom();
+  const suggested = roomState?.suggested || '';
+  // Only the facilitator may accept something other than the suggestion
+  const canOverride = suggested === '' || (!!currentUserId && roomState?.facilitatorId === currentUserId);
+  const [finalValue, setFinalValue] = useState(suggested);
+  const [reason, setReason] = useState('');
+
+  useEffect(() => {
+    setFinalValue(suggested);
+    setReason('');
+  }, [suggested]);
+
+  const isOverride = suggested !== '' && finalValue !== suggested;
+
+  const handleAccept = () => {
+    if (!finalValue) return;
+    onAccept({
+      value: finalValue,
+      reason: isOverride && reason.trim() ? reason.trim() : undefined,
+    });
+  };
 
   const votes = roomState?.votes || [];
   const average = roomState?.average;
+  const dimensionResults = roomState?.dimensionResults || [];
+  const pert = roomState?.pert;
 
   // Group votes by value for better visualization
   const voteCounts = votes.reduce((acc, vote) => {
@@ -46,6 +74,41 @@ export default function ResultsDisplay({ onClear }: ResultsDisplayProps) {
         </div>
       </Card>
 
+      {/* PERT result of a three-point room */}
+      {pert && (
+        <Card variant="outlined" padding="lg">
+          <h3 className="text-lg font-semibold text-gray-900 mb-2">
+            Three-Point Estimate
+          </h3>
+          <div className="text-3xl font-bold text-blue-600" data-testid="pert-expected">
+            {pert.expected.toFixed(2)} <span className="text-base text-gray-500">± {pert.stdDev.toFixed(2)}</span>
+          </div>
+          <p className="text-sm text-gray-600">
+            Range {pert.optimistic.toFixed(1)} – {pert.pessimistic.toFixed(1)}, most likely {pert.mostLikely.toFixed(1)}
+          </p>
+        </Card>
+      )}
+
+      {/* Per-dimension results, combined into the suggestion */}
+      {dimensionResults.length > 0 && (
+        <Card variant="outlined" padding="lg">
+          <h3 className="text-lg font-semibold text-gray-900 mb-4">
+            By Dimension
+          </h3>
+          <div className="grid grid-cols-1 sm:grid-cols-3 gap-4">
+            {dimensionResults.map((result) => (
+              <div key={result.key} className="bg-gray-50 rounded-lg p-4 border border-gray-200 text-center">
+                <div className="text-sm text-gray-600">{result.name}</div>
+                <div className="text-2xl font-bold text-gray-900">{result.suggested || 'N/A'}</div>
+                {result.average !== null && result.average !== undefined && (
+                  <div className="text-xs text-gray-500">avg {result.average.toFixed(1)}</div>
+                )}
+              </div>
+            ))}
+          </div>
+        </Card>
+      )}
+
       {/* Individual Votes */}
       <Card variant="outlined" padding="lg">
         <h3 className="text-lg font-semibold text-gray-900 mb-4">
@@ -62,12 +125,43 @@ export default function ResultsDisplay({ onClear }: ResultsDisplayProps) {
                 {vote.value}
               </div>
               <div className="text-sm text-gray-600 text-center truncate">
-                {vote.userName}
+                {vote.userName ?? 'Anonymous'}
               </div>
+              {vote.threePoint && (
+                <div className="text-xs text-gray-500 text-center">
+                  {vote.threePoint.optimistic} / {vote.threePoint.mostLikely} / {vote.threePoint.pessimistic}
+                </div>
+              )}
+              {vote.dimensions && (
+                <div className="text-xs text-gray-500 text-center">
+                  {dimensionResults.map(d => `${d.name[0]} ${vote.dimensions?.[d.key] ?? '-'}`).join(' · ')}
+                </div>
+              )}
+              {vote.comment && (
+                <div className="text-xs text-gray-700 italic mt-2 break-words" title={vote.comment}>
+                  “{vote.comment}”
+                </div>
+              )}
             </div>
           ))}
         </div>
 
+        {/* Votes changed after the reveal */}
+        {roomState?.changes && roomState.changes.length > 0 && (
+          <div className="border-t border-gray-200 pt-4 mb-4" data-testid="vote-changes">
+            <h4 className="text-sm font-medium text-gray-700 mb-2">
+              Changed After Reveal
+            </h4>
+            <ul className="text-sm text-gray-600 space-y-1">
+              {roomState.changes.map((change, index) => (
+                <li key={index}>
+                  {change.voter ?? 'Someone'} {change.from ? `changed from ${change.from} to ${change.to}` : `voted ${change.to} after the reveal`}
+                </li>
+              ))}
+            </ul>
+          </div>
+        )}
+
         {/* Vote Distribution */}
         <div className="border-t border-gray-200 pt-4">
           <h4 className="text-sm font-medium text-gray-700 mb-3">
@@ -97,11 +191,46 @@ export default function ResultsDisplay({ onClear }: ResultsDisplayProps) {
           </div>
         </div>
 
-        {/* Clear Button */}
-        <div className="mt-6 pt-4 border-t border-gray-200">
-          <Button onClick={onClear} variant="primary" fullWidth>
-            Next Round
-          </Button>
+        {/* Final Estimate */}
+        <div className="mt-6 pt-4 border-t border-gray-200 space-y-3">
+          <div className="flex items-center gap-3">
+            <label htmlFor="final-estimate" className="text-sm font-medium text-gray-700">
+              Final estimate
+            </label>
+            <select
+              id="final-estimate"
+              value={finalValue}
+              onChange={(e) => setFinalValue(e.target.value)}
+              disabled={!canOverride}
+              title={canOverride ? undefined : 'Only the facilitator can override the suggestion'}
+              className="border rounded px-2 py-1"
+              data-testid="final-estimate-select"
+            >
+              <option value="" disabled>Pick a card</option>
+              {DECK.map(value => (
+                <option key={value} value={value}>
+                  {value}{value === suggested ? ' (suggested)' : ''}
+                </option>
+              ))}
+            </select>
+          </div>
+          {isOverride && (
+            <Input
+              type="text"
+              value={reason}
+              onChange={(e) => setReason(e.target.value)}
+              placeholder="Why override the suggestion? (optional)"
+              maxLength={500}
+            />
+          )}
+          <div className="flex gap-2">
+            <Button onClick={handleAccept} variant="primary" fullWidth disabled={!finalValue}>
+              Accept & Next
+            </Button>
+            <Button onClick={onRevote} variant="outline" fullWidth>
+              Re-vote
+            </Button>
+          </div>
         </div>
       </Card>
     </div>
@@ -1,8 +1,9 @@
 import { useState } from 'react';
 import { Card, Button } from '../common';
 import { useRoom } from '../../context/RoomContext';
-import { VALID_VOTES } from '../../types';
-import type { VoteValue, ClientEvent } from '../../types';
+import { useTasks } from '../../context/TaskContext';
+import { VALID_VOTES, MAX_VOTE_COMMENT_LENGTH } from '../../types';
+import type { VoteValue, ClientEvent, VotePayload } from '../../types';
 import VoteCard from './VoteCard';
 
 interface VotePanelProps {
@@ -10,9 +11,27 @@ interface VotePanelProps {
   sendEvent: (event: ClientEvent) => void;
 }
 
+const THREE_POINT_ROWS = [
+  { key: 'optimistic', name: 'Optimistic' },
+  { key: 'mostLikely', name: 'Most likely' },
+  { key: 'pessimistic', name: 'Pessimistic' },
+];
+
 export default function VotePanel({ onReveal, sendEvent }: VotePanelProps) {
-  const { currentUser, roomState } = useRoom();
+  const { currentUser, roomState, room } = useRoom();
   const [selectedVote, setSelectedVote] = useState<VoteValue | null>(null);
+  const [selectedValues, setSelectedValues] = useState<Record<string, VoteValue>>({});
+  const [comment, setComment] = useState('');
+  const [lastVote, setLastVote] = useState<VotePayload | null>(null);
+  const isThreePoint = room?.estimation_mode === 'three_point';
+  const votesLocked = room?.vote_change_policy === 'locked' && (roomState?.isRevealed || false);
+  const activeTask = useTasks()?.activeTask;
+  // The server refuses votes on a task without acceptance criteria when the room requires them
+  const needsCriteria = !!room?.require_acceptance_criteria && !!activeTask && !activeTask.acceptanceCriteria?.length;
+  const votingBlocked = votesLocked || needsCriteria;
+  // A three-point vote is entered like three dimensions, each picking a numeric card
+  const dimensions = isThreePoint ? THREE_POINT_ROWS : room?.dimensions || [];
+  const deck = isThreePoint ? VALID_VOTES.filter(v => v !== '?') : VALID_VOTES;
 
   // Find the user's current vote from revealed votes
   const currentUserVoteValue = roomState?.votes?.find(
@@ -24,14 +43,44 @@ export default function VotePanel({ onReveal, sendEvent }: VotePanelProps) {
     setSelectedVote(currentUserVoteValue);
   }
 
-  const handleVoteClick = (value: VoteValue) => {
-    setSelectedVote(value);
+  // The comment travels with the vote, so a changed comment re-sends the last vote
+  const sendVote = (vote: VotePayload, voteComment = comment) => {
+    setLastVote(vote);
     sendEvent({
       type: 'vote',
-      payload: { value },
+      payload: { ...vote, comment: voteComment.trim() || undefined },
     });
   };
 
+  const handleVoteClick = (value: VoteValue) => {
+    setSelectedVote(value);
+    sendVote({ value });
+  };
+
+  const handleCommentBlur = () => {
+    if (lastVote && currentUserVoted) {
+      sendVote(lastVote);
+    }
+  };
+
+  // In multi-dimensional rooms the vote is sent once every dimension has a card
+  const handleDimensionVoteClick = (key: string, value: VoteValue) => {
+    const values = { ...selectedValues, [key]: value };
+    setSelectedValues(values);
+    if (!dimensions.every(d => values[d.key])) return;
+
+    if (isThreePoint) {
+      const { optimistic, mostLikely, pessimistic } = values;
+      if (parseFloat(optimistic) > parseFloat(mostLikely) || parseFloat(mostLikely) > parseFloat(pessimistic)) {
+        return; // the server rejects decreasing triples
+      }
+      sendVote({ threePoint: { optimistic, mostLikely, pessimistic } });
+      return;
+    }
+
+    sendVote({ values });
+  };
+
   const currentUserVoted = currentUser
     ? roomState?.users.some(
         (u) => u.id === currentUser.id && u.isVoted
@@ -47,19 +96,56 @@ export default function VotePanel({ onReveal, sendEvent }: VotePanelProps) {
       {/* Vote Cards Grid */}
       <Card variant="outlined" padding="lg">
         <h3 className="text-lg font-semibold text-gray-900 mb-4">
-          {isRevealed ? 'Change Your Estimate' : 'Select Your Estimate'}
+          {votesLocked ? 'Votes Are Locked' : isRevealed ? 'Change Your Estimate' : 'Select Your Estimate'}
         </h3>
 
-        <div className="grid grid-cols-4 sm:grid-cols-6 md:grid-cols-8 gap-3 mb-6">
-          {VALID_VOTES.map((vote) => (
-            <VoteCard
-              key={vote}
-              value={vote}
-              isSelected={selectedVote === vote}
-              onClick={handleVoteClick}
-              disabled={!currentUser}
-            />
-          ))}
+        {dimensions.length === 0 ? (
+          <div className="grid grid-cols-4 sm:grid-cols-6 md:grid-cols-8 gap-3 mb-6">
+            {VALID_VOTES.map((vote) => (
+              <VoteCard
+                key={vote}
+                value={vote}
+                isSelected={selectedVote === vote}
+                onClick={handleVoteClick}
+                disabled={!currentUser || votingBlocked}
+              />
+            ))}
+          </div>
+        ) : (
+          dimensions.map((dimension) => (
+            <div key={dimension.key} className="mb-6" data-testid={`dimension-${dimension.key}`}>
+              <h4 className="text-sm font-medium text-gray-700 mb-2">{dimension.name}</h4>
+              <div className="grid grid-cols-4 sm:grid-cols-6 md:grid-cols-8 gap-3">
+                {deck.map((vote) => (
+                  <VoteCard
+                    key={vote}
+                    value={vote}
+                    isSelected={selectedValues[dimension.key] === vote}
+                    onClick={(value) => handleDimensionVoteClick(dimension.key, value)}
+                    disabled={!currentUser || votingBlocked}
+                  />
+                ))}
+              </div>
+            </div>
+          ))
+        )}
+
+        <div className="mb-4">
+          <label htmlFor="vote-comment" className="block text-sm font-medium text-gray-700 mb-1">
+            Why? (optional, shown when votes are revealed)
+          </label>
+          <input
+            id="vote-comment"
+            type="text"
+            value={comment}
+            maxLength={MAX_VOTE_COMMENT_LENGTH}
+            onChange={(e) => setComment(e.target.value)}
+            onBlur={handleCommentBlur}
+            placeholder="e.g. touches the legacy billing code"
+            className="border rounded px-2 py-1 text-sm w-full"
+            disabled={!currentUser || votingBlocked}
+            data-testid="vote-comment"
+          />
         </div>
 
         {/* Vote Status */}
@@ -67,8 +153,10 @@ export default function VotePanel({ onReveal, sendEvent }: VotePanelProps) {
           <div className="text-sm text-gray-600">
             {currentUserVoted ? (
               <span className="text-green-600 font-medium">
-                ✓ You voted {selectedVote}
-                {isRevealed && ' - Click to change'}
+                ✓ You voted {dimensions.length === 0
+                  ? selectedVote
+                  : dimensions.map(d => `${d.name} ${selectedValues[d.key]}`).join(', ')}
+                {isRevealed && !votesLocked && ' - Click to change'}
               </span>
             ) : (
               <span>Select a card to vote</span>
@@ -92,9 +180,15 @@ export default function VotePanel({ onReveal, sendEvent }: VotePanelProps) {
           </p>
         )}
 
-        {isRevealed && (
+        {needsCriteria && (
+          <p className="text-xs text-amber-600 mt-2 text-right font-medium">
+            Add acceptance criteria to the task before voting
+          </p>
+        )}
+
+        {isRevealed && !votesLocked && (
           <p className="text-xs text-blue-600 mt-2 text-right font-medium">
-            You can change your vote - the average will update automatically
+            You can change your vote - the change will be shown to everyone
           </p>
         )}
       </Card>
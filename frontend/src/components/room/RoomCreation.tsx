@@ -5,12 +5,19 @@ import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
 import { useRoom } from '../../context/RoomContext';
+import { DEFAULT_DIMENSIONS } from '../../types';
+import type { DimensionCombination, EstimationMode } from '../../types';
 
 export default function RoomCreation() {
   const navigate = useNavigate();
   const { newRoom, error, isLoading } = useRoom();
   const [roomName, setRoomName] = useState('');
   const [nickname, setNickname] = useState('');
+  const [voteStyle, setVoteStyle] = useState<'single' | 'dimensions' | EstimationMode>('single');
+  const [combination, setCombination] = useState<DimensionCombination>('sum');
+  const [anonymousVotes, setAnonymousVotes] = useState(false);
+  const [lockVotes, setLockVotes] = useState(false);
+  const [requireCriteria, setRequireCriteria] = useState(false);
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -20,7 +27,17 @@ export default function RoomCreation() {
     }
 
     try {
-      const roomId = await newRoom(roomName.trim(), nickname.trim());
+      const roomId = await newRoom(
+        roomName.trim(),
+        nickname.trim(),
+        {
+          ...(voteStyle === 'dimensions' && { dimensions: DEFAULT_DIMENSIONS, combination }),
+          ...(voteStyle === 'three_point' && { mode: 'three_point' as const }),
+          anonymousVotes,
+          voteChangePolicy: lockVotes ? 'locked' : 'tracked',
+          requireAcceptanceCriteria: requireCriteria,
+        }
+      );
       navigate(`/room/${roomId}`);
     } catch (err) {
       // Error is handled by context
@@ -59,6 +76,68 @@ export default function RoomCreation() {
         </p>
       </div>
 
+      <div className="space-y-2">
+        <Label htmlFor="vote-style">Voting</Label>
+        <select
+          id="vote-style"
+          value={voteStyle}
+          onChange={(e) => setVoteStyle(e.target.value as typeof voteStyle)}
+          className="border rounded px-2 py-1 text-sm w-full"
+          disabled={isLoading}
+        >
+          <option value="single">One card per vote</option>
+          <option value="dimensions">Complexity, effort and uncertainty separately</option>
+          <option value="three_point">Three-point (optimistic / most likely / pessimistic)</option>
+        </select>
+        {voteStyle === 'dimensions' && (
+          <div className="flex items-center gap-2">
+            <Label htmlFor="combination">Combine with</Label>
+            <select
+              id="combination"
+              value={combination}
+              onChange={(e) => setCombination(e.target.value as DimensionCombination)}
+              className="border rounded px-2 py-1 text-sm"
+              disabled={isLoading}
+            >
+              <option value="sum">Sum</option>
+              <option value="average">Average</option>
+              <option value="max">Highest</option>
+              <option value="weighted">Weighted average</option>
+            </select>
+          </div>
+        )}
+      </div>
+
+      <label className="flex items-center gap-2 text-sm">
+        <input
+          type="checkbox"
+          checked={anonymousVotes}
+          onChange={(e) => setAnonymousVotes(e.target.checked)}
+          disabled={isLoading}
+        />
+        Anonymous votes (show only the distribution after reveal)
+      </label>
+
+      <label className="flex items-center gap-2 text-sm">
+        <input
+          type="checkbox"
+          checked={lockVotes}
+          onChange={(e) => setLockVotes(e.target.checked)}
+          disabled={isLoading}
+        />
+        Lock votes after reveal (otherwise changes are shown as "changed from X to Y")
+      </label>
+
+      <label className="flex items-center gap-2 text-sm">
+        <input
+          type="checkbox"
+          checked={requireCriteria}
+          onChange={(e) => setRequireCriteria(e.target.checked)}
+          disabled={isLoading}
+        />
+        Require acceptance criteria before a task can be voted on
+      </label>
+
       {error && (
         <div className="bg-red-50 border border-red-200 text-red-700 px-4 py-3 rounded-lg text-sm">
           {error}
@@ -0,0 +1,91 @@
+import { useState } from 'react';
+import { Button } from '../common';
+import { MAX_CRITERION_LENGTH } from '../../types';
+import type { Task, ClientEvent } from '../../types';
+
+interface AcceptanceCriteriaProps {
+  task: Task;
+  sendEvent: (event: ClientEvent) => void;
+}
+
+// Ordered checklist of what "done" means for the task
+export default function AcceptanceCriteria({ task, sendEvent }: AcceptanceCriteriaProps) {
+  const [draft, setDraft] = useState('');
+  const criteria = task.acceptanceCriteria ?? [];
+
+  const addCriterion = () => {
+    const text = draft.trim();
+    if (!text) {
+      return;
+    }
+    sendEvent({ type: 'add_criterion', payload: { taskId: task.id, text } });
+    setDraft('');
+  };
+
+  const move = (index: number, offset: number) => {
+    const ids = criteria.map(c => c.id);
+    const target = index + offset;
+    if (target < 0 || target >= ids.length) {
+      return;
+    }
+    [ids[index], ids[target]] = [ids[target], ids[index]];
+    sendEvent({ type: 'reorder_criteria', payload: { taskId: task.id, criterionIds: ids } });
+  };
+
+  return (
+    <div className="mt-2 pt-2 border-t text-sm" onClick={(e) => e.stopPropagation()} data-testid="acceptance-criteria">
+      <div className="font-medium text-gray-700">
+        Acceptance criteria
+        {criteria.length > 0 && (
+          <span className="ml-1 text-xs text-gray-400">
+            {criteria.filter(c => c.done).length}/{criteria.length}
+          </span>
+        )}
+      </div>
+
+      {criteria.length === 0 && (
+        <div className="text-xs text-gray-400">No acceptance criteria yet</div>
+      )}
+
+      <ul className="mt-1 space-y-1">
+        {criteria.map((criterion, index) => (
+          <li key={criterion.id} className="flex items-center gap-2">
+            <input
+              type="checkbox"
+              checked={criterion.done}
+              onChange={(e) => sendEvent({
+                type: 'update_criterion',
+                payload: { taskId: task.id, criterionId: criterion.id, done: e.target.checked }
+              })}
+            />
+            <span className={`flex-1 ${criterion.done ? 'line-through text-gray-400' : 'text-gray-600'}`}>
+              {criterion.text}
+            </span>
+            <button onClick={() => move(index, -1)} disabled={index === 0} className="text-xs text-gray-500 disabled:opacity-30">↑</button>
+            <button onClick={() => move(index, 1)} disabled={index === criteria.length - 1} className="text-xs text-gray-500 disabled:opacity-30">↓</button>
+            <button
+              onClick={() => sendEvent({ type: 'delete_criterion', payload: { taskId: task.id, criterionId: criterion.id } })}
+              className="text-xs text-red-600 hover:underline"
+            >
+              Remove
+            </button>
+          </li>
+        ))}
+      </ul>
+
+      <div className="flex gap-2 mt-1">
+        <input
+          type="text"
+          value={draft}
+          maxLength={MAX_CRITERION_LENGTH}
+          onChange={(e) => setDraft(e.target.value)}
+          onKeyDown={(e) => e.key === 'Enter' && addCriterion()}
+          placeholder="Add a criterion..."
+          className="flex-1 px-2 py-1 border rounded"
+          data-testid="criterion-input"
+        />
+        <Button size="sm" onClick={addCriterion}>Add</Button>
+      </div>
+    </div>
+  );
+}
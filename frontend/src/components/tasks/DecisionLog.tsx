@@ -0,0 +1,102 @@
+import { useState } from 'react';
+import { Button } from '../common';
+import { useRoom } from '../../context/RoomContext';
+import { MAX_TASK_NOTES, MAX_TASK_NOTE_LENGTH } from '../../types';
+import type { Task, ClientEvent } from '../../types';
+
+interface DecisionLogProps {
+  task: Task;
+  sendEvent: (event: ClientEvent) => void;
+}
+
+// Assumptions agreed for the estimate, one per line, with earlier revisions
+export default function DecisionLog({ task, sendEvent }: DecisionLogProps) {
+  const { roomState, currentUserId } = useRoom();
+  // The facilitator keeps the log; everyone else reads it
+  const canEdit = !!currentUserId && roomState?.facilitatorId === currentUserId;
+  const [isEditing, setIsEditing] = useState(false);
+  const [draft, setDraft] = useState('');
+  const [showHistory, setShowHistory] = useState(false);
+
+  const items = task.notes?.items ?? [];
+  const history = task.notesHistory ?? [];
+
+  const startEditing = () => {
+    setDraft(items.join('\n'));
+    setIsEditing(true);
+  };
+
+  const lines = draft.split('\n').map(line => line.trim()).filter(Boolean);
+  const isValid = lines.length <= MAX_TASK_NOTES && lines.every(line => line.length <= MAX_TASK_NOTE_LENGTH);
+
+  const save = () => {
+    if (!isValid) {
+      return;
+    }
+    sendEvent({ type: 'update_notes', payload: { taskId: task.id, notes: lines } });
+    setIsEditing(false);
+  };
+
+  return (
+    <div className="mt-2 pt-2 border-t text-sm" onClick={(e) => e.stopPropagation()} data-testid="decision-log">
+      <div className="flex items-center justify-between">
+        <span className="font-medium text-gray-700">Decision log</span>
+        {canEdit && !isEditing && (
+          <button onClick={startEditing} className="text-blue-600 hover:underline text-xs">
+            {items.length > 0 ? 'Edit' : 'Add assumptions'}
+          </button>
+        )}
+      </div>
+
+      {isEditing ? (
+        <div className="mt-1">
+          <textarea
+            value={draft}
+            onChange={(e) => setDraft(e.target.value)}
+            placeholder={'One assumption per line, e.g.\nexcludes migration\nAPI already exists'}
+            className="w-full px-2 py-1 border rounded"
+            rows={Math.min(Math.max(lines.length + 1, 3), 8)}
+            autoFocus
+          />
+          {!isValid && (
+            <div className="text-xs text-red-600">
+              Up to {MAX_TASK_NOTES} notes of {MAX_TASK_NOTE_LENGTH} characters each
+            </div>
+          )}
+          <div className="flex gap-2 mt-1">
+            <Button size="sm" onClick={save} disabled={!isValid}>Save</Button>
+            <Button size="sm" variant="outline" onClick={() => setIsEditing(false)}>Cancel</Button>
+          </div>
+        </div>
+      ) : items.length > 0 && (
+        <ul className="mt-1 list-disc list-inside text-gray-600">
+          {items.map((item, i) => <li key={i}>{item}</li>)}
+        </ul>
+      )}
+
+      {task.notes && !isEditing && (
+        <div className="text-xs text-gray-400 mt-1">
+          {task.notes.author && `${task.notes.author} · `}{new Date(task.notes.updatedAt).toLocaleString()}
+          {history.length > 0 && (
+            <button onClick={() => setShowHistory(!showHistory)} className="ml-2 text-blue-600 hover:underline">
+              {showHistory ? 'Hide revisions' : `${history.length} earlier revision${history.length > 1 ? 's' : ''}`}
+            </button>
+          )}
+        </div>
+      )}
+
+      {showHistory && (
+        <ol className="mt-1 space-y-1 text-xs text-gray-500">
+          {[...history].reverse().map((revision, i) => (
+            <li key={i}>
+              {revision.author && `${revision.author} · `}{new Date(revision.updatedAt).toLocaleString()}
+              {revision.items.length > 0
+                ? <ul className="list-disc list-inside">{revision.items.map((item, j) => <li key={j}>{item}</li>)}</ul>
+                : <div className="italic">cleared</div>}
+            </li>
+          ))}
+        </ol>
+      )}
+    </div>
+  );
+}
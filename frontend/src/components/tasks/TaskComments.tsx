@@ -0,0 +1,144 @@
+import { useState, useEffect } from 'react';
+import { Button } from '../common';
+import { useRoom } from '../../context/RoomContext';
+import { useTasks } from '../../context/TaskContext';
+import { api } from '../../services/api';
+import { MAX_TASK_COMMENT_LENGTH } from '../../types';
+import type { Task, ClientEvent, TaskComment } from '../../types';
+
+const PAGE_SIZE = 50;
+
+interface TaskCommentsProps {
+  task: Task;
+  sendEvent: (event: ClientEvent) => void;
+}
+
+export default function TaskComments({ task, sendEvent }: TaskCommentsProps) {
+  const { currentUserId } = useRoom();
+  const taskContext = useTasks();
+  const comments = taskContext?.comments[task.id];
+  const setTaskComments = taskContext?.setTaskComments;
+
+  const [total, setTotal] = useState(0);
+  const [draft, setDraft] = useState('');
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [editBody, setEditBody] = useState('');
+
+  useEffect(() => {
+    if (comments || !setTaskComments) {
+      return;
+    }
+    api.getTaskComments(task.roomId, task.id, PAGE_SIZE)
+      .then(page => {
+        setTotal(page.total);
+        setTaskComments(task.id, page.comments);
+      })
+      .catch(error => console.error('Failed to load comments:', error));
+  }, [comments, setTaskComments, task.roomId, task.id]);
+
+  const loadMore = async () => {
+    if (!comments || !setTaskComments) {
+      return;
+    }
+    try {
+      const page = await api.getTaskComments(task.roomId, task.id, PAGE_SIZE, comments.length);
+      setTotal(page.total);
+      const known = new Set(comments.map(c => c.id));
+      setTaskComments(task.id, [...comments, ...page.comments.filter(c => !known.has(c.id))]);
+    } catch (error) {
+      console.error('Failed to load comments:', error);
+    }
+  };
+
+  const addComment = () => {
+    const body = draft.trim();
+    if (!body) {
+      return;
+    }
+    sendEvent({ type: 'add_comment', payload: { taskId: task.id, body } });
+    setDraft('');
+  };
+
+  const startEditing = (comment: TaskComment) => {
+    setEditingId(comment.id);
+    setEditBody(comment.body);
+  };
+
+  const saveEdit = (comment: TaskComment) => {
+    const body = editBody.trim();
+    if (body && body !== comment.body) {
+      sendEvent({ type: 'edit_comment', payload: { commentId: comment.id, body } });
+    }
+    setEditingId(null);
+  };
+
+  const deleteComment = (comment: TaskComment) => {
+    sendEvent({ type: 'delete_comment', payload: { commentId: comment.id } });
+  };
+
+  if (!comments) {
+    return null;
+  }
+
+  return (
+    <div className="mt-2 pt-2 border-t text-sm" onClick={(e) => e.stopPropagation()} data-testid="task-comments">
+      <div className="font-medium text-gray-700 mb-1">Discussion</div>
+
+      {comments.length < total && (
+        <button onClick={loadMore} className="text-blue-600 hover:underline text-xs mb-1">
+          Load more comments
+        </button>
+      )}
+
+      <ul className="space-y-1">
+        {comments.map(comment => (
+          <li key={comment.id} className="text-gray-600">
+            <span className="font-medium">{comment.authorName || 'Someone'}</span>
+            <span className="text-xs text-gray-400 ml-1">
+              {new Date(comment.createdAt).toLocaleString()}
+              {comment.edited && ' (edited)'}
+            </span>
+            {editingId === comment.id ? (
+              <input
+                type="text"
+                value={editBody}
+                maxLength={MAX_TASK_COMMENT_LENGTH}
+                onChange={(e) => setEditBody(e.target.value)}
+                onBlur={() => saveEdit(comment)}
+                onKeyDown={(e) => e.key === 'Enter' && saveEdit(comment)}
+                className="w-full px-2 py-1 border rounded"
+                autoFocus
+              />
+            ) : (
+              <div className="whitespace-pre-wrap">{comment.body}</div>
+            )}
+            {comment.authorId === currentUserId && editingId !== comment.id && (
+              <div className="flex gap-2 text-xs">
+                <button onClick={() => startEditing(comment)} className="text-blue-600 hover:underline">
+                  Edit
+                </button>
+                <button onClick={() => deleteComment(comment)} className="text-red-600 hover:underline">
+                  Delete
+                </button>
+              </div>
+            )}
+          </li>
+        ))}
+      </ul>
+
+      <div className="flex gap-2 mt-2">
+        <input
+          type="text"
+          value={draft}
+          maxLength={MAX_TASK_COMMENT_LENGTH}
+          onChange={(e) => setDraft(e.target.value)}
+          onKeyDown={(e) => e.key === 'Enter' && addComment()}
+          placeholder="Add a comment..."
+          className="flex-1 px-2 py-1 border rounded"
+          data-testid="task-comment-input"
+        />
+        <Button size="sm" onClick={addComment}>Post</Button>
+      </div>
+    </div>
+  );
+}
@@ -1,15 +1,28 @@
 import { useState, useEffect } from 'react';
-import type { Task, ClientEvent } from '../../types';
+import type { Task, TaskType, ClientEvent, EstimationRecord, EstimateRollup, UpdateTaskPayload } from '../../types';
+import { TASK_TYPES } from '../../types';
+import { api } from '../../services/api';
+import { useTasks } from '../../context/TaskContext';
+import TaskComments from './TaskComments';
+import DecisionLog from './DecisionLog';
+import AcceptanceCriteria from './AcceptanceCriteria';
 
 interface TaskItemProps {
   task: Task;
   isActive: boolean;
+  depth?: number; // epics above the task, used for indentation
+  rollup?: EstimateRollup; // set for epics
+  parentOptions?: Task[]; // tasks this one may be filed under
+  blockers?: Task[]; // tasks this one depends on
+  dependencyOptions?: Task[]; // tasks this one could be made to depend on
+  previousSibling?: Task; // neighbours under the same epic, used to move the task one step
+  nextSibling?: Task;
   onSetActive: () => void;
   onDelete: () => void;
   sendEvent: (event: ClientEvent) => void;
 }
 
-export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEvent }: TaskItemProps) {
+export default function TaskItem({ task, isActive, depth = 0, rollup, parentOptions = [], blockers = [], dependencyOptions = [], previousSibling, nextSibling, onSetActive, onDelete, sendEvent }: TaskItemProps) {
   const [isEditing, setIsEditing] = useState(false);
   const [headline, setHeadline] = useState(task.headline);
 
@@ -19,25 +32,154 @@ export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEv
     }
   }, [task.headline, isEditing]);
 
+  // Edits carry the version they were made on; the last one is kept to retry after a conflict
+  const taskContext = useTasks();
+  const conflict = taskContext?.conflictedTask?.id === task.id ? taskContext.conflictedTask : null;
+  const [lastEdit, setLastEdit] = useState<Omit<UpdateTaskPayload, 'taskId' | 'version'> | null>(null);
+
+  const sendUpdate = (changes: Omit<UpdateTaskPayload, 'taskId' | 'version'>) => {
+    setLastEdit(changes);
+    sendEvent({
+      type: 'update_task',
+      payload: { taskId: task.id, version: task.version, ...changes }
+    });
+  };
+
+  const resolveConflict = (retry: boolean) => {
+    if (retry && lastEdit) {
+      sendUpdate(lastEdit);
+    }
+    taskContext?.setConflictedTask(null);
+  };
+
   const handleUpdate = () => {
     if (headline.trim() && headline !== task.headline) {
+      sendUpdate({ headline: headline.trim() });
+    }
+    setIsEditing(false);
+  };
+
+  const [labels, setLabels] = useState((task.labels || []).join(', '));
+
+  useEffect(() => {
+    setLabels((task.labels || []).join(', '));
+  }, [task.labels]);
+
+  const handleTypeChange = (type: TaskType) => {
+    sendUpdate({ type });
+  };
+
+  const handleLabelsUpdate = () => {
+    const next = labels.split(',').map(l => l.trim()).filter(Boolean);
+    if (next.join(',') !== (task.labels || []).join(',')) {
+      sendUpdate({ labels: next });
+    }
+  };
+
+  const [subtaskHeadline, setSubtaskHeadline] = useState('');
+
+  const handleAddSubtask = () => {
+    if (subtaskHeadline.trim()) {
       sendEvent({
-        type: 'update_task',
-        payload: {
-          taskId: task.id,
-          headline: headline.trim()
-        }
+        type: 'create_task',
+        payload: { headline: subtaskHeadline.trim(), parentId: task.id }
       });
+      setSubtaskHeadline('');
     }
-    setIsEditing(false);
   };
 
+  const handleParentChange = (parentId: string) => {
+    sendEvent({
+      type: 'set_task_parent',
+      payload: { taskId: task.id, parentId }
+    });
+  };
+
+  const changeDependency = (type: 'add_dependency' | 'remove_dependency', dependsOnId: string) => {
+    sendEvent({
+      type,
+      payload: { taskId: task.id, dependsOnId }
+    });
+  };
+
+  const [history, setHistory] = useState<EstimationRecord[] | null>(null);
+
+  const toggleHistory = async () => {
+    if (history) {
+      setHistory(null);
+      return;
+    }
+    try {
+      setHistory(await api.getEstimationHistory(task.roomId, task.id));
+    } catch (error) {
+      console.error('Failed to load estimation history:', error);
+    }
+  };
+
+  const changeStatus = (type: 'skip_task' | 'defer_task' | 'reopen_task' | 'reestimate_task') => {
+    sendEvent({
+      type,
+      payload: { taskId: task.id }
+    });
+  };
+
+  const resized = !!task.previousEstimation && task.previousEstimation !== task.estimation;
+
+  const breakdown = task.estimationBreakdown
+    ? Object.entries(task.estimationBreakdown).map(([key, value]) => `${key} ${value}`).join(', ')
+    : '';
+  const pertRange = task.pert
+    ? `PERT ${task.pert.expected} ± ${task.pert.stdDev} (${task.pert.optimistic}–${task.pert.pessimistic})`
+    : '';
+
   const estimationBadge = task.estimation ? (
-    <span className="px-2 py-1 bg-green-100 text-green-800 rounded text-xs font-medium">
+    <span
+      className="px-2 py-1 bg-green-100 text-green-800 rounded text-xs font-medium"
+      title={[resized ? `Previously estimated ${task.previousEstimation}` : '', breakdown, pertRange].filter(Boolean).join('\n') || undefined}
+    >
+      {resized && <span className="line-through text-green-600 mr-1">{task.previousEstimation}</span>}
       {task.estimation}
     </span>
   ) : null;
 
+  const isParked = task.status === 'skipped' || task.status === 'deferred';
+  const isOpen = task.status === 'pending' || task.status === 'in_discussion';
+
+  const statusBadge = isParked ? (
+    <span className="px-2 py-1 bg-gray-100 text-gray-600 rounded text-xs font-medium">
+      {task.status === 'skipped' ? 'Skipped' : 'Needs info'}
+    </span>
+  ) : null;
+
+  // Epics are not voted on, their estimate is the sum of their subtasks
+  const rollupBadge = rollup ? (
+    <span
+      className="px-2 py-1 bg-indigo-100 text-indigo-800 rounded text-xs font-medium"
+      title={`${rollup.estimated} of ${rollup.subtasks} subtasks estimated`}
+    >
+      Σ {rollup.points}
+      {rollup.estimated < rollup.subtasks && <span className="text-indigo-500"> ({rollup.estimated}/{rollup.subtasks})</span>}
+    </span>
+  ) : null;
+
+  // Blocked while any task it depends on still lacks an estimate
+  const openBlockers = blockers.filter(b => b.status !== 'estimated');
+  const blockedBadge = openBlockers.length > 0 ? (
+    <span
+      className="px-2 py-1 bg-red-100 text-red-700 rounded text-xs font-medium"
+      title={`Blocked by ${openBlockers.map(b => b.headline).join(', ')}`}
+    >
+      Blocked
+    </span>
+  ) : null;
+
+  // Stories are the default, only call out the other types
+  const typeBadge = task.type && task.type !== 'story' ? (
+    <span className="px-2 py-1 bg-purple-100 text-purple-800 rounded text-xs font-medium capitalize">
+      {task.type}
+    </span>
+  ) : null;
+
   return (
     <div
       className={`
@@ -45,6 +187,7 @@ export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEv
         ${isActive ? 'border-blue-500 bg-blue-50' : 'border-gray-200'}
         transition-colors cursor-pointer hover:border-blue-300
       `}
+      style={depth > 0 ? { marginLeft: `${depth * 1.25}rem` } : undefined}
       onClick={() => !isActive && onSetActive()}
     >
       <div className="flex items-center justify-between gap-2">
@@ -62,8 +205,11 @@ export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEv
             />
           ) : (
             <div className="flex items-center gap-2">
-              <span className="font-medium truncate">{task.headline}</span>
-              {estimationBadge}
+              <span className={`font-medium truncate ${isParked ? 'text-gray-400' : ''}`}>{task.headline}</span>
+              {typeBadge}
+              {rollupBadge ?? estimationBadge}
+              {statusBadge}
+              {blockedBadge}
             </div>
           )}
         </div>
@@ -74,6 +220,68 @@ export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEv
               Active
             </span>
           )}
+          {isOpen && (
+            <>
+              <button
+                onClick={() => changeStatus('skip_task')}
+                className="p-1 hover:bg-gray-100 rounded"
+                data-testid="skip-task-button"
+              >
+                Skip
+              </button>
+              <button
+                onClick={() => changeStatus('defer_task')}
+                className="p-1 hover:bg-gray-100 rounded"
+                data-testid="defer-task-button"
+              >
+                Defer
+              </button>
+            </>
+          )}
+          {isParked && (
+            <button
+              onClick={() => changeStatus('reopen_task')}
+              className="p-1 hover:bg-gray-100 rounded"
+              data-testid="reopen-task-button"
+            >
+              Reopen
+            </button>
+          )}
+          {task.status === 'estimated' && (
+            <button
+              onClick={() => changeStatus('reestimate_task')}
+              className="p-1 hover:bg-gray-100 rounded"
+              data-testid="reestimate-task-button"
+            >
+              Re-estimate
+            </button>
+          )}
+          {previousSibling && (
+            <button
+              onClick={(e) => {
+                e.stopPropagation();
+                sendEvent({ type: 'move_task', payload: { taskId: task.id, beforeId: previousSibling.id } });
+              }}
+              className="p-1 hover:bg-gray-100 rounded"
+              aria-label="Move up"
+              data-testid="move-task-up-button"
+            >
+              ↑
+            </button>
+          )}
+          {nextSibling && (
+            <button
+              onClick={(e) => {
+                e.stopPropagation();
+                sendEvent({ type: 'move_task', payload: { taskId: task.id, afterId: nextSibling.id } });
+              }}
+              className="p-1 hover:bg-gray-100 rounded"
+              aria-label="Move down"
+              data-testid="move-task-down-button"
+            >
+              ↓
+            </button>
+          )}
           <button
             onClick={(e) => {
               e.stopPropagation();
@@ -96,6 +304,149 @@ export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEv
         </div>
       </div>
 
+      {conflict && (
+        <div className="mt-2 p-2 bg-yellow-50 border border-yellow-300 rounded text-sm" onClick={(e) => e.stopPropagation()}>
+          <span className="text-yellow-800">Someone else changed this task first, your edit was not saved.</span>
+          {lastEdit && (
+            <button onClick={() => resolveConflict(true)} className="ml-2 text-blue-600 hover:underline">
+              Apply my change on top
+            </button>
+          )}
+          <button onClick={() => resolveConflict(false)} className="ml-2 text-gray-600 hover:underline">
+            Keep theirs
+          </button>
+        </div>
+      )}
+
+      {task.labels && task.labels.length > 0 && (
+        <div className="mt-1 flex flex-wrap gap-1">
+          {task.labels.map(label => (
+            <span key={label} className="px-1.5 py-0.5 bg-gray-100 text-gray-600 rounded text-xs">
+              #{label}
+            </span>
+          ))}
+        </div>
+      )}
+
+      {isActive && (
+        <div className="mt-2 flex gap-2 text-sm" onClick={(e) => e.stopPropagation()}>
+          <select
+            value={task.type || 'story'}
+            onChange={(e) => handleTypeChange(e.target.value as TaskType)}
+            className="px-2 py-1 border rounded capitalize"
+            data-testid="task-type-select"
+          >
+            {TASK_TYPES.map(type => (
+              <option key={type} value={type}>{type}</option>
+            ))}
+          </select>
+          <input
+            type="text"
+            value={labels}
+            onChange={(e) => setLabels(e.target.value)}
+            onBlur={handleLabelsUpdate}
+            onKeyDown={(e) => e.key === 'Enter' && handleLabelsUpdate()}
+            placeholder="Labels, comma separated"
+            className="flex-1 px-2 py-1 border rounded"
+            data-testid="task-labels-input"
+          />
+        </div>
+      )}
+
+      {isActive && (
+        <div className="mt-2 flex gap-2 text-sm" onClick={(e) => e.stopPropagation()}>
+          <select
+            value={task.parentId || ''}
+            onChange={(e) => handleParentChange(e.target.value)}
+            className="max-w-[40%] px-2 py-1 border rounded"
+            data-testid="task-parent-select"
+          >
+            <option value="">No epic</option>
+            {parentOptions.map(option => (
+              <option key={option.id} value={option.id}>{option.headline}</option>
+            ))}
+          </select>
+          <input
+            type="text"
+            value={subtaskHeadline}
+            onChange={(e) => setSubtaskHeadline(e.target.value)}
+            onKeyDown={(e) => e.key === 'Enter' && handleAddSubtask()}
+            placeholder="Add subtask..."
+            className="flex-1 px-2 py-1 border rounded"
+            data-testid="add-subtask-input"
+          />
+        </div>
+      )}
+
+      {isActive && (
+        <div className="mt-2 text-sm" onClick={(e) => e.stopPropagation()}>
+          <div className="flex flex-wrap items-center gap-1">
+            <span className="text-gray-500">Depends on:</span>
+            {blockers.length === 0 && <span className="text-gray-400">nothing</span>}
+            {blockers.map(blocker => (
+              <span key={blocker.id} className="inline-flex items-center gap-1 px-1.5 py-0.5 bg-gray-100 rounded text-xs">
+                {blocker.headline}
+                {blocker.status === 'estimated' && ' ✓'}
+                <button
+                  onClick={() => changeDependency('remove_dependency', blocker.id)}
+                  className="text-gray-400 hover:text-red-600"
+                  aria-label={`Remove dependency on ${blocker.headline}`}
+                >
+                  ×
+                </button>
+              </span>
+            ))}
+            {dependencyOptions.length > 0 && (
+              <select
+                value=""
+                onChange={(e) => e.target.value && changeDependency('add_dependency', e.target.value)}
+                className="px-1 py-0.5 border rounded text-xs"
+                data-testid="add-dependency-select"
+              >
+                <option value="">+ Add dependency</option>
+                {dependencyOptions.map(option => (
+                  <option key={option.id} value={option.id}>{option.headline}</option>
+                ))}
+              </select>
+            )}
+          </div>
+        </div>
+      )}
+
+      {isActive && task.estimation && (
+        <div className="mt-2 text-sm" onClick={(e) => e.stopPropagation()}>
+          <button onClick={toggleHistory} className="text-blue-600 hover:underline">
+            {history ? 'Hide history' : 'Show estimation history'}
+          </button>
+          {history && (
+            <ul className="mt-1 space-y-1 text-gray-600">
+              {history.map(record => (
+                <li key={record.id}>
+                  <span className="font-medium">{record.value}</span>
+                  {record.source === 'override' && ' (override)'}
+                  {' · '}{new Date(record.createdAt).toLocaleString()}
+                  {record.participants.length > 0 && ` · ${record.participants.join(', ')}`}
+                  {record.reason && <div className="text-xs text-gray-500">{record.reason}</div>}
+                  {record.notes && record.notes.length > 0 && (
+                    <div className="text-xs text-gray-500">Assumptions: {record.notes.join('; ')}</div>
+                  )}
+                  {record.comments?.map((c, i) => (
+                    <div key={i} className="text-xs text-gray-500 italic">
+                      {c.value}{c.author && ` (${c.author})`}: “{c.comment}”
+                    </div>
+                  ))}
+                  {record.changes?.map((c, i) => (
+                    <div key={`change-${i}`} className="text-xs text-gray-500">
+                      {c.voter ?? 'Someone'} changed {c.from ? `from ${c.from} ` : ''}to {c.to} after the reveal
+                    </div>
+                  ))}
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      )}
+
       {/* Show full details only for active task */}
       {isActive && (task.description || task.trackerLink) && (
         <div className="mt-2 pt-2 border-t text-sm text-gray-600">
@@ -112,6 +463,12 @@ export default function TaskItem({ task, isActive, onSetActive, onDelete, sendEv
           )}
         </div>
       )}
+
+      {isActive && <AcceptanceCriteria task={task} sendEvent={sendEvent} />}
+
+      {isActive && <DecisionLog task={task} sendEvent={sendEvent} />}
+
+      {isActive && <TaskComments task={task} sendEvent={sendEvent} />}
     </div>
   );
 }
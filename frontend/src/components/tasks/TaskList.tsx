@@ -1,8 +1,28 @@
 import { useState } from 'react';
 import { Card, Button, Input } from '../common';
 import { useTasks } from '../../context/TaskContext';
-import type { ClientEvent } from '../../types';
+import { useRoom } from '../../context/RoomContext';
+import type { ClientEvent, Task, TaskFilter, TaskType, UndoAction } from '../../types';
+import { TASK_TYPES } from '../../types';
 import TaskItem from './TaskItem';
+import { taskDepth, descendantIds, rollupEstimate } from '../../lib/taskTree';
+
+// matchesFilter mirrors the backend: every non-empty list must match, any label is enough
+const matchesFilter = (task: Task, filter: TaskFilter): boolean =>
+  (!filter.types?.length || filter.types.includes(task.type || 'story')) &&
+  (!filter.statuses?.length || filter.statuses.includes(task.status)) &&
+  (!filter.labels?.length || filter.labels.some(l => task.labels?.includes(l)));
+
+const describeFilter = (filter: TaskFilter): string =>
+  [...(filter.types || []), ...(filter.labels || []).map(l => `#${l}`)].join(', ');
+
+const UNDO_LABELS: Record<UndoAction, string> = {
+  delete_task: 'Undo delete',
+  reorder_tasks: 'Undo reorder',
+  move_task: 'Undo move',
+  accept_estimate: 'Undo estimate',
+  clear: 'Undo clear',
+};
 
 interface TaskListProps {
   sendEvent: (event: ClientEvent) => void;
@@ -12,9 +32,34 @@ export default function TaskList({ sendEvent }: TaskListProps) {
   const taskContext = useTasks();
   const tasks = taskContext?.tasks || [];
   const activeTask = taskContext?.activeTask || null;
+  const proposedOrder = taskContext?.proposedOrder || null;
+  const setProposedOrder = taskContext?.setProposedOrder;
   const [isCreating, setIsCreating] = useState(false);
   const [newTaskHeadline, setNewTaskHeadline] = useState('');
   const [showOnlyUnestimated, setShowOnlyUnestimated] = useState(true);
+  const [typeFilter, setTypeFilter] = useState<TaskType | ''>('');
+  const [labelFilter, setLabelFilter] = useState('');
+  const { room, roomState, currentUserId } = useRoom();
+  const savedFilter = room?.task_filter;
+
+  // Only the author of the change or the facilitator may undo it
+  const lastChange = roomState?.lastChange;
+  const canUndo = !!lastChange && !!currentUserId &&
+    (lastChange.userId === currentUserId || roomState?.facilitatorId === currentUserId);
+
+  const viewFilter: TaskFilter = {
+    types: typeFilter ? [typeFilter] : [],
+    labels: labelFilter.trim() ? [labelFilter.trim().toLowerCase()] : [],
+  };
+  const hasViewFilter = !!typeFilter || !!labelFilter.trim();
+
+  // Saving the current view restricts which tasks "Next" picks for the whole room
+  const handleSaveFilter = (filter: TaskFilter) => {
+    sendEvent({
+      type: 'set_task_filter',
+      payload: filter
+    });
+  };
 
   const handleCreateTask = () => {
     if (newTaskHeadline.trim()) {
@@ -30,13 +75,25 @@ export default function TaskList({ sendEvent }: TaskListProps) {
 
   const handleSetActive = (taskId: string) => {
     sendEvent({
-      type: 'set_active_task',
+      type: 'jump_to_task',
       payload: { taskId }
     });
   };
 
+  const handleNavigate = (type: 'previous_task' | 'next_task') => {
+    sendEvent({ type, payload: {} });
+  };
+
+  const handleSkipActive = () => {
+    sendEvent({ type: 'skip_task', payload: {} });
+  };
+
   const handleDelete = (taskId: string) => {
-    if (confirm('Are you sure you want to delete this task?')) {
+    const hasSubtasks = tasks.some(t => t.parentId === taskId);
+    const message = hasSubtasks
+      ? 'Delete this epic? Its subtasks will move up a level.'
+      : 'Are you sure you want to delete this task?';
+    if (confirm(message)) {
       sendEvent({
         type: 'delete_task',
         payload: { taskId }
@@ -44,12 +101,46 @@ export default function TaskList({ sendEvent }: TaskListProps) {
     }
   };
 
-  const estimatedCount = tasks.filter(t => t.estimation && t.estimation !== '?').length;
+  const handleUndo = () => {
+    sendEvent({ type: 'undo', payload: {} });
+  };
+
+  const handleProposeOrder = () => {
+    sendEvent({ type: 'propose_task_order', payload: {} });
+  };
+
+  const handleApplyOrder = () => {
+    if (proposedOrder) {
+      sendEvent({ type: 'reorder_tasks', payload: { taskIds: proposedOrder } });
+    }
+  };
+
+  // Count the tasks the proposal would move, unchanged proposals are not worth showing
+  const proposedMoves = proposedOrder ? proposedOrder.filter((id, i) => tasks[i]?.id !== id).length : 0;
+
+  // Tasks move one step at a time among the tasks sharing their epic
+  const neighboursOf = (task: Task): [Task | undefined, Task | undefined] => {
+    const siblings = tasks.filter(t => (t.parentId || '') === (task.parentId || ''));
+    const index = siblings.findIndex(t => t.id === task.id);
+    return [siblings[index - 1], siblings[index + 1]];
+  };
+
+  const blockersOf = (task: Task): Task[] =>
+    (task.dependsOn || []).flatMap(id => tasks.filter(t => t.id === id));
+
+  // A task can move under any task except itself and its own subtasks
+  const parentOptions = (task: Task): Task[] => {
+    const below = descendantIds(task.id, tasks);
+    return tasks.filter(t => t.id !== task.id && !below.has(t.id));
+  };
+
+  const estimatedCount = tasks.filter(t => t.status === 'estimated').length;
   const totalCount = tasks.length;
 
-  const displayedTasks = showOnlyUnestimated
-    ? tasks.filter(t => !t.estimation || t.estimation === '?')
-    : tasks.filter(t => t.estimation && t.estimation !== '?');
+  const displayedTasks = (showOnlyUnestimated
+    ? tasks.filter(t => t.status !== 'estimated')
+    : tasks.filter(t => t.status === 'estimated')
+  ).filter(t => matchesFilter(t, viewFilter));
 
   return (
     <Card variant="outlined" padding="md">
@@ -61,6 +152,23 @@ export default function TaskList({ sendEvent }: TaskListProps) {
           </span>
         </div>
 
+        <div className="flex gap-2 mb-2">
+          <Button variant="outline" size="sm" onClick={() => handleNavigate('previous_task')} disabled={!activeTask}>
+            ← Prev
+          </Button>
+          <Button variant="outline" size="sm" onClick={handleSkipActive} disabled={!activeTask}>
+            Skip
+          </Button>
+          <Button variant="outline" size="sm" onClick={() => handleNavigate('next_task')}>
+            Next →
+          </Button>
+          {canUndo && lastChange && (
+            <Button variant="outline" size="sm" onClick={handleUndo} data-testid="undo-button">
+              {UNDO_LABELS[lastChange.action]}
+            </Button>
+          )}
+        </div>
+
         <div className="flex gap-2 mb-2">
           <button
             onClick={() => setShowOnlyUnestimated(true)}
@@ -84,6 +192,63 @@ export default function TaskList({ sendEvent }: TaskListProps) {
           </button>
         </div>
 
+        <div className="flex gap-2 mb-2 text-sm">
+          <select
+            value={typeFilter}
+            onChange={(e) => setTypeFilter(e.target.value as TaskType | '')}
+            className="px-2 py-1 border rounded capitalize"
+            data-testid="task-type-filter"
+          >
+            <option value="">All types</option>
+            {TASK_TYPES.map(type => (
+              <option key={type} value={type}>{type}</option>
+            ))}
+          </select>
+          <input
+            type="text"
+            value={labelFilter}
+            onChange={(e) => setLabelFilter(e.target.value)}
+            placeholder="Label"
+            className="flex-1 min-w-0 px-2 py-1 border rounded"
+            data-testid="task-label-filter"
+          />
+          <Button
+            variant="outline"
+            size="sm"
+            onClick={() => handleSaveFilter(viewFilter)}
+            disabled={!hasViewFilter}
+            title="Only pick matching tasks when moving to the next task"
+          >
+            Estimate only these
+          </Button>
+        </div>
+
+        <div className="flex items-center gap-2 mb-2 text-sm">
+          <Button variant="outline" size="sm" onClick={handleProposeOrder} disabled={!tasks.some(t => t.dependsOn?.length)}>
+            Suggest order from dependencies
+          </Button>
+          {proposedOrder && (
+            proposedMoves > 0 ? (
+              <>
+                <span className="text-gray-600">{proposedMoves} tasks would move</span>
+                <button onClick={handleApplyOrder} className="text-blue-600 hover:underline">Apply</button>
+                <button onClick={() => setProposedOrder?.(null)} className="text-gray-500 hover:underline">Dismiss</button>
+              </>
+            ) : (
+              <span className="text-gray-500">Backlog already respects dependencies</span>
+            )
+          )}
+        </div>
+
+        {savedFilter && describeFilter(savedFilter) && (
+          <div className="flex items-center justify-between mb-2 px-2 py-1 bg-yellow-50 text-yellow-800 rounded text-xs">
+            <span>Next task limited to: {describeFilter(savedFilter)}</span>
+            <button onClick={() => handleSaveFilter({})} className="hover:underline">
+              Clear
+            </button>
+          </div>
+        )}
+
         {!isCreating ? (
           <Button
             variant="outline"
@@ -112,16 +277,26 @@ export default function TaskList({ sendEvent }: TaskListProps) {
       </div>
 
       <div className="space-y-2" data-testid="task-list">
-        {displayedTasks.map(task => (
-          <TaskItem
-            key={task.id}
-            task={task}
-            isActive={task.id === activeTask?.id}
-            onSetActive={() => handleSetActive(task.id)}
-            onDelete={() => handleDelete(task.id)}
-            sendEvent={sendEvent}
-          />
-        ))}
+        {displayedTasks.map(task => {
+          const [previous, next] = neighboursOf(task);
+          return (
+            <TaskItem
+              key={task.id}
+              task={task}
+              isActive={task.id === activeTask?.id}
+              depth={taskDepth(task, tasks)}
+              rollup={rollupEstimate(task.id, tasks)}
+              parentOptions={task.id === activeTask?.id ? parentOptions(task) : []}
+              blockers={blockersOf(task)}
+              dependencyOptions={task.id === activeTask?.id ? tasks.filter(t => t.id !== task.id && !task.dependsOn?.includes(t.id)) : []}
+              previousSibling={previous}
+              nextSibling={next}
+              onSetActive={() => handleSetActive(task.id)}
+              onDelete={() => handleDelete(task.id)}
+              sendEvent={sendEvent}
+            />
+          );
+        })}
 
         {displayedTasks.length === 0 && tasks.length > 0 && (
           <div className="text-center py-8 text-gray-400">
@@ -39,6 +39,14 @@ export default function UserCard({ user, isCurrentUser = false }: UserCardProps)
                 You
               </span>
             )}
+            {user.raisedHand && (
+              <span
+                className="text-xs bg-amber-100 text-amber-800 px-2 py-0.5 rounded"
+                title={user.raisedHand === 'split' ? 'This story is too big, split it' : 'Has a question before we vote'}
+              >
+                {user.raisedHand === 'split' ? '✂' : '✋'} #{user.handPosition}
+              </span>
+            )}
           </div>
         </div>
       </div>
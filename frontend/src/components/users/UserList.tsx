@@ -1,11 +1,35 @@
 import { Card } from '../common';
 import { useRoom } from '../../context/RoomContext';
 import UserCard from './UserCard';
+import type { ClientEvent, HandSignal } from '../../types';
 
-export default function UserList() {
+interface UserListProps {
+  sendEvent?: (event: ClientEvent) => void;
+}
+
+const HAND_LABELS: Record<HandSignal, string> = {
+  question: 'Question',
+  split: 'Split it',
+};
+
+export default function UserList({ sendEvent }: UserListProps) {
   const { roomState, currentUser } = useRoom();
 
   const users = roomState?.users || [];
+  const handQueue = users
+    .filter((u) => u.raisedHand && u.handPosition)
+    .sort((a, b) => (a.handPosition ?? 0) - (b.handPosition ?? 0));
+  const me = users.find((u) => u.id === currentUser?.id);
+  // Only the facilitator may call on someone else
+  const isFacilitator = !!me && roomState?.facilitatorId === me.id;
+
+  const raiseHand = (signal: HandSignal) => {
+    sendEvent?.({ type: 'raise_hand', payload: { signal } });
+  };
+
+  const lowerHand = (userId?: string) => {
+    sendEvent?.({ type: 'lower_hand', payload: userId ? { userId } : {} });
+  };
   const votedCount = users.filter((u) => u.isVoted).length;
   const totalCount = users.length;
 
@@ -30,6 +54,62 @@ export default function UserList() {
         </div>
       )}
 
+      {/* Hand Signals */}
+      {sendEvent && me && (
+        <div className="flex flex-wrap gap-2 mb-4">
+          {me.raisedHand ? (
+            <button
+              onClick={() => lowerHand()}
+              className="text-xs px-2 py-1 rounded border border-gray-300 hover:bg-gray-50"
+            >
+              Lower hand
+            </button>
+          ) : (
+            <>
+              <button
+                onClick={() => raiseHand('question')}
+                className="text-xs px-2 py-1 rounded border border-gray-300 hover:bg-gray-50"
+                title="I have a question before we vote"
+              >
+                ✋ Question
+              </button>
+              <button
+                onClick={() => raiseHand('split')}
+                className="text-xs px-2 py-1 rounded border border-gray-300 hover:bg-gray-50"
+                title="This story is too big, split it"
+              >
+                ✂ Split it
+              </button>
+            </>
+          )}
+        </div>
+      )}
+
+      {/* Hand Queue */}
+      {handQueue.length > 0 && (
+        <div className="mb-4" data-testid="hand-queue">
+          <h4 className="text-sm font-medium text-gray-700 mb-2">Raised Hands</h4>
+          <ol className="space-y-1 text-sm">
+            {handQueue.map((user) => (
+              <li key={user.id} className="flex items-center justify-between">
+                <span>
+                  {user.handPosition}. {user.name}{' '}
+                  <span className="text-xs text-amber-700">{user.raisedHand && HAND_LABELS[user.raisedHand]}</span>
+                </span>
+                {sendEvent && isFacilitator && (
+                  <button
+                    onClick={() => lowerHand(user.id)}
+                    className="text-xs text-blue-600 hover:underline"
+                  >
+                    Call on
+                  </button>
+                )}
+              </li>
+            ))}
+          </ol>
+        </div>
+      )}
+
       {/* User List */}
       <div className="space-y-2" data-testid="user-list">
         {users.length === 0 ? (
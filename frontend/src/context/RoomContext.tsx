@@ -1,6 +1,6 @@
 import React, { createContext, useContext, useState, useCallback, useMemo } from 'react';
 import type { ReactNode } from 'react';
-import type { Room, User, Vote, RoomState, NewRoomReq } from '../types';
+import type { Room, User, Vote, RoomState, NewRoomReq, DimensionResult, EstimationDimension, DimensionCombination, EstimationMode, PertResult, VoteChange, VoteChangePolicy, RaisedHand, TaskFilter, Change } from '../types';
 import { api } from '../services/api';
 
 interface RoomContextState {
@@ -17,17 +17,49 @@ interface RoomContextState {
   currentUser: User | null;
 
   // Actions
-  newRoom: (roomName: string, nickname: string) => Promise<string>;
+  newRoom: (roomName: string, nickname: string, options?: NewRoomOptions) => Promise<string>;
   joinRoom: (roomId: string, nickname: string) => Promise<void>;
   leaveRoom: () => Promise<void>;
   setRoomState: (state: RoomState) => void;
   updateUsers: (users: User[]) => void;
   updateUserVoteStatus: (userId: string, hasVoted: boolean) => void;
+  upsertUser: (user: User) => void;
+  removeUser: (userId: string, facilitatorId?: string) => void;
+  renameUser: (userId: string, name: string) => void;
+  setHands: (hands: RaisedHand[]) => void;
   updateVotes: (votes: Vote[]) => void;
-  setRevealed: (revealed: boolean, average?: number | null) => void;
+  setRevealed: (revealed: boolean, average?: number | null, suggested?: string, dimensionResults?: DimensionResult[], pert?: PertResult, changes?: VoteChange[]) => void;
+  resetRound: () => void;
+  setTaskDescription: (description: string) => void;
+  setTaskFilter: (filter: TaskFilter) => void;
+  setLastChange: (change: Change | null) => void;
   clearError: () => void;
 }
 
+interface NewRoomOptions {
+  dimensions?: EstimationDimension[];
+  combination?: DimensionCombination;
+  mode?: EstimationMode;
+  anonymousVotes?: boolean;
+  voteChangePolicy?: VoteChangePolicy;
+  requireAcceptanceCriteria?: boolean;
+}
+
+// handQueue rebuilds the queue from the users' hands, first raised first
+const handQueue = (users: User[]): RaisedHand[] =>
+  users
+    .flatMap(u => (u.raisedHand && u.handPosition ? [{ userId: u.id, signal: u.raisedHand, position: u.handPosition }] : []))
+    .sort((a, b) => a.position - b.position)
+    .map((hand, i) => ({ ...hand, position: i + 1 }));
+
+// withHands applies a hand queue to the users, lowering every hand not in it
+const withHands = (users: User[], hands: RaisedHand[]): User[] =>
+  users.map(u => {
+    const hand = hands.find(h => h.userId === u.id);
+    const position = hand ? hands.indexOf(hand) + 1 : undefined;
+    return { ...u, raisedHand: hand?.signal, handPosition: position };
+  });
+
 const RoomContext = createContext<RoomContextState | undefined>(undefined);
 
 interface RoomProviderProps {
@@ -46,7 +78,7 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
     setError(null);
   }, []);
 
-  const newRoom = useCallback(async (roomName: string, nickname: string): Promise<string> => {
+  const newRoom = useCallback(async (roomName: string, nickname: string, options?: NewRoomOptions): Promise<string> => {
     setIsLoading(true);
     setError(null);
 
@@ -55,6 +87,12 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
         name: roomName,
         voting_system: 'fibonacci',
         auto_reveal: false,
+        dimensions: options?.dimensions,
+        combination: options?.combination,
+        estimation_mode: options?.mode,
+        anonymous_votes: options?.anonymousVotes,
+        vote_change_policy: options?.voteChangePolicy,
+        require_acceptance_criteria: options?.requireAcceptanceCriteria,
       };
 
       const response = await api.newRoom(request);
@@ -65,6 +103,12 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
         name: response.name,
         voting_system: response.voting_system,
         auto_reveal: response.auto_reveal,
+        dimensions: response.dimensions,
+        combination: response.combination,
+        estimation_mode: response.estimation_mode,
+        anonymous_votes: response.anonymous_votes,
+        vote_change_policy: response.vote_change_policy,
+        require_acceptance_criteria: response.require_acceptance_criteria,
         created_at: response.created_at,
         updated_at: response.created_at,
       };
@@ -168,6 +212,55 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
     });
   }, []);
 
+  const upsertUser = useCallback((user: User) => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      const exists = prev.users.some(u => u.id === user.id);
+      return {
+        ...prev,
+        users: exists
+          ? prev.users.map(u => u.id === user.id ? { ...u, ...user } : u)
+          : [...prev.users, user],
+      };
+    });
+  }, []);
+
+  const removeUser = useCallback((userId: string, facilitatorId?: string) => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      return {
+        ...prev,
+        facilitatorId: facilitatorId ?? prev.facilitatorId,
+        users: withHands(
+          prev.users.filter(u => u.id !== userId),
+          handQueue(prev.users).filter(h => h.userId !== userId)
+        ),
+        votes: prev.votes.filter(v => v.userId !== userId),
+      };
+    });
+  }, []);
+
+  const renameUser = useCallback((userId: string, name: string) => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      return {
+        ...prev,
+        users: prev.users.map(u => u.id === userId ? { ...u, name } : u),
+        votes: prev.votes.map(v => v.userId === userId ? { ...v, userName: name } : v),
+      };
+    });
+  }, []);
+
+  const setHands = useCallback((hands: RaisedHand[]) => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      return {
+        ...prev,
+        users: withHands(prev.users, hands),
+      };
+    });
+  }, []);
+
   const updateVotes = useCallback((votes: Vote[]) => {
     setRoomStateInternal((prev) => {
       if (!prev) return null;
@@ -178,13 +271,58 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
     });
   }, []);
 
-  const setRevealed = useCallback((revealed: boolean, average?: number | null) => {
+  const setRevealed = useCallback((revealed: boolean, average?: number | null, suggested?: string, dimensionResults?: DimensionResult[], pert?: PertResult, changes?: VoteChange[]) => {
     setRoomStateInternal((prev) => {
       if (!prev) return null;
       return {
         ...prev,
         isRevealed: revealed,
         average: revealed ? average : undefined,
+        suggested: revealed ? suggested : undefined,
+        dimensionResults: revealed ? dimensionResults : undefined,
+        pert: revealed ? pert : undefined,
+        changes: revealed ? changes : undefined,
+      };
+    });
+  }, []);
+
+  const resetRound = useCallback(() => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      return {
+        ...prev,
+        users: withHands(prev.users.map(u => ({ ...u, isVoted: false })), []),
+        votes: [],
+        isRevealed: false,
+        average: undefined,
+        suggested: undefined,
+        dimensionResults: undefined,
+        pert: undefined,
+        changes: undefined,
+      };
+    });
+  }, []);
+
+  const setTaskDescription = useCallback((description: string) => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      return {
+        ...prev,
+        taskDescription: description,
+      };
+    });
+  }, []);
+
+  const setTaskFilter = useCallback((filter: TaskFilter) => {
+    setRoom((prev) => (prev ? { ...prev, task_filter: filter } : prev));
+  }, []);
+
+  const setLastChange = useCallback((change: Change | null) => {
+    setRoomStateInternal((prev) => {
+      if (!prev) return null;
+      return {
+        ...prev,
+        lastChange: change,
       };
     });
   }, []);
@@ -202,8 +340,16 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
     setRoomState,
     updateUsers,
     updateUserVoteStatus,
+    upsertUser,
+    removeUser,
+    renameUser,
+    setHands,
     updateVotes,
     setRevealed,
+    resetRound,
+    setTaskDescription,
+    setTaskFilter,
+    setLastChange,
     clearError,
   }), [
     room,
@@ -218,8 +364,16 @@ export const RoomProvider: React.FC<RoomProviderProps> = ({ children }) => {
     setRoomState,
     updateUsers,
     updateUserVoteStatus,
+    upsertUser,
+    removeUser,
+    renameUser,
+    setHands,
     updateVotes,
     setRevealed,
+    resetRound,
+    setTaskDescription,
+    setTaskFilter,
+    setLastChange,
     clearError,
   ]);
 
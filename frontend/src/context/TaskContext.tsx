@@ -1,6 +1,6 @@
 import React, { createContext, useContext, useState, useCallback, useMemo } from 'react';
 import type { ReactNode } from 'react';
-import type { Task } from '../types';
+import type { Task, TaskComment } from '../types';
 
 interface TaskContextState {
   tasks: Task[];
@@ -12,6 +12,20 @@ interface TaskContextState {
   updateTask: (task: Task) => void;
   removeTask: (taskId: string) => void;
   reorderTasks: (taskIds: string[]) => void;
+
+  // Dependency-respecting order suggested by the server, waiting to be applied or dismissed
+  proposedOrder: string[] | null;
+  setProposedOrder: (taskIds: string[] | null) => void;
+
+  // Current version of a task whose edit was rejected because someone else saved it first
+  conflictedTask: Task | null;
+  setConflictedTask: (task: Task | null) => void;
+
+  // Discussion threads loaded so far, by task ID
+  comments: Record<string, TaskComment[]>;
+  setTaskComments: (taskId: string, comments: TaskComment[]) => void;
+  upsertComment: (comment: TaskComment) => void;
+  removeComment: (taskId: string, commentId: string) => void;
 }
 
 const TaskContext = createContext<TaskContextState | undefined>(undefined);
@@ -52,10 +66,14 @@ export const TaskProvider: React.FC<TaskProviderProps> = ({ children }) => {
     setTasksInternal(prev =>
       prev.map(t => t.id === updatedTask.id ? updatedTask : t)
     );
+    setActiveTaskInternal(prev => prev?.id === updatedTask.id ? updatedTask : prev);
   }, []);
 
   const removeTask = useCallback((taskId: string) => {
-    setTasksInternal(prev => prev.filter(t => t.id !== taskId));
+    // The server drops dependencies on a deleted task along with it
+    setTasksInternal(prev => prev
+      .filter(t => t.id !== taskId)
+      .map(t => t.dependsOn?.includes(taskId) ? { ...t, dependsOn: t.dependsOn.filter(id => id !== taskId) } : t));
     setActiveTaskInternal(prev => prev?.id === taskId ? null : prev);
   }, []);
 
@@ -69,6 +87,39 @@ export const TaskProvider: React.FC<TaskProviderProps> = ({ children }) => {
     });
   }, []);
 
+  const [proposedOrder, setProposedOrder] = useState<string[] | null>(null);
+  const [conflictedTask, setConflictedTask] = useState<Task | null>(null);
+
+  const [comments, setComments] = useState<Record<string, TaskComment[]>>({});
+
+  const setTaskComments = useCallback((taskId: string, taskComments: TaskComment[]) => {
+    setComments(prev => ({ ...prev, [taskId]: taskComments }));
+  }, []);
+
+  const upsertComment = useCallback((comment: TaskComment) => {
+    setComments(prev => {
+      // Threads that were never opened are fetched in full when they are
+      const thread = prev[comment.taskId];
+      if (!thread) {
+        return prev;
+      }
+      const updated = thread.some(c => c.id === comment.id)
+        ? thread.map(c => c.id === comment.id ? comment : c)
+        : [...thread, comment];
+      return { ...prev, [comment.taskId]: updated };
+    });
+  }, []);
+
+  const removeComment = useCallback((taskId: string, commentId: string) => {
+    setComments(prev => {
+      const thread = prev[taskId];
+      if (!thread) {
+        return prev;
+      }
+      return { ...prev, [taskId]: thread.filter(c => c.id !== commentId) };
+    });
+  }, []);
+
   const value: TaskContextState = useMemo(() => ({
     tasks,
     activeTask,
@@ -78,7 +129,15 @@ export const TaskProvider: React.FC<TaskProviderProps> = ({ children }) => {
     updateTask,
     removeTask,
     reorderTasks,
-  }), [tasks, activeTask, setTasks, setActiveTask, addTask, updateTask, removeTask, reorderTasks]);
+    proposedOrder,
+    setProposedOrder,
+    conflictedTask,
+    setConflictedTask,
+    comments,
+    setTaskComments,
+    upsertComment,
+    removeComment,
+  }), [tasks, activeTask, setTasks, setActiveTask, addTask, updateTask, removeTask, reorderTasks, proposedOrder, conflictedTask, comments, setTaskComments, upsertComment, removeComment]);
 
   return <TaskContext.Provider value={value}>{children}</TaskContext.Provider>;
 };
@@ -1,10 +1,27 @@
 import { useEffect, useRef, useCallback, useState } from 'react';
 import { WebSocketClient } from '../services/websocket';
 import type { ConnectionState } from '../services/websocket';
-import type { ServerEvent, ClientEvent, Task, TaskListSyncPayload } from '../types';
+import type {
+  ServerEvent,
+  ClientEvent,
+  Task,
+  TaskListSyncPayload,
+  UserJoinedPayload,
+  UserLeftPayload,
+  UserUpdatedPayload,
+  RoundResetPayload,
+  VotesRevealedPayload,
+  HandsUpdatedPayload,
+  ActiveTaskSetPayload,
+  TaskComment,
+  CommentDeletedPayload,
+  TaskFilter,
+  TaskOrderProposedPayload,
+  UndoUpdatedPayload,
+  ErrorPayload,
+} from '../types';
 import { useRoom } from '../context/RoomContext';
 import { useTasks } from '../context/TaskContext';
-import { api } from '../services/api';
 
 interface UseWebSocketReturn {
   isConnected: boolean;
@@ -14,7 +31,22 @@ interface UseWebSocketReturn {
 }
 
 export const useWebSocket = (roomId: string): UseWebSocketReturn => {
-  const { currentUserId, currentUser, setRoomState, updateVotes, setRevealed, updateUserVoteStatus } = useRoom();
+  const {
+    currentUserId,
+    currentUser,
+    setRoomState,
+    updateVotes,
+    setRevealed,
+    updateUserVoteStatus,
+    upsertUser,
+    removeUser,
+    renameUser,
+    setHands,
+    resetRound,
+    setTaskDescription,
+    setTaskFilter,
+    setLastChange,
+  } = useRoom();
   const taskContext = useTasks();
   const tasks = taskContext?.tasks || [];
   const activeTask = taskContext?.activeTask || null;
@@ -24,6 +56,10 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
   const removeTask = taskContext?.removeTask;
   const reorderTasks = taskContext?.reorderTasks;
   const setActiveTask = taskContext?.setActiveTask;
+  const upsertComment = taskContext?.upsertComment;
+  const removeComment = taskContext?.removeComment;
+  const setProposedOrder = taskContext?.setProposedOrder;
+  const setConflictedTask = taskContext?.setConflictedTask;
   const [connectionState, setConnectionState] = useState<ConnectionState>('disconnected');
   const wsClient = useRef<WebSocketClient | null>(null);
   const hasInitialized = useRef(false);
@@ -33,12 +69,24 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
   const updateVotesRef = useRef(updateVotes);
   const setRevealedRef = useRef(setRevealed);
   const updateUserVoteStatusRef = useRef(updateUserVoteStatus);
+  const upsertUserRef = useRef(upsertUser);
+  const removeUserRef = useRef(removeUser);
+  const renameUserRef = useRef(renameUser);
+  const setHandsRef = useRef(setHands);
+  const resetRoundRef = useRef(resetRound);
+  const setTaskDescriptionRef = useRef(setTaskDescription);
+  const setTaskFilterRef = useRef(setTaskFilter);
+  const setLastChangeRef = useRef(setLastChange);
   const setTasksRef = useRef(setTasks);
   const addTaskRef = useRef(addTask);
   const updateTaskRef = useRef(updateTask);
   const removeTaskRef = useRef(removeTask);
   const reorderTasksRef = useRef(reorderTasks);
   const setActiveTaskRef = useRef(setActiveTask);
+  const upsertCommentRef = useRef(upsertComment);
+  const removeCommentRef = useRef(removeComment);
+  const setProposedOrderRef = useRef(setProposedOrder);
+  const setConflictedTaskRef = useRef(setConflictedTask);
   const tasksRef = useRef(tasks);
   const activeTaskRef = useRef(activeTask);
 
@@ -48,25 +96,54 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
     updateVotesRef.current = updateVotes;
     setRevealedRef.current = setRevealed;
     updateUserVoteStatusRef.current = updateUserVoteStatus;
+    upsertUserRef.current = upsertUser;
+    removeUserRef.current = removeUser;
+    renameUserRef.current = renameUser;
+    setHandsRef.current = setHands;
+    resetRoundRef.current = resetRound;
+    setTaskDescriptionRef.current = setTaskDescription;
+    setTaskFilterRef.current = setTaskFilter;
+    setLastChangeRef.current = setLastChange;
     setTasksRef.current = setTasks;
     addTaskRef.current = addTask;
     updateTaskRef.current = updateTask;
     removeTaskRef.current = removeTask;
     reorderTasksRef.current = reorderTasks;
     setActiveTaskRef.current = setActiveTask;
+    upsertCommentRef.current = upsertComment;
+    removeCommentRef.current = removeComment;
+    setProposedOrderRef.current = setProposedOrder;
+    setConflictedTaskRef.current = setConflictedTask;
     tasksRef.current = tasks;
     activeTaskRef.current = activeTask;
-  }, [setRoomState, updateVotes, setRevealed, updateUserVoteStatus, setTasks, addTask, updateTask, removeTask, reorderTasks, setActiveTask, tasks, activeTask]);
-
-  const fetchRoomState = useCallback(async () => {
-    if (!roomId) return;
-    try {
-      const state = await api.getRoomState(roomId);
-      setRoomStateRef.current(state);
-    } catch (error) {
-      console.error('Failed to fetch room state:', error);
+  }, [setRoomState, updateVotes, setRevealed, updateUserVoteStatus, upsertUser, removeUser, renameUser, setHands, resetRound, setTaskDescription, setTaskFilter, setLastChange, setTasks, addTask, updateTask, removeTask, reorderTasks, setActiveTask, upsertComment, removeComment, setProposedOrder, setConflictedTask, tasks, activeTask]);
+
+  const activateTask = useCallback((taskId: string | undefined, details?: Task) => {
+    if (!taskId) {
+      setActiveTaskRef.current?.(null);
+      return;
+    }
+    // Prefer the details sent by the server, they carry the latest status
+    if (details) {
+      updateTaskRef.current?.(details);
+      setActiveTaskRef.current?.(details);
+      return;
+    }
+    // Find task in current list and set as active
+    const task = tasksRef.current.find(t => t.id === taskId);
+    if (task && setActiveTaskRef.current) {
+      setActiveTaskRef.current(task);
+    } else if (taskId && !task) {
+      // Task not found in current list, might arrive in next task_list_sync
+      // Set a timeout to retry after a brief delay
+      setTimeout(() => {
+        const retryTask = tasksRef.current.find(t => t.id === taskId);
+        if (retryTask && setActiveTaskRef.current) {
+          setActiveTaskRef.current(retryTask);
+        }
+      }, 100);
     }
-  }, [roomId]);
+  }, []);
 
   const handleMessage = useCallback(
     (event: ServerEvent) => {
@@ -78,11 +155,21 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
           break;
         }
 
-        case 'user_joined':
-        case 'user_left':
+        case 'user_joined': {
+          const { userId, nickname, isVoted, isOnline } = event.payload as UserJoinedPayload;
+          upsertUserRef.current({ id: userId, name: nickname, isVoted, isOnline });
+          break;
+        }
+
+        case 'user_left': {
+          const { userId, facilitatorId } = event.payload as UserLeftPayload;
+          removeUserRef.current(userId, facilitatorId);
+          break;
+        }
+
         case 'user_updated': {
-          // Refresh room state from server
-          fetchRoomState();
+          const { userId, nickname } = event.payload as UserUpdatedPayload;
+          renameUserRef.current(userId, nickname);
           break;
         }
 
@@ -95,24 +182,56 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
 
         case 'votes_revealed': {
           // Show votes and average
-          const { votes, average } = event.payload;
+          const { votes, average, suggested, dimensions, pert, changes } = event.payload as VotesRevealedPayload;
           updateVotesRef.current(votes);
-          setRevealedRef.current(true, average);
+          setRevealedRef.current(true, average, suggested, dimensions, pert, changes);
           break;
         }
 
         case 'votes_cleared': {
           // Clear votes for new round
-          // Backend will send updated room_state right after this event
           updateVotesRef.current([]);
           setRevealedRef.current(false);
+          setHandsRef.current([]);
+          break;
+        }
+
+        case 'hands_updated': {
+          const { hands } = event.payload as HandsUpdatedPayload;
+          setHandsRef.current(hands);
+          break;
+        }
+
+        case 'round_reset': {
+          // Votes cleared, estimation saved and next task picked in one step
+          const { activeTaskId, activeTask, estimatedTask } = event.payload as RoundResetPayload;
+          resetRoundRef.current();
+          if (estimatedTask) {
+            updateTaskRef.current?.(estimatedTask);
+          }
+          activateTask(activeTaskId, activeTask);
+          break;
+        }
+
+        case 'task_description_set': {
+          const { description } = event.payload as { description: string };
+          setTaskDescriptionRef.current(description);
+          break;
+        }
+
+        case 'task_filter_set': {
+          setTaskFilterRef.current(event.payload as TaskFilter);
           break;
         }
 
         case 'error': {
-          // Handle error
-          const { message } = event.payload;
+          const { message, code, task } = event.payload as ErrorPayload;
           console.error('WebSocket error:', message);
+          // Our edit lost against someone else's: show their version and let the user decide
+          if (code === 'CONFLICT' && task) {
+            updateTaskRef.current?.(task);
+            setConflictedTaskRef.current?.(task);
+          }
           break;
         }
 
@@ -153,25 +272,40 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
         case 'tasks_reordered': {
           const { taskIds } = event.payload as { taskIds: string[] };
           reorderTasksRef.current?.(taskIds);
+          setProposedOrderRef.current?.(null);
+          break;
+        }
+
+        case 'task_order_proposed': {
+          const { taskIds } = event.payload as TaskOrderProposedPayload;
+          setProposedOrderRef.current?.(taskIds);
+          break;
+        }
+
+        case 'undo_updated': {
+          // The restored tasks and round arrive as their own events, only the undo target changes here
+          const { lastChange } = event.payload as UndoUpdatedPayload;
+          setLastChangeRef.current(lastChange);
           break;
         }
 
         case 'active_task_set': {
-          const { taskId } = event.payload as { taskId: string };
-          // Find task in current list and set as active
-          const task = tasksRef.current.find(t => t.id === taskId);
-          if (task && setActiveTaskRef.current) {
-            setActiveTaskRef.current(task);
-          } else if (taskId && !task) {
-            // Task not found in current list, might arrive in next task_list_sync
-            // Set a timeout to retry after a brief delay
-            setTimeout(() => {
-              const retryTask = tasksRef.current.find(t => t.id === taskId);
-              if (retryTask && setActiveTaskRef.current) {
-                setActiveTaskRef.current(retryTask);
-              }
-            }, 100);
-          }
+          // Navigation always starts a fresh round on the new task
+          const { taskId, task } = event.payload as ActiveTaskSetPayload;
+          resetRoundRef.current();
+          activateTask(taskId, task);
+          break;
+        }
+
+        case 'comment_added':
+        case 'comment_updated': {
+          upsertCommentRef.current?.(event.payload as TaskComment);
+          break;
+        }
+
+        case 'comment_deleted': {
+          const { taskId, commentId } = event.payload as CommentDeletedPayload;
+          removeCommentRef.current?.(taskId, commentId);
           break;
         }
 
@@ -179,7 +313,7 @@ export const useWebSocket = (roomId: string): UseWebSocketReturn => {
           console.warn('Unknown WebSocket event type:', event.type);
       }
     },
-    [fetchRoomState]
+    [activateTask]
   );
 
   const handleStateChange = useCallback((state: ConnectionState) => {
@@ -0,0 +1,54 @@
+import type { Task, EstimateRollup } from '../types';
+
+const subtasksOf = (taskId: string, tasks: Task[]): Task[] =>
+  tasks.filter(t => t.parentId === taskId);
+
+// taskDepth counts the epics above a task, 0 for top-level tasks
+export const taskDepth = (task: Task, tasks: Task[]): number => {
+  let depth = 0;
+  const seen = new Set<string>();
+  for (let parentId = task.parentId; parentId && !seen.has(parentId); depth++) {
+    seen.add(parentId);
+    parentId = tasks.find(t => t.id === parentId)?.parentId;
+  }
+  return depth;
+};
+
+// descendantIds lists every task below an epic; none of them can become its parent
+export const descendantIds = (taskId: string, tasks: Task[]): Set<string> => {
+  const ids = new Set<string>();
+  const walk = (id: string) => {
+    for (const sub of subtasksOf(id, tasks)) {
+      if (!ids.has(sub.id)) {
+        ids.add(sub.id);
+        walk(sub.id);
+      }
+    }
+  };
+  walk(taskId);
+  return ids;
+};
+
+// rollupEstimate mirrors the backend: the numeric estimates of an epic's leaf subtasks summed up.
+// Computed locally so epics follow their subtasks' task_updated events.
+export const rollupEstimate = (taskId: string, tasks: Task[]): EstimateRollup | undefined => {
+  if (subtasksOf(taskId, tasks).length === 0) {
+    return undefined;
+  }
+  let points = 0;
+  let estimated = 0;
+  let subtasks = 0;
+  for (const id of descendantIds(taskId, tasks)) {
+    if (subtasksOf(id, tasks).length > 0) {
+      continue;
+    }
+    subtasks++;
+    const estimation = tasks.find(t => t.id === id)?.estimation;
+    const value = Number(estimation);
+    if (estimation && !Number.isNaN(value)) {
+      points += value;
+      estimated++;
+    }
+  }
+  return { points: String(points), estimated, subtasks };
+};
@@ -8,6 +8,7 @@ import UserList from '../components/users/UserList';
 import ResultsDisplay from '../components/estimation/ResultsDisplay';
 import TaskList from '../components/tasks/TaskList';
 import { TaskProvider } from '../context/TaskContext';
+import type { AcceptEstimatePayload } from '../types';
 
 function RoomContent() {
   const { roomId } = useParams<{ roomId: string }>();
@@ -50,6 +51,10 @@ function RoomContent() {
 
   const handleClear = () => {
     sendEvent({ type: 'clear', payload: {} });
+  };
+
+  const handleAccept = (payload: AcceptEstimatePayload) => {
+    sendEvent({ type: 'accept_estimate', payload });
     setTaskDescription('');
   };
 
@@ -134,7 +139,7 @@ function RoomContent() {
           <div className="grid grid-cols-1 lg:grid-cols-3 gap-6">
             {/* Left Column - Users */}
             <div className="lg:col-span-1">
-              <UserList />
+              <UserList sendEvent={sendEvent} />
             </div>
 
             {/* Middle Column - Tasks */}
@@ -185,7 +190,7 @@ function RoomContent() {
 
               {/* Results (when revealed) */}
               {roomState?.isRevealed && (
-                <ResultsDisplay onClear={handleClear} />
+                <ResultsDisplay onAccept={handleAccept} onRevote={handleClear} />
               )}
 
               {/* Voting Panel (always shown) */}
@@ -1,4 +1,4 @@
-import type { NewRoomReq, NewRoomResp, Room, RoomState } from '../types';
+import type { NewRoomReq, NewRoomResp, Room, RoomState, EstimationRecord, TaskCommentPage } from '../types';
 
 const API_BASE_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080';
 
@@ -148,9 +148,13 @@ export const api = {
   /**
    * Clear votes (start new round)
    */
-  async clearVotes(roomId: string): Promise<void> {
+  async clearVotes(roomId: string, userId?: string): Promise<void> {
     const response = await fetch(`${API_BASE_URL}/api/rooms/${roomId}/clear`, {
       method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ user_id: userId }),
     });
 
     if (!response.ok) {
@@ -159,6 +163,25 @@ export const api = {
     }
   },
 
+  /**
+   * Get every estimation accepted for a task, oldest first
+   */
+  async getEstimationHistory(roomId: string, taskId: string): Promise<EstimationRecord[]> {
+    const response = await fetch(`${API_BASE_URL}/api/rooms/${roomId}/tasks/${taskId}/estimations`);
+    const data = await handleResponse<{ estimations: EstimationRecord[] }>(response);
+    return data.estimations;
+  },
+
+  /**
+   * Get a page of a task's discussion thread, oldest first
+   */
+  async getTaskComments(roomId: string, taskId: string, limit = 50, offset = 0): Promise<TaskCommentPage> {
+    const response = await fetch(
+      `${API_BASE_URL}/api/rooms/${roomId}/tasks/${taskId}/comments?limit=${limit}&offset=${offset}`
+    );
+    return handleResponse<TaskCommentPage>(response);
+  },
+
   /**
    * Health check
    */
@@ -4,10 +4,32 @@ export interface Room {
   name: string;
   voting_system: string;
   auto_reveal: boolean;
+  dimensions?: EstimationDimension[];
+  combination?: DimensionCombination;
+  estimation_mode?: EstimationMode;
+  anonymous_votes?: boolean;
+  vote_change_policy?: VoteChangePolicy;
+  require_acceptance_criteria?: boolean; // votes are refused on tasks without criteria
+  task_filter?: TaskFilter; // restricts which tasks "next" picks
   created_at: string;
   updated_at: string;
 }
 
+// What happens to votes cast after the reveal
+export type VoteChangePolicy = 'tracked' | 'locked';
+
+export type EstimationMode = 'standard' | 'three_point';
+
+// An axis estimated separately in multi-dimensional rooms
+export interface EstimationDimension {
+  key: string;
+  name: string;
+  voting_system?: string;
+  weight?: number;
+}
+
+export type DimensionCombination = 'sum' | 'average' | 'max' | 'weighted';
+
 export interface RoomSettings {
   voting_system: string;
   auto_reveal: boolean;
@@ -18,6 +40,12 @@ export interface NewRoomReq {
   voting_system?: string;
   auto_reveal?: boolean;
   settings?: RoomSettings;
+  dimensions?: EstimationDimension[];
+  combination?: DimensionCombination;
+  estimation_mode?: EstimationMode;
+  anonymous_votes?: boolean;
+  vote_change_policy?: VoteChangePolicy;
+  require_acceptance_criteria?: boolean; // votes are refused on tasks without criteria
 }
 
 export interface NewRoomResp {
@@ -25,6 +53,12 @@ export interface NewRoomResp {
   name: string;
   voting_system: string;
   auto_reveal: boolean;
+  dimensions?: EstimationDimension[];
+  combination?: DimensionCombination;
+  estimation_mode?: EstimationMode;
+  anonymous_votes?: boolean;
+  vote_change_policy?: VoteChangePolicy;
+  require_acceptance_criteria?: boolean; // votes are refused on tasks without criteria
   created_at: string;
 }
 
@@ -36,13 +70,48 @@ export interface User {
   isVoted: boolean;
   hasVoted?: boolean; // Legacy compatibility
   isOnline?: boolean;
+  raisedHand?: HandSignal;
+  handPosition?: number; // 1-based place in the hand queue
+}
+
+// "I have a question before we vote" or "this story is too big, split it"
+export type HandSignal = 'question' | 'split';
+
+export interface RaisedHand {
+  userId: string;
+  signal: HandSignal;
+  position: number;
 }
 
 // Vote related types
+// userId and userName are omitted by the server in anonymous rooms
 export interface Vote {
-  userId: string;
-  userName: string;
+  userId?: string;
+  userName?: string;
   value: string;
+  dimensions?: Record<string, string>; // dimension key -> card
+  threePoint?: ThreePoint<number>;
+  comment?: string; // rationale, only present once votes are revealed
+}
+
+// Optimistic / most likely / pessimistic vote of a three-point room
+export interface ThreePoint<T = string> {
+  optimistic: T;
+  mostLikely: T;
+  pessimistic: T;
+}
+
+// Team's PERT estimate: mean triple, expected value and standard deviation
+export interface PertResult extends ThreePoint<number> {
+  expected: number;
+  stdDev: number;
+}
+
+export interface DimensionResult {
+  key: string;
+  name: string;
+  average?: number | null;
+  suggested?: string;
 }
 
 // Room state types
@@ -53,7 +122,21 @@ export interface RoomState {
   votes: Vote[];
   isRevealed: boolean;
   average?: number | null;
+  suggested?: string;
+  dimensionResults?: DimensionResult[];
+  pert?: PertResult;
+  changes?: VoteChange[];
   taskDescription?: string;
+  facilitatorId?: string; // may undo anyone's change
+  lastChange?: Change | null; // latest change undo would revert
+}
+
+// Destructive changes that can be undone by their author or the facilitator
+export type UndoAction = 'delete_task' | 'reorder_tasks' | 'move_task' | 'accept_estimate' | 'clear';
+
+export interface Change {
+  action: UndoAction;
+  userId: string; // who made the change
 }
 
 // Task types
@@ -64,9 +147,138 @@ export interface Task {
   description?: string;
   trackerLink?: string;
   estimation?: string;
+  status: TaskStatus;
   position: number;
+  version: number; // send it back with update_task so concurrent edits are detected
+  estimationOverridden?: boolean;
+  overrideReason?: string;
+  previousEstimation?: string;
+  estimationBreakdown?: Record<string, string>;
+  pert?: PertResult;
+  notes?: TaskNotes;
+  notesHistory?: TaskNotes[]; // latest earlier revisions (up to 10), oldest first
+  acceptanceCriteria?: AcceptanceCriterion[];
+  type: TaskType;
+  labels?: string[];
+  parentId?: string; // epic this task is a subtask of
+  rollup?: EstimateRollup; // only on epics
+  dependsOn?: string[]; // IDs of the tasks blocking this one
 }
 
+// An epic's estimate summed from the leaf subtasks below it
+export interface EstimateRollup {
+  points: string;
+  estimated: number;
+  subtasks: number;
+}
+
+// A task with its subtasks nested below it
+export interface TaskNode extends Task {
+  subtasks?: TaskNode[];
+}
+
+export type TaskType = 'story' | 'bug' | 'spike' | 'chore';
+
+export const TASK_TYPES: TaskType[] = ['story', 'bug', 'spike', 'chore'];
+
+export const MAX_TASK_LABELS = 10;
+
+// Narrows the backlog; empty lists match everything and any listed label is enough
+export interface TaskFilter {
+  labels?: string[];
+  types?: TaskType[];
+  statuses?: TaskStatus[];
+}
+
+// One item of a task's acceptance criteria checklist
+export interface AcceptanceCriterion {
+  id: string;
+  text: string;
+  done: boolean;
+}
+
+export const MAX_CRITERION_LENGTH = 500;
+
+// Sent with add_criterion, update_criterion and delete_criterion
+export interface CriterionPayload {
+  taskId?: string; // defaults to the active task
+  criterionId?: string;
+  text?: string;
+  done?: boolean;
+}
+
+export interface ReorderCriteriaPayload {
+  taskId?: string;
+  criterionIds: string[];
+}
+
+// One revision of a task's decision log: the assumptions behind the estimate
+export interface TaskNotes {
+  items: string[];
+  author?: string;
+  updatedAt: string;
+}
+
+export const MAX_TASK_NOTES = 20;
+export const MAX_TASK_NOTE_LENGTH = 200;
+
+export interface UpdateNotesPayload {
+  taskId?: string; // defaults to the active task
+  notes: string[];
+}
+
+export interface EstimationRecord {
+  id: string;
+  taskId: string;
+  value: string;
+  source: 'vote' | 'override';
+  reason?: string;
+  participants: string[];
+  notes?: string[]; // decision log the estimate was accepted with
+  comments?: VoteComment[];
+  changes?: VoteChange[];
+  createdAt: string;
+}
+
+// A vote changed after the reveal; voter is omitted in anonymous rooms
+export interface VoteChange {
+  voter?: string;
+  from?: string; // missing when the user first voted after the reveal
+  to: string;
+}
+
+// Comment kept in the estimation history; author is omitted for anonymous rounds
+export interface VoteComment {
+  author?: string;
+  value: string;
+  comment: string;
+}
+
+export const MAX_VOTE_COMMENT_LENGTH = 280;
+
+// One message of a task's discussion thread
+export interface TaskComment {
+  id: string;
+  taskId: string;
+  authorId: string;
+  authorName: string;
+  body: string;
+  edited?: boolean;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface TaskCommentPage {
+  comments: TaskComment[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export const MAX_TASK_COMMENT_LENGTH = 2000;
+
+export type TaskStatus = 'pending' | 'in_discussion' | 'estimated' | 'skipped' | 'deferred';
+
 export interface CreateTaskReq {
   headline: string;
   description?: string;
@@ -80,7 +292,42 @@ export interface UpdateTaskReq {
 }
 
 export interface TaskListSyncPayload {
-  tasks: Task[];
+  tasks: Task[]; // flat, in backlog order: every epic directly followed by its subtasks
+  tree?: TaskNode[];
+}
+
+// Sent with add_dependency and remove_dependency
+export interface DependencyPayload {
+  taskId?: string; // defaults to the active task
+  dependsOnId: string;
+}
+
+// Answer to propose_task_order, only sent to the requester; apply it with reorder_tasks
+export interface TaskOrderProposedPayload {
+  taskIds: string[];
+}
+
+// Sent with update_task; omitted fields stay unchanged
+export interface UpdateTaskPayload {
+  taskId: string;
+  version: number; // version the edit was made on, rejected with a CONFLICT error when outdated
+  headline?: string;
+  description?: string;
+  trackerLink?: string;
+  type?: TaskType;
+  labels?: string[];
+}
+
+// Places a task right before or right after another one; set exactly one of the two
+export interface MoveTaskPayload {
+  taskId: string;
+  beforeId?: string;
+  afterId?: string;
+}
+
+export interface SetTaskParentPayload {
+  taskId: string;
+  parentId: string; // empty moves the task to the top level
 }
 
 // WebSocket event types
@@ -94,7 +341,33 @@ export type ClientEventType =
   | 'update_task'
   | 'delete_task'
   | 'reorder_tasks'
-  | 'set_active_task';
+  | 'move_task'
+  | 'set_active_task'
+  | 'resync'
+  | 'skip_task'
+  | 'defer_task'
+  | 'reopen_task'
+  | 'accept_estimate'
+  | 'next_task'
+  | 'previous_task'
+  | 'jump_to_task'
+  | 'reestimate_task'
+  | 'raise_hand'
+  | 'lower_hand'
+  | 'add_comment'
+  | 'edit_comment'
+  | 'delete_comment'
+  | 'update_notes'
+  | 'add_criterion'
+  | 'update_criterion'
+  | 'delete_criterion'
+  | 'reorder_criteria'
+  | 'set_task_filter'
+  | 'set_task_parent'
+  | 'add_dependency'
+  | 'remove_dependency'
+  | 'propose_task_order'
+  | 'undo';
 
 export type ServerEventType =
   | 'room_state'
@@ -110,7 +383,16 @@ export type ServerEventType =
   | 'task_deleted'
   | 'tasks_reordered'
   | 'active_task_set'
-  | 'task_list_sync';
+  | 'task_list_sync'
+  | 'round_reset'
+  | 'task_description_set'
+  | 'hands_updated'
+  | 'comment_added'
+  | 'comment_updated'
+  | 'comment_deleted'
+  | 'task_filter_set'
+  | 'task_order_proposed'
+  | 'undo_updated';
 
 export interface ClientEvent<T = any> {
   type: ClientEventType;
@@ -124,7 +406,10 @@ export interface ServerEvent<T = any> {
 
 // Client event payloads
 export interface VotePayload {
-  value: string;
+  value?: string;
+  values?: Record<string, string>; // one card per dimension in multi-dimensional rooms
+  threePoint?: ThreePoint;
+  comment?: string;
 }
 
 export interface UpdateNicknamePayload {
@@ -143,17 +428,26 @@ export interface RoomStatePayload {
   votes: Vote[];
   isRevealed: boolean;
   average?: number | null;
+  facilitatorId?: string;
+  lastChange?: Change | null;
+}
+
+// Sent after every undoable change and every undo; lastChange is null when nothing is left to undo
+export interface UndoUpdatedPayload {
+  lastChange: Change | null;
+  undone?: Change;
 }
 
 export interface UserJoinedPayload {
   userId: string;
-  name: string;
+  nickname: string;
   isVoted: boolean;
   isOnline: boolean;
 }
 
 export interface UserLeftPayload {
   userId: string;
+  facilitatorId?: string; // the facilitator once the user has left
 }
 
 export interface VoteSubmittedPayload {
@@ -161,21 +455,81 @@ export interface VoteSubmittedPayload {
   hasVoted: boolean;
 }
 
+export interface HandsUpdatedPayload {
+  hands: RaisedHand[];
+}
+
+export interface RaiseHandPayload {
+  signal: HandSignal;
+}
+
+export interface LowerHandPayload {
+  userId?: string; // someone else's hand, when the facilitator calls on them
+}
+
+export interface AddCommentPayload {
+  taskId?: string; // defaults to the active task
+  body: string;
+}
+
+export interface EditCommentPayload {
+  commentId: string;
+  body: string;
+}
+
+export interface DeleteCommentPayload {
+  commentId: string;
+}
+
+export interface CommentDeletedPayload {
+  commentId: string;
+  taskId: string;
+}
+
 export interface VotesRevealedPayload {
   votes: Vote[];
   average?: number | null;
+  suggested?: string;
+  dimensions?: DimensionResult[];
+  pert?: PertResult;
+  anonymous?: boolean;
+  changes?: VoteChange[];
+}
+
+export interface AcceptEstimatePayload {
+  value: string;
+  reason?: string;
+  breakdown?: Record<string, string>;
 }
 
 export interface UserUpdatedPayload {
   userId: string;
-  name: string;
+  nickname: string;
+}
+
+export interface RoundResetPayload {
+  activeTaskId?: string;
+  activeTask?: Task;
+  estimatedTask?: Task;
+}
+
+export interface ActiveTaskSetPayload {
+  taskId: string;
+  task?: Task;
 }
 
 export interface ErrorPayload {
   message: string;
   code?: string;
+  task?: Task; // current task, sent with CONFLICT when an edit was made on an outdated version
 }
 
+export const DEFAULT_DIMENSIONS: EstimationDimension[] = [
+  { key: 'complexity', name: 'Complexity' },
+  { key: 'effort', name: 'Effort' },
+  { key: 'uncertainty', name: 'Uncertainty' },
+];
+
 // Valid vote values for DBS Fibonacci
 export const VALID_VOTES = ['0', '0.5', '1', '2', '3', '5', '8', '13', '20', '40', '100', '?'] as const;
 export type ValidVote = typeof VALID_VOTES[number];